@@ -4,15 +4,15 @@ import (
 	"context"
 	"fmt"
 	"math/big"
-	"strings"
 	"time"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/convert"
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
+	"github.com/ZeraVision/zera-go-sdk/parts"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -21,21 +21,52 @@ import (
 type AllowanceDetails struct {
 	Authorize          bool // true for approve, false for revoke
 	WalletAddr         string
-	CurrencyEquivalent *float64 // actual currency equivalent value, it 1.234, scaled within function (one of this OR amount present)
-	Amount             *big.Int // parts of a token (one of this OR currency equivalent present)
-	PeriodMonths       *uint32  // Number of months (one of this OR seconds present)
-	PeriodSeconds      *uint32  // Number of seconds (one of this OR months present)
-	StartTime          int64    // unix of starttime
+	CurrencyEquivalent *convert.Amount // actual currency equivalent value, ie convert.MustParseDecimal("1.234"), scaled within function (one of this OR amount present)
+	Amount             *big.Int        // parts of a token, see CreateAllowanceTxnWithAmount for full coins (one of this OR currency equivalent present)
+	PeriodMonths       *uint32         // Number of months (one of this OR seconds present)
+	PeriodSeconds      *uint32         // Number of seconds (one of this OR months present)
+	StartTime          int64           // unix of starttime
 }
 
 func CreateAllowanceTxn(nonceInfo nonce.NonceInfo, symbol string, details AllowanceDetails, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.AllowanceTXN, error) {
+	return CreateAllowanceTxnWithContext(context.Background(), nonceInfo, symbol, details, publicKeyBase58, privateKeyBase58, feeID, feeAmountParts)
+}
+
+// CreateAllowanceTxnWithContext is CreateAllowanceTxn with a context that is passed through to the nonce lookup.
+func CreateAllowanceTxnWithContext(ctx context.Context, nonceInfo nonce.NonceInfo, symbol string, details AllowanceDetails, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.AllowanceTXN, error) {
+	return CreateAllowanceTxnWithSigner(ctx, nonceInfo, symbol, details, helper.NewPrivateKeySigner(publicKeyBase58, privateKeyBase58), feeID, feeAmountParts)
+}
+
+// CreateAllowanceTxnWithAmount is CreateAllowanceTxnWithSigner allowing amount in full coins of the contract partsInfo
+// looks up, ie convert.ParseAmount("1.5", partsPerCoin), instead of details.Amount. An amount at another denomination
+// (ie convert.ParseDecimal("1.5")) is an error rather than allowed as its parts.
+func CreateAllowanceTxnWithAmount(ctx context.Context, nonceInfo nonce.NonceInfo, partsInfo parts.PartsInfo, details AllowanceDetails, amount convert.Amount, signer helper.Signer, feeID string, feeAmountParts string) (*pb.AllowanceTXN, error) {
+	if details.Amount != nil {
+		return nil, fmt.Errorf("only one of details.Amount or amount should be provided")
+	}
+
+	partsPerCoin, err := parts.GetPartsWithContext(ctx, partsInfo)
+	if err != nil {
+		return nil, fmt.Errorf("could not get parts: %v", err)
+	}
+
+	details.Amount, err = amount.PartsIn(partsPerCoin)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount for %s: %v", partsInfo.Symbol, err)
+	}
+
+	return CreateAllowanceTxnWithSigner(ctx, nonceInfo, partsInfo.Symbol, details, signer, feeID, feeAmountParts)
+}
+
+// CreateAllowanceTxnWithSigner is CreateAllowanceTxn with the transaction signed by signer instead of a base58 private key.
+func CreateAllowanceTxnWithSigner(ctx context.Context, nonceInfo nonce.NonceInfo, symbol string, details AllowanceDetails, signer helper.Signer, feeID string, feeAmountParts string) (*pb.AllowanceTXN, error) {
 	// Step 1: Decode public key
-	_, _, pubKeyBytes, err := transcode.Base58DecodePublicKey(publicKeyBase58)
+	txnPublicKey, err := helper.TxnPublicKey(signer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode public key: %v", err)
 	}
 
-	nonce, err := nonce.GetNonce(nonceInfo, 5)
+	nonce, err := nonce.GetNonceWithContext(ctx, nonceInfo, nonce.DefaultConcurrency)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nonce: %v", err)
@@ -56,8 +87,13 @@ func CreateAllowanceTxn(nonceInfo nonce.NonceInfo, symbol string, details Allowa
 
 	// Take curEquivAmount to 1e18
 	if details.CurrencyEquivalent != nil {
-		currencyEquivScaled := new(big.Float).Mul(big.NewFloat(*details.CurrencyEquivalent), big.NewFloat(1e18)).Text('f', 0)
-		currencyEquivScaledStr = &currencyEquivScaled
+		currencyEquivScaled, err := details.CurrencyEquivalent.PartsAt(big.NewInt(1e18))
+		if err != nil {
+			return nil, fmt.Errorf("invalid currency equivalent: %v", err)
+		}
+
+		currencyEquivScaledText := currencyEquivScaled.String()
+		currencyEquivScaledStr = &currencyEquivScaledText
 	}
 
 	var amountParts *string
@@ -83,9 +119,7 @@ func CreateAllowanceTxn(nonceInfo nonce.NonceInfo, symbol string, details Allowa
 
 	// Step 2: Create BaseTXN
 	base := &pb.BaseTXN{
-		PublicKey: &pb.PublicKey{
-			Single: pubKeyBytes,
-		},
+		PublicKey: txnPublicKey,
 		FeeId:     feeID,
 		FeeAmount: feeAmountParts,
 		Timestamp: timestamppb.New(time.Now().UTC()),
@@ -121,14 +155,8 @@ func CreateAllowanceTxn(nonceInfo nonce.NonceInfo, symbol string, details Allowa
 		return nil, fmt.Errorf("failed to serialize allowance transaction: %v", err)
 	}
 
-	keyType, err := helper.DetermineKeyType(publicKeyBase58)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine key type: %v", err)
-	}
-
 	// Step 5: Sign the transaction
-	signature, err := helper.Sign(privateKeyBase58, byteDataNoSig, keyType)
+	signature, err := helper.SignWith(signer, byteDataNoSig)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign allowance transaction: %v", err)
@@ -151,20 +179,26 @@ func CreateAllowanceTxn(nonceInfo nonce.NonceInfo, symbol string, details Allowa
 }
 
 func SendAllowanceTxn(grpcAddr string, txn *pb.AllowanceTXN) (*emptypb.Empty, error) {
-	if !strings.Contains(grpcAddr, ":") {
-		grpcAddr += ":50052"
-	}
+	return SendAllowanceTxnWithContext(context.Background(), grpcAddr, txn)
+}
+
+// SendAllowanceTxnWithContext is SendAllowanceTxn with a context that is passed through to the gRPC call.
+func SendAllowanceTxnWithContext(ctx context.Context, grpcAddr string, txn *pb.AllowanceTXN) (*emptypb.Empty, error) {
+	return SendAllowanceTxnWithSecurity(ctx, grpcAddr, txn, nil)
+}
 
-	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// SendAllowanceTxnWithSecurity is SendAllowanceTxnWithContext with the validator dialed using security (plaintext if nil).
+func SendAllowanceTxnWithSecurity(ctx context.Context, grpcAddr string, txn *pb.AllowanceTXN, security *helper.TransportSecurity) (*emptypb.Empty, error) {
+	conn, err := helper.NewConnection(grpcAddr, "50052", security)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 
 	client := pb.NewTXNServiceClient(conn)
-	response, err := client.Allowance(context.Background(), txn)
+	response, err := client.Allowance(ctx, txn)
 	if err != nil {
-		return nil, fmt.Errorf("allowance transaction failed: %v", err)
+		return nil, fmt.Errorf("allowance transaction failed: %w", zeraerr.FromGRPC(err))
 	}
 
 	return response, nil
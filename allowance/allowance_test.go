@@ -8,6 +8,7 @@ import (
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
 	"github.com/ZeraVision/zera-go-sdk/allowance"
+	"github.com/ZeraVision/zera-go-sdk/convert"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
 	"github.com/ZeraVision/zera-go-sdk/testvars"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
@@ -52,10 +53,10 @@ func TestAllowance(t *testing.T) {
 	baseFeeSymbol := "$ZRA+0000" // some ACE authorized token
 	baseFeeParts := "1000000000" // a number of parts sufficient to cover the fee with your symbol
 
-	var currencyEquivalent *float64
+	var currencyEquivalent *convert.Amount
 	var amount *big.Int
 	if true {
-		currencyEquivalentV := 1.234 // $1.234
+		currencyEquivalentV := convert.MustParseDecimal("1.234") // $1.234
 		currencyEquivalent = &currencyEquivalentV
 	} else {
 		amount = big.NewInt(100_000_000_000) // 100 billion parts
@@ -0,0 +1,213 @@
+// zera-signer is the reference remote signing daemon. It holds keys from an encrypted keystore directory
+// and signs transaction payloads for remotesigner.Signer clients, so private keys never leave its process.
+//
+//	zera-signer import -keystore ./keys -name treasury -public r_A_c_... [-argon2id]   (private key read from stdin)
+//	zera-signer serve  -keystore ./keys -listen 127.0.0.1:7400 [-tls-cert cert.pem -tls-key key.pem [-client-ca ca.pem]]
+//
+// The keystore passphrase is read from ZERA_SIGNER_PASSPHRASE (or stdin, without echo on a terminal), the client
+// token from ZERA_SIGNER_TOKEN.
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/keystore"
+	"github.com/ZeraVision/zera-go-sdk/remotesigner"
+	"golang.org/x/term"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "serve":
+		err = serve(os.Args[2:])
+	case "import":
+		err = importKey(os.Args[2:])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: zera-signer serve|import [flags]")
+	os.Exit(2)
+}
+
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	listen := flags.String("listen", "127.0.0.1:7400", "address to listen on")
+	dir := flags.String("keystore", "", "directory of encrypted key files (required)")
+	certFile := flags.String("tls-cert", "", "TLS certificate (PEM)")
+	keyFile := flags.String("tls-key", "", "TLS private key (PEM)")
+	clientCA := flags.String("client-ca", "", "CA for client certificates, enables mTLS")
+	flags.Parse(args)
+
+	if *dir == "" {
+		return errors.New("-keystore is required")
+	}
+
+	if (*certFile == "") != (*keyFile == "") {
+		return errors.New("-tls-cert and -tls-key must be set together")
+	}
+
+	// mTLS without TLS would serve plain http and silently skip client authentication
+	if *clientCA != "" && *certFile == "" {
+		return errors.New("-client-ca requires -tls-cert and -tls-key")
+	}
+
+	passphrase, err := readSecret("ZERA_SIGNER_PASSPHRASE", "keystore passphrase:")
+	if err != nil {
+		return err
+	}
+
+	keys, err := keystore.LoadDir(*dir, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		return fmt.Errorf("no keys found in %s", *dir)
+	}
+
+	token := os.Getenv("ZERA_SIGNER_TOKEN")
+	if token == "" && !isLoopback(*listen) {
+		return errors.New("ZERA_SIGNER_TOKEN is required when listening on a non loopback address")
+	}
+
+	server := remotesigner.NewServer(remotesigner.ServerConfig{
+		Token:  token,
+		Logger: log.New(os.Stderr, "audit ", log.LstdFlags|log.LUTC),
+	})
+
+	for _, key := range keys {
+		server.Add(key.Name, key.Signer())
+		log.Printf("loaded key %s (%s)", key.Name, key.PublicKey)
+	}
+
+	httpServer := &http.Server{
+		Addr:              *listen,
+		Handler:           server,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if *certFile == "" {
+		log.Printf("listening on http://%s", *listen)
+		return httpServer.ListenAndServe()
+	}
+
+	if *clientCA != "" {
+		pool, err := helper.LoadCertPool(*clientCA)
+		if err != nil {
+			return err
+		}
+		httpServer.TLSConfig = &tls.Config{ClientCAs: pool, ClientAuth: tls.RequireAndVerifyClientCert, MinVersion: tls.VersionTLS12}
+	}
+
+	log.Printf("listening on https://%s", *listen)
+	return httpServer.ListenAndServeTLS(*certFile, *keyFile)
+}
+
+func importKey(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	dir := flags.String("keystore", "", "directory of encrypted key files (required)")
+	name := flags.String("name", "", "key name (required)")
+	publicKey := flags.String("public", "", "base58 public key, ie r_A_c_... (required)")
+	argon := flags.Bool("argon2id", false, "encrypt with Argon2id and XChaCha20-Poly1305 instead of scrypt and AES-256-GCM")
+	flags.Parse(args)
+
+	if *dir == "" || *name == "" || *publicKey == "" {
+		return errors.New("-keystore, -name and -public are required")
+	}
+
+	parsed, err := helper.ParsePublicKey(*publicKey)
+	if err != nil {
+		return err
+	}
+
+	if parsed.Special() {
+		return fmt.Errorf("%s is not signed with a private key", *publicKey)
+	}
+
+	passphrase, err := readSecret("ZERA_SIGNER_PASSPHRASE", "keystore passphrase:")
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := readSecret("", "private key (base58):")
+	if err != nil {
+		return err
+	}
+
+	keyring, err := keystore.OpenKeyring(*dir)
+	if err != nil {
+		return err
+	}
+
+	options := keystore.Options{}
+	if *argon {
+		options.KDF = keystore.KDFArgon2id
+		options.Cipher = keystore.CipherXChaCha20Poly1305
+	}
+
+	// the key pair is checked before it is written
+	return keyring.Import(&keystore.Key{
+		Name:       *name,
+		PublicKey:  *publicKey,
+		PrivateKey: privateKey,
+		KeyType:    parsed.KeyType,
+	}, passphrase, options)
+}
+
+var stdin = bufio.NewReader(os.Stdin)
+
+// readSecret reads a secret from env, or a line from stdin (without echo if stdin is a terminal)
+func readSecret(env, prompt string) (string, error) {
+	if env != "" {
+		if secret := os.Getenv(env); secret != "" {
+			return secret, nil
+		}
+	}
+
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, prompt+" ")
+		secret, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(secret)), nil
+	}
+
+	fmt.Fprintln(os.Stderr, prompt)
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func isLoopback(addr string) bool {
+	host := addr
+	if i := strings.LastIndex(addr, ":"); i != -1 {
+		host = addr[:i]
+	}
+	return host == "127.0.0.1" || host == "localhost" || host == "[::1]"
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ZeraVision/zera-go-sdk/inspect"
+	"google.golang.org/protobuf/proto"
+)
+
+func inspectCommand(args []string) error {
+	flags := flag.NewFlagSet("inspect", flag.ExitOnError)
+	txnType := flags.String("type", "", "transaction type, ie CoinTXN (detected if empty)")
+	file := flags.String("file", "", "file holding the hex or base64 transaction (the argument or stdin if empty)")
+	raw := flags.Bool("raw", false, "-file holds the serialized protobuf instead of hex or base64")
+	text := flags.Bool("text", false, "print a text report instead of JSON")
+	var partsFlags stringList
+	flags.Var(&partsFlags, "parts", "parts per coin of a contract as <contract id>=<parts>, repeatable")
+	flags.Parse(args)
+
+	partsPerCoin := make(map[string]*big.Int)
+	for _, value := range partsFlags {
+		contractID, count, ok := strings.Cut(value, "=")
+		parts, valid := new(big.Int).SetString(count, 10)
+		if !ok || !valid || parts.Sign() <= 0 {
+			return fmt.Errorf("invalid parts %q, expected <contract id>=<parts>", value)
+		}
+		partsPerCoin[contractID] = parts
+	}
+
+	var data []byte
+	var err error
+	switch {
+	case *file != "":
+		data, err = os.ReadFile(*file)
+	case flags.NArg() > 0:
+		data = []byte(flags.Arg(0))
+	default:
+		data, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *raw && *file == "" {
+		return errors.New("-raw requires -file")
+	}
+
+	var txn proto.Message
+	if *raw {
+		txn, err = inspect.Decode(data, *txnType)
+	} else {
+		txn, err = inspect.DecodeString(string(data), *txnType)
+	}
+	if err != nil {
+		return err
+	}
+
+	report, err := inspect.Inspect(txn, partsPerCoin)
+	if err != nil {
+		return err
+	}
+
+	if *text {
+		fmt.Fprint(stdout, report)
+		return nil
+	}
+	return printJSON(report)
+}
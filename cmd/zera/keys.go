@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/keystore"
+	"github.com/ZeraVision/zera-go-sdk/wallet"
+)
+
+func mnemonicCommand(args []string) error {
+	flags := flag.NewFlagSet("mnemonic", flag.ExitOnError)
+	strength := flags.Int("strength", 256, "entropy in bits (128, 160, 192, 224 or 256)")
+	flags.Parse(args)
+
+	mnemonic, err := wallet.GenerateMnemonic(*strength)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(map[string]string{"mnemonic": mnemonic})
+}
+
+type keygenOutput struct {
+	Name       string `json:"name,omitempty"`
+	Path       string `json:"path,omitempty"`
+	PublicKey  string `json:"publicKey"`
+	Address    string `json:"address"`
+	PrivateKey string `json:"privateKey,omitempty"` // only printed when the key is not stored in a keystore
+	Keystore   string `json:"keystore,omitempty"`
+}
+
+func keygenCommand(args []string) error {
+	flags := flag.NewFlagSet("keygen", flag.ExitOnError)
+	keyType := flags.String("key-type", "ed25519", "ed25519 or ed448")
+	hash := flags.String("hash", "c", "address hash token (c blake3, a sha3-256, b sha3-512)")
+	path := flags.String("path", "", "hardened ed25519 derivation path m/44'/<coin type>'/<account>'/<index>' (empty for the single key of the mnemonic)")
+	passphrase := flags.Bool("bip39-passphrase", false, "read a BIP39 passphrase from ZERA_BIP39_PASSPHRASE or stdin (requires -path)")
+	restricted := flags.Bool("restricted", false, "prefix the public key with r_")
+	dir := flags.String("keystore", "", "keyring directory to store the key in (the private key is printed otherwise)")
+	name := flags.String("name", "", "key name in the keystore (required with -keystore)")
+	argon := flags.Bool("argon2id", false, "encrypt with Argon2id and XChaCha20-Poly1305 instead of scrypt and AES-256-GCM")
+	flags.Parse(args)
+
+	config := wallet.DerivationConfig{Path: *path}
+
+	switch *keyType {
+	case "ed25519":
+		config.KeyType = helper.ED25519
+	case "ed448":
+		config.KeyType = helper.ED448
+	default:
+		return fmt.Errorf("unknown key type %s", *keyType)
+	}
+
+	hashType, err := helper.ParseHashType(*hash)
+	if err != nil || hashType == helper.RESTRICTED {
+		return fmt.Errorf("unknown hash token %s", *hash)
+	}
+	config.HashAlg = hashType
+
+	// the mnemonic is never a flag, flags end up in shell history and process listings
+	if config.Mnemonic, err = readSecret("ZERA_MNEMONIC", "mnemonic:"); err != nil {
+		return err
+	}
+
+	if *passphrase {
+		if config.Passphrase, err = readSecret("ZERA_BIP39_PASSPHRASE", "BIP39 passphrase:"); err != nil {
+			return err
+		}
+	}
+
+	derived, err := wallet.DeriveKey(config)
+	if err != nil {
+		return err
+	}
+
+	publicKey := derived.PublicKey
+	if *restricted {
+		publicKey = "r_" + publicKey
+	}
+
+	output := keygenOutput{Name: *name, Path: derived.Path, PublicKey: publicKey, Address: derived.Address}
+
+	if *dir == "" {
+		output.PrivateKey = derived.PrivateKey
+		return printJSON(output)
+	}
+
+	if *name == "" {
+		return errors.New("-name is required with -keystore")
+	}
+
+	keyring, err := keystore.OpenKeyring(*dir)
+	if err != nil {
+		return err
+	}
+
+	storePassphrase, err := readSecret("ZERA_PASSPHRASE", "keystore passphrase:")
+	if err != nil {
+		return err
+	}
+
+	options := keystore.Options{}
+	if *argon {
+		options.KDF = keystore.KDFArgon2id
+		options.Cipher = keystore.CipherXChaCha20Poly1305
+	}
+
+	key := &keystore.Key{Name: *name, PublicKey: publicKey, PrivateKey: derived.PrivateKey, KeyType: config.KeyType}
+	if err := keyring.Import(key, storePassphrase, options); err != nil {
+		return err
+	}
+
+	output.Keystore = *dir
+	return printJSON(output)
+}
+
+func addressCommand(args []string) error {
+	flags := flag.NewFlagSet("address", flag.ExitOnError)
+	publicKey := flags.String("public", "", "base58 public key, ie A_c_... (required)")
+	flags.Parse(args)
+
+	if *publicKey == "" {
+		return errors.New("-public is required")
+	}
+
+	parsed, err := helper.ParsePublicKey(*publicKey)
+	if err != nil {
+		return err
+	}
+
+	_, address, err := wallet.GetAddressFromKey(parsed.Proto())
+	if err != nil {
+		return err
+	}
+
+	return printJSON(map[string]string{"publicKey": *publicKey, "address": address})
+}
@@ -0,0 +1,328 @@
+// zera is a command line wallet built on the SDK, for operators that need to create keys and send transactions
+// without writing Go.
+//
+//	zera mnemonic [-strength 256]
+//	zera keygen   [-key-type ed25519|ed448] [-hash c|b|a] [-path m/44'/<coin>'/0'/0'] [-keystore ./keys -name treasury]
+//	zera address  -public A_c_...
+//
+//	zera transfer   -symbol '$ZRA+0000' -to <address>=<amount> [-to ...] [-parts 1000000000]
+//	zera mint       -symbol '$TEST+0000' -amount 1.23 -recipient <address> [-parts 100]
+//	zera contract   create|update -file contract.yaml   (see contract.TokenSpec)
+//	zera governance propose -symbol '$ZRA+0000' -title ... -synopsis ... -body ... [-option yes -option no] [-start ... -end ...]
+//	zera governance vote    -symbol '$ZRA+0000' -proposal <hex id> (-support=true|false | -option-index n)
+//	zera allowance  -symbol '$ZRA+0000' -wallet <address> (-amount 1.23 [-parts 1000000000] | -currency-equivalent 1.23) (-months n | -seconds n) [-revoke]
+//	zera compliance -symbol '$TEST+0000' -wallet <address> -level n [-revoke] [-expiry 2026-01-01T00:00:00Z]
+//
+//	zera inspect [-type CoinTXN] [-parts '$TEST+0000=100'] [-text] (<hex or base64> | -file txn.hex [-raw])
+//
+// Every transaction command signs with either a keystore key (-keystore ./keys -key treasury, passphrase from
+// ZERA_PASSPHRASE or stdin) or a remote signing daemon (-signer https://... -key treasury, token from ZERA_SIGNER_TOKEN),
+// and submits to -validator (default ZERA_VALIDATOR). With -dry-run the signed transaction is printed without sending.
+// Output is JSON on stdout, transactions in the txnjson encoding.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/keystore"
+	"github.com/ZeraVision/zera-go-sdk/nonce"
+	"github.com/ZeraVision/zera-go-sdk/remotesigner"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/ZeraVision/zera-go-sdk/txnjson"
+	"github.com/ZeraVision/zera-go-sdk/wallet"
+	"github.com/ZeraVision/zera-go-sdk/zera"
+	"golang.org/x/term"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultFeeID     = "$ZRA+0000"
+	defaultFeeAmount = "1000000000"
+	defaultTimeout   = 30 * time.Second
+)
+
+var commands = map[string]func(args []string) error{
+	"mnemonic":   mnemonicCommand,
+	"keygen":     keygenCommand,
+	"address":    addressCommand,
+	"transfer":   transferCommand,
+	"mint":       mintCommand,
+	"contract":   contractCommand,
+	"governance": governanceCommand,
+	"allowance":  allowanceCommand,
+	"compliance": complianceCommand,
+	"inspect":    inspectCommand,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+	}
+
+	if err := command(os.Args[2:]); err != nil {
+		printJSON(map[string]string{"error": err.Error()})
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: zera mnemonic|keygen|address|transfer|mint|contract|governance|allowance|compliance|inspect [flags]")
+	os.Exit(2)
+}
+
+// txnFlags are the flags shared by every transaction command
+type txnFlags struct {
+	validator *string
+	keystore  *string
+	signerURL *string
+	key       *string
+	feeID     *string
+	feeAmount *string
+	nonce     *optionalUint64
+	dryRun    *bool
+	timeout   *time.Duration
+}
+
+func addTxnFlags(flags *flag.FlagSet) *txnFlags {
+	nonceFlag := &optionalUint64{}
+	flags.Var(nonceFlag, "nonce", "nonce to use instead of looking it up on the validator (1 or more)")
+
+	return &txnFlags{
+		nonce:     nonceFlag,
+		validator: flags.String("validator", os.Getenv("ZERA_VALIDATOR"), "validator host (default $ZERA_VALIDATOR)"),
+		keystore:  flags.String("keystore", "", "keyring directory holding -key"),
+		signerURL: flags.String("signer", "", "remote signing daemon url holding -key (instead of -keystore)"),
+		key:       flags.String("key", "", "name of the signing key (required)"),
+		feeID:     flags.String("fee-id", defaultFeeID, "contract id the base fee is paid in"),
+		feeAmount: flags.String("fee-amount", defaultFeeAmount, "maximum base fee in parts"),
+		dryRun:    flags.Bool("dry-run", false, "print the signed transaction without sending it"),
+		timeout:   flags.Duration("timeout", defaultTimeout, "timeout for network requests"),
+	}
+}
+
+// signer resolves the signing key from the keyring or the remote signer
+func (f *txnFlags) signer(ctx context.Context) (helper.Signer, error) {
+	if *f.key == "" {
+		return nil, errors.New("-key is required")
+	}
+
+	switch {
+	case *f.keystore != "" && *f.signerURL != "":
+		return nil, errors.New("only one of -keystore and -signer may be set")
+
+	case *f.keystore != "":
+		keyring, err := keystore.OpenKeyring(*f.keystore)
+		if err != nil {
+			return nil, err
+		}
+
+		passphrase, err := readSecret("ZERA_PASSPHRASE", "keystore passphrase:")
+		if err != nil {
+			return nil, err
+		}
+
+		return keyring.Unlock(*f.key, passphrase)
+
+	case *f.signerURL != "":
+		return remotesigner.NewSigner(ctx, remotesigner.ClientConfig{URL: *f.signerURL, Token: os.Getenv("ZERA_SIGNER_TOKEN")}, *f.key)
+
+	default:
+		return nil, errors.New("one of -keystore or -signer is required")
+	}
+}
+
+// nonceInfo looks up the nonce of the signer's wallet on the validator, unless -nonce is set
+func (f *txnFlags) nonceInfo(signer helper.Signer) (nonce.NonceInfo, error) {
+	if f.nonce.set {
+		// nonces start at 1, an override of 0 would be sent as no nonce
+		if f.nonce.value == 0 {
+			return nonce.NonceInfo{}, errors.New("-nonce must be at least 1")
+		}
+		return nonce.NonceInfo{Override: []uint64{f.nonce.value}}, nil
+	}
+
+	if *f.validator == "" {
+		return nonce.NonceInfo{}, errors.New("-validator (or -nonce) is required")
+	}
+
+	address, err := signerAddress(signer)
+	if err != nil {
+		return nonce.NonceInfo{}, err
+	}
+
+	nonceReq, err := nonce.MakeNonceRequest(address)
+	if err != nil {
+		return nonce.NonceInfo{}, err
+	}
+
+	return nonce.NonceInfo{NonceReqs: []*pb.NonceRequest{nonceReq}, ValidatorAddr: *f.validator}, nil
+}
+
+func signerAddress(signer helper.Signer) (string, error) {
+	txnPublicKey, err := helper.TxnPublicKey(signer)
+	if err != nil {
+		return "", err
+	}
+
+	_, address, err := wallet.GetAddressFromKey(txnPublicKey)
+	return address, err
+}
+
+// txnOutput is printed for every transaction command
+type txnOutput struct {
+	Type        string          `json:"type"`
+	Hash        string          `json:"hash"`
+	Submitted   bool            `json:"submitted"`
+	Validator   string          `json:"validator,omitempty"`
+	Transaction json.RawMessage `json:"transaction"`
+}
+
+// finish prints txn and submits it unless -dry-run is set
+func (f *txnFlags) finish(ctx context.Context, txn proto.Message) error {
+	body, err := txnjson.Marshal(txn)
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction: %v", err)
+	}
+
+	output := txnOutput{
+		Type:        string(proto.MessageName(txn)),
+		Transaction: body,
+	}
+
+	if base, ok := txn.(interface{ GetBase() *pb.BaseTXN }); ok {
+		output.Hash = transcode.HexEncode(base.GetBase().GetHash())
+	}
+
+	if !*f.dryRun {
+		if *f.validator == "" {
+			return errors.New("-validator is required to submit (or use -dry-run)")
+		}
+
+		client, err := zera.NewClient(zera.Config{Validators: []string{*f.validator}, HealthCheckInterval: -1})
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		if _, err := client.Submit(ctx, txn); err != nil {
+			return err
+		}
+
+		output.Submitted = true
+		output.Validator = client.Validators()[0]
+	}
+
+	return printJSON(output)
+}
+
+// run parses the shared flags and builds, prints and submits the transaction returned by build
+func run(flags *flag.FlagSet, common *txnFlags, args []string, build func(ctx context.Context, nonceInfo nonce.NonceInfo, signer helper.Signer) (proto.Message, error)) error {
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *common.timeout)
+	defer cancel()
+
+	signer, err := common.signer(ctx)
+	if err != nil {
+		return err
+	}
+
+	nonceInfo, err := common.nonceInfo(signer)
+	if err != nil {
+		return err
+	}
+
+	txn, err := build(ctx, nonceInfo, signer)
+	if err != nil {
+		return err
+	}
+
+	return common.finish(ctx, txn)
+}
+
+// stdout is where command output is printed
+var stdout io.Writer = os.Stdout
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+var stdin = bufio.NewReader(os.Stdin)
+
+// readSecret reads a secret from env, or a line from stdin (without echo if stdin is a terminal)
+func readSecret(env, prompt string) (string, error) {
+	if secret := os.Getenv(env); secret != "" {
+		return secret, nil
+	}
+
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, prompt+" ")
+		secret, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(secret)), nil
+	}
+
+	fmt.Fprintln(os.Stderr, prompt)
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (s *stringList) String() string { return strings.Join(*s, ",") }
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// optionalUint64 is a uint64 flag that records whether it was set, so -nonce 0 is rejected rather than read as no -nonce
+type optionalUint64 struct {
+	value uint64
+	set   bool
+}
+
+func (o *optionalUint64) String() string {
+	if o == nil || !o.set {
+		return ""
+	}
+	return strconv.FormatUint(o.value, 10)
+}
+
+func (o *optionalUint64) Set(value string) error {
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	o.value, o.set = parsed, true
+	return nil
+}
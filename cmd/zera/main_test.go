@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/keystore"
+	"github.com/ZeraVision/zera-go-sdk/txnjson"
+	"github.com/ZeraVision/zera-go-sdk/zeratest"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	TEST_PUBLIC    = "A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"
+	TEST_PRIVATE   = "2ap5CkCekErkqJ4UuSGAW1BmRRRNr8hXaebudv1j8TY6mJMSsbnniakorFGmetE4aegsyQAD8WX1N8Q2Y45YEBDs"
+	TEST_RECIPIENT = "Hv3KUwrmR8C8XVSxuJFJrQqeDixeDnakUTkUUMZkFCUS"
+)
+
+func TestTxnFlags(t *testing.T) {
+	tests := []struct {
+		args    []string
+		set     bool
+		nonce   uint64
+		wantErr bool
+	}{
+		{args: nil},
+		{args: []string{"-nonce", "7"}, set: true, nonce: 7},
+		{args: []string{"-nonce", "0"}, set: true, wantErr: true},
+		{args: []string{"-nonce", "-1"}, wantErr: true},
+		{args: []string{"-nonce", "seven"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+		common := addTxnFlags(flags)
+
+		err := flags.Parse(test.args)
+		if err == nil && common.nonce.set {
+			_, err = common.nonceInfo(nil)
+		}
+
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("Expected an error for %v, got none", test.args)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Expected no error for %v, got %v", test.args, err)
+		}
+
+		if common.nonce.set != test.set || common.nonce.value != test.nonce {
+			t.Errorf("Expected nonce %d (set %v) for %v, got %d (set %v)", test.nonce, test.set, test.args, common.nonce.value, common.nonce.set)
+		}
+
+		if *common.feeID != defaultFeeID || *common.feeAmount != defaultFeeAmount || *common.timeout != defaultTimeout {
+			t.Errorf("Expected the default fee and timeout, got %s %s %s", *common.feeID, *common.feeAmount, *common.timeout)
+		}
+	}
+}
+
+func TestTransferDryRun(t *testing.T) {
+	server := zeratest.NewServer()
+	defer server.Close()
+
+	args := append(keyArgs(t, TEST_PUBLIC), "-dry-run", "-nonce", "5", "-symbol", "$ZRA+0000", "-parts", "1000000000", "-to", TEST_RECIPIENT+"=1.5")
+
+	var txn pb.CoinTXN
+	output := runCommand(t, transferCommand, args, &txn)
+
+	if output.Submitted || output.Type != "zera_txn.CoinTXN" || output.Hash == "" {
+		t.Errorf("Expected an unsubmitted CoinTXN with a hash, got %+v", output)
+	}
+
+	if nonces := txn.GetAuth().GetNonce(); len(nonces) != 1 || nonces[0] != 5 {
+		t.Errorf("Expected nonce 5, got %v", nonces)
+	}
+
+	if outputs := txn.GetOutputTransfers(); len(outputs) != 1 || outputs[0].GetAmount() != "1500000000" {
+		t.Errorf("Expected 1500000000 parts to the recipient, got %v", outputs)
+	}
+
+	// the printed transaction is signed and hashed as the validator expects
+	submit(t, server, &txn)
+}
+
+func TestMintDryRun(t *testing.T) {
+	server := zeratest.NewServer()
+	defer server.Close()
+
+	t.Setenv("ZERA_INDEXER_AUTH", zeratest.APIKey)
+
+	// minting needs a restricted key, parts per coin are looked up on the indexer
+	args := append(keyArgs(t, "r_"+TEST_PUBLIC), "-dry-run", "-nonce", "1", "-symbol", "$ZRA+0000", "-amount", "2.5", "-recipient", TEST_RECIPIENT, "-indexer", server.Indexer.URL)
+
+	var txn pb.MintTXN
+	output := runCommand(t, mintCommand, args, &txn)
+
+	if output.Submitted || txn.GetBase().GetNonce() != 1 {
+		t.Errorf("Expected an unsubmitted mint with nonce 1, got %+v", output)
+	}
+
+	if txn.GetAmount() != "2500000000" || txn.GetContractId() != "$ZRA+0000" {
+		t.Errorf("Expected 2500000000 parts of $ZRA+0000, got %s of %s", txn.GetAmount(), txn.GetContractId())
+	}
+
+	submit(t, server, &txn)
+}
+
+func TestTransferNonceZero(t *testing.T) {
+	args := append(keyArgs(t, TEST_PUBLIC), "-dry-run", "-nonce", "0", "-symbol", "$ZRA+0000", "-parts", "1000000000", "-to", TEST_RECIPIENT+"=1")
+
+	if err := transferCommand(args); err == nil || !strings.Contains(err.Error(), "-nonce") {
+		t.Fatalf("Expected -nonce 0 to be rejected, got %v", err)
+	}
+}
+
+// keyArgs imports the test key (as publicKey) into a new keyring and returns the flags signing with it
+func keyArgs(t *testing.T, publicKey string) []string {
+	t.Helper()
+
+	dir := t.TempDir()
+	keyring, err := keystore.OpenKeyring(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err = keyring.Import(&keystore.Key{Name: "treasury", PublicKey: publicKey, PrivateKey: TEST_PRIVATE, KeyType: helper.ED25519}, "correct horse", keystore.Options{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	t.Setenv("ZERA_PASSPHRASE", "correct horse")
+	return []string{"-keystore", dir, "-key", "treasury"}
+}
+
+// runCommand runs command with args and decodes its output, and the transaction into txn
+func runCommand(t *testing.T, command func(args []string) error, args []string, txn proto.Message) txnOutput {
+	t.Helper()
+
+	var buf bytes.Buffer
+	stdout = &buf
+	defer func() { stdout = os.Stdout }()
+
+	if err := command(args); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var output txnOutput
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := txnjson.Unmarshal(output.Transaction, txn); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	return output
+}
+
+func submit(t *testing.T, server *zeratest.Server, txn proto.Message) {
+	t.Helper()
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Submit(context.Background(), txn); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if accepted := server.Accepted(); len(accepted) != 1 {
+		t.Errorf("Expected the transaction to be accepted, got %d accepted", len(accepted))
+	}
+}
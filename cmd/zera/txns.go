@@ -0,0 +1,387 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ZeraVision/zera-go-sdk/allowance"
+	"github.com/ZeraVision/zera-go-sdk/compliance"
+	"github.com/ZeraVision/zera-go-sdk/contract"
+	"github.com/ZeraVision/zera-go-sdk/convert"
+	"github.com/ZeraVision/zera-go-sdk/governance"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/mint"
+	"github.com/ZeraVision/zera-go-sdk/nonce"
+	"github.com/ZeraVision/zera-go-sdk/parts"
+	"github.com/ZeraVision/zera-go-sdk/transfer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func transferCommand(args []string) error {
+	flags := flag.NewFlagSet("transfer", flag.ExitOnError)
+	common := addTxnFlags(flags)
+	symbol := flags.String("symbol", "", "contract id of the coin, ie $ZRA+0000 (required)")
+	var to stringList
+	flags.Var(&to, "to", "recipient as <address>=<amount in full coins>, repeatable (required)")
+	denomination := addPartsFlags(flags)
+
+	return run(flags, common, args, func(ctx context.Context, nonceInfo nonce.NonceInfo, signer helper.Signer) (proto.Message, error) {
+		if *symbol == "" || len(to) == 0 {
+			return nil, errors.New("-symbol and -to are required")
+		}
+
+		outputs := make(map[string]string, len(to))
+		total := new(big.Rat)
+		decimals := 0
+		for _, recipient := range to {
+			address, amount, ok := strings.Cut(recipient, "=")
+			if !ok || address == "" {
+				return nil, fmt.Errorf("invalid recipient %q (expected <address>=<amount>)", recipient)
+			}
+
+			value, err := convert.ParseDecimal(amount)
+			if err != nil || value.IsZero() {
+				return nil, fmt.Errorf("invalid amount %q", amount)
+			}
+
+			if _, fraction, found := strings.Cut(amount, "."); found && len(fraction) > decimals {
+				decimals = len(fraction)
+			}
+
+			outputs[address] = amount
+			total.Add(total, value.Rat())
+		}
+
+		partsInfo, err := denomination.info(*symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		address, err := signerAddress(signer)
+		if err != nil {
+			return nil, err
+		}
+
+		inputs := []transfer.Inputs{{
+			B58Address: address,
+			Signer:     signer,
+			Amount:     total.FloatString(decimals), // the outputs have at most decimals places, so does their total
+			FeePercent: 100,
+		}}
+
+		return transfer.CreateCoinTxnWithContext(ctx, nonceInfo, partsInfo, inputs, outputs, *common.feeID, *common.feeAmount, nil, nil, nonce.DefaultConcurrency)
+	})
+}
+
+func mintCommand(args []string) error {
+	flags := flag.NewFlagSet("mint", flag.ExitOnError)
+	common := addTxnFlags(flags)
+	symbol := flags.String("symbol", "", "contract id, ie $TEST+0000 (required)")
+	amount := flags.String("amount", "", "amount in full coins, ie 1.23 (required)")
+	recipient := flags.String("recipient", "", "base58 recipient address (required)")
+	denomination := addPartsFlags(flags)
+
+	return run(flags, common, args, func(ctx context.Context, nonceInfo nonce.NonceInfo, signer helper.Signer) (proto.Message, error) {
+		if *symbol == "" || *amount == "" || *recipient == "" {
+			return nil, errors.New("-symbol, -amount and -recipient are required")
+		}
+
+		value, err := denomination.amount(ctx, *symbol, *amount)
+		if err != nil {
+			return nil, err
+		}
+
+		return mint.CreateMintTxnWithSigner(ctx, nonceInfo, *symbol, value.PartsString(), *recipient, signer, *common.feeID, *common.feeAmount)
+	})
+}
+
+// contractCommand creates or updates a contract from a spec file (see contract.TokenSpec / contract.UpdateSpec)
+func contractCommand(args []string) error {
+	if len(args) < 1 || (args[0] != "create" && args[0] != "update") {
+		return errors.New("usage: zera contract create|update -file contract.yaml [flags]")
+	}
+
+	flags := flag.NewFlagSet("contract "+args[0], flag.ExitOnError)
+	common := addTxnFlags(flags)
+	file := flags.String("file", "", "contract spec, YAML or JSON (required)")
+
+	return run(flags, common, args[1:], func(ctx context.Context, nonceInfo nonce.NonceInfo, signer helper.Signer) (proto.Message, error) {
+		if *file == "" {
+			return nil, errors.New("-file is required")
+		}
+
+		if args[0] == "create" {
+			token, err := contract.LoadTokenData(*file)
+			if err != nil {
+				return nil, err
+			}
+			if err := checkFindings(contract.Validate(token)); err != nil {
+				return nil, err
+			}
+			return contract.CreateContractTXNWithSigner(ctx, nonceInfo, token, signer, *common.feeID, *common.feeAmount)
+		}
+
+		update, err := contract.LoadUpdateData(*file)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkFindings(contract.ValidateUpdate(update)); err != nil {
+			return nil, err
+		}
+		return contract.UpdateContractTXNWithSigner(ctx, nonceInfo, update, signer, *common.feeID, *common.feeAmount)
+	})
+}
+
+// checkFindings prints warnings to stderr and refuses contracts with errors
+func checkFindings(findings contract.Findings) error {
+	for _, finding := range findings {
+		if finding.Severity == contract.SeverityWarning {
+			fmt.Fprintln(os.Stderr, finding)
+		}
+	}
+	return findings.Err()
+}
+
+func governanceCommand(args []string) error {
+	if len(args) < 1 {
+		return errors.New("usage: zera governance propose|vote [flags]")
+	}
+
+	switch args[0] {
+	case "propose":
+		return proposeCommand(args[1:])
+	case "vote":
+		return voteCommand(args[1:])
+	default:
+		return errors.New("usage: zera governance propose|vote [flags]")
+	}
+}
+
+func proposeCommand(args []string) error {
+	flags := flag.NewFlagSet("governance propose", flag.ExitOnError)
+	common := addTxnFlags(flags)
+	symbol := flags.String("symbol", "", "contract id of the governing contract (required)")
+	title := flags.String("title", "", "proposal title (required)")
+	synopsis := flags.String("synopsis", "", "proposal synopsis")
+	body := flags.String("body", "", "proposal body")
+	var options stringList
+	flags.Var(&options, "option", "vote option for multi option proposals, repeatable")
+	start := flags.String("start", "", "start time (RFC 3339), adaptive governance only")
+	end := flags.String("end", "", "end time (RFC 3339), adaptive governance only")
+
+	return run(flags, common, args, func(ctx context.Context, nonceInfo nonce.NonceInfo, signer helper.Signer) (proto.Message, error) {
+		if *symbol == "" || *title == "" {
+			return nil, errors.New("-symbol and -title are required")
+		}
+
+		startTimestamp, err := parseTimestamp(*start)
+		if err != nil {
+			return nil, err
+		}
+
+		endTimestamp, err := parseTimestamp(*end)
+		if err != nil {
+			return nil, err
+		}
+
+		return governance.CreateProposalTxnWithSigner(ctx, nonceInfo, *symbol, signer, *common.feeID, *common.feeAmount, *title, *synopsis, *body, options, startTimestamp, endTimestamp, nil)
+	})
+}
+
+func voteCommand(args []string) error {
+	flags := flag.NewFlagSet("governance vote", flag.ExitOnError)
+	common := addTxnFlags(flags)
+	symbol := flags.String("symbol", "", "contract id of the governing contract (required)")
+	proposal := flags.String("proposal", "", "proposal id in hex (required)")
+	support := flags.String("support", "", "true or false, for yes / no proposals")
+	option := flags.Int("option-index", -1, "index of the chosen option, for multi option proposals")
+
+	return run(flags, common, args, func(ctx context.Context, nonceInfo nonce.NonceInfo, signer helper.Signer) (proto.Message, error) {
+		if *symbol == "" || *proposal == "" {
+			return nil, errors.New("-symbol and -proposal are required")
+		}
+
+		var supportPtr *bool
+		var optionPtr *uint32
+
+		switch {
+		case *support != "" && *option >= 0:
+			return nil, errors.New("only one of -support and -option-index may be set")
+		case *support == "true" || *support == "false":
+			value := *support == "true"
+			supportPtr = &value
+		case *support != "":
+			return nil, fmt.Errorf("invalid -support %q (expected true or false)", *support)
+		case *option >= 0:
+			value := uint32(*option)
+			optionPtr = &value
+		default:
+			return nil, errors.New("one of -support and -option-index is required")
+		}
+
+		return governance.CreateVoteTxnWithSigner(ctx, nonceInfo, *symbol, *proposal, signer, *common.feeID, *common.feeAmount, supportPtr, optionPtr)
+	})
+}
+
+func allowanceCommand(args []string) error {
+	flags := flag.NewFlagSet("allowance", flag.ExitOnError)
+	common := addTxnFlags(flags)
+	symbol := flags.String("symbol", "", "contract id (required)")
+	walletAddr := flags.String("wallet", "", "base58 address of the allowed wallet (required)")
+	amount := flags.String("amount", "", "allowed amount in full coins, ie 1.23")
+	currencyEquivalent := flags.String("currency-equivalent", "", "allowed currency equivalent value, ie 1.23")
+	months := flags.Uint("months", 0, "allowance period in months")
+	seconds := flags.Uint("seconds", 0, "allowance period in seconds")
+	start := flags.String("start", "", "start time (RFC 3339, default now)")
+	revoke := flags.Bool("revoke", false, "revoke the allowance instead of approving it")
+	denomination := addPartsFlags(flags)
+
+	return run(flags, common, args, func(ctx context.Context, nonceInfo nonce.NonceInfo, signer helper.Signer) (proto.Message, error) {
+		if *symbol == "" || *walletAddr == "" {
+			return nil, errors.New("-symbol and -wallet are required")
+		}
+
+		details := allowance.AllowanceDetails{
+			Authorize:  !*revoke,
+			WalletAddr: *walletAddr,
+			StartTime:  time.Now().Unix(),
+		}
+
+		if *amount != "" {
+			value, err := denomination.amount(ctx, *symbol, *amount)
+			if err != nil {
+				return nil, err
+			}
+			details.Amount = value.Parts()
+		}
+
+		if *currencyEquivalent != "" {
+			value, err := convert.ParseDecimal(*currencyEquivalent)
+			if err != nil {
+				return nil, err
+			}
+			details.CurrencyEquivalent = &value
+		}
+
+		if *months != 0 {
+			value := uint32(*months)
+			details.PeriodMonths = &value
+		}
+
+		if *seconds != 0 {
+			value := uint32(*seconds)
+			details.PeriodSeconds = &value
+		}
+
+		if *start != "" {
+			startTime, err := time.Parse(time.RFC3339, *start)
+			if err != nil {
+				return nil, fmt.Errorf("invalid -start: %v", err)
+			}
+			details.StartTime = startTime.Unix()
+		}
+
+		return allowance.CreateAllowanceTxnWithSigner(ctx, nonceInfo, *symbol, details, signer, *common.feeID, *common.feeAmount)
+	})
+}
+
+func complianceCommand(args []string) error {
+	flags := flag.NewFlagSet("compliance", flag.ExitOnError)
+	common := addTxnFlags(flags)
+	symbol := flags.String("symbol", "", "contract id (required)")
+	walletAddr := flags.String("wallet", "", "base58 address of the wallet (required)")
+	level := flags.Int("level", -1, "compliance level (required)")
+	revoke := flags.Bool("revoke", false, "revoke the level instead of assigning it")
+	expiry := flags.String("expiry", "", "expiry of the level (RFC 3339)")
+
+	return run(flags, common, args, func(ctx context.Context, nonceInfo nonce.NonceInfo, signer helper.Signer) (proto.Message, error) {
+		if *symbol == "" || *walletAddr == "" || *level < 0 {
+			return nil, errors.New("-symbol, -wallet and -level are required")
+		}
+
+		expiryTimestamp, err := parseTimestamp(*expiry)
+		if err != nil {
+			return nil, err
+		}
+
+		details := []compliance.ComplianceDetails{{
+			WalletAddr: *walletAddr,
+			Level:      uint32(*level),
+			Assign:     !*revoke,
+			Expiry:     expiryTimestamp,
+		}}
+
+		return compliance.CreateComplianceTxnWithSigner(ctx, nonceInfo, *symbol, details, signer, *common.feeID, *common.feeAmount)
+	})
+}
+
+// parseTimestamp parses an optional RFC 3339 time
+func parseTimestamp(value string) (*timestamppb.Timestamp, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %q: %v", value, err)
+	}
+
+	return timestamppb.New(parsed), nil
+}
+
+// partsFlags are the parts per coin of a contract, given or looked up on an indexer
+type partsFlags struct {
+	partsPerCoin *string
+	indexer      *string
+}
+
+func addPartsFlags(flags *flag.FlagSet) *partsFlags {
+	return &partsFlags{
+		partsPerCoin: flags.String("parts", "", "parts per coin of the contract (looked up on -indexer if empty)"),
+		indexer:      flags.String("indexer", os.Getenv("ZERA_INDEXER"), "indexer url for the parts lookup, authorization from ZERA_INDEXER_AUTH"),
+	}
+}
+
+// info is the parts lookup for symbol
+func (f *partsFlags) info(symbol string) (parts.PartsInfo, error) {
+	partsInfo := parts.PartsInfo{Symbol: symbol}
+	if *f.partsPerCoin != "" {
+		override, ok := new(big.Int).SetString(*f.partsPerCoin, 10)
+		if !ok {
+			return parts.PartsInfo{}, fmt.Errorf("invalid parts %q", *f.partsPerCoin)
+		}
+		partsInfo.Override = override
+		return partsInfo, nil
+	}
+
+	if *f.indexer == "" {
+		return parts.PartsInfo{}, errors.New("-parts or -indexer is required")
+	}
+
+	partsInfo.UseIndexer = true
+	partsInfo.IndexerUrl = *f.indexer
+	partsInfo.Authorization = os.Getenv("ZERA_INDEXER_AUTH")
+	return partsInfo, nil
+}
+
+// amount parses value in full coins of symbol
+func (f *partsFlags) amount(ctx context.Context, symbol, value string) (convert.Amount, error) {
+	partsInfo, err := f.info(symbol)
+	if err != nil {
+		return convert.Amount{}, err
+	}
+
+	partsPerCoin, err := parts.GetPartsWithContext(ctx, partsInfo)
+	if err != nil {
+		return convert.Amount{}, fmt.Errorf("could not get parts: %v", err)
+	}
+
+	return convert.ParseAmount(value, partsPerCoin)
+}
@@ -3,16 +3,14 @@ package compliance
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 	"github.com/golang/protobuf/ptypes/timestamp"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -26,13 +24,23 @@ type ComplianceDetails struct {
 }
 
 func CreateComplianceTxn(nonceInfo nonce.NonceInfo, symbol string, details []ComplianceDetails, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.ComplianceTXN, error) {
+	return CreateComplianceTxnWithContext(context.Background(), nonceInfo, symbol, details, publicKeyBase58, privateKeyBase58, feeID, feeAmountParts)
+}
+
+// CreateComplianceTxnWithContext is CreateComplianceTxn with a context that is passed through to the nonce lookup.
+func CreateComplianceTxnWithContext(ctx context.Context, nonceInfo nonce.NonceInfo, symbol string, details []ComplianceDetails, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.ComplianceTXN, error) {
+	return CreateComplianceTxnWithSigner(ctx, nonceInfo, symbol, details, helper.NewPrivateKeySigner(publicKeyBase58, privateKeyBase58), feeID, feeAmountParts)
+}
+
+// CreateComplianceTxnWithSigner is CreateComplianceTxn with the transaction signed by signer instead of a base58 private key.
+func CreateComplianceTxnWithSigner(ctx context.Context, nonceInfo nonce.NonceInfo, symbol string, details []ComplianceDetails, signer helper.Signer, feeID string, feeAmountParts string) (*pb.ComplianceTXN, error) {
 	// Step 1: Decode public key
-	_, _, pubKeyBytes, err := transcode.Base58DecodePublicKey(publicKeyBase58)
+	txnPublicKey, err := helper.TxnPublicKey(signer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode public key: %v", err)
 	}
 
-	nonce, err := nonce.GetNonce(nonceInfo, 5)
+	nonce, err := nonce.GetNonceWithContext(ctx, nonceInfo, nonce.DefaultConcurrency)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nonce: %v", err)
@@ -44,9 +52,7 @@ func CreateComplianceTxn(nonceInfo nonce.NonceInfo, symbol string, details []Com
 
 	// Step 2: Create BaseTXN
 	base := &pb.BaseTXN{
-		PublicKey: &pb.PublicKey{
-			Single: pubKeyBytes,
-		},
+		PublicKey: txnPublicKey,
 		FeeId:     feeID,
 		FeeAmount: feeAmountParts,
 		Timestamp: timestamppb.New(time.Now().UTC()),
@@ -84,14 +90,8 @@ func CreateComplianceTxn(nonceInfo nonce.NonceInfo, symbol string, details []Com
 		return nil, fmt.Errorf("failed to serialize compliance transaction: %v", err)
 	}
 
-	keyType, err := helper.DetermineKeyType(publicKeyBase58)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine key type: %v", err)
-	}
-
 	// Step 5: Sign the transaction
-	signature, err := helper.Sign(privateKeyBase58, byteDataNoSig, keyType)
+	signature, err := helper.SignWith(signer, byteDataNoSig)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign compliance transaction: %v", err)
@@ -114,20 +114,26 @@ func CreateComplianceTxn(nonceInfo nonce.NonceInfo, symbol string, details []Com
 }
 
 func SendComplianceTxn(grpcAddr string, txn *pb.ComplianceTXN) (*emptypb.Empty, error) {
-	if !strings.Contains(grpcAddr, ":") {
-		grpcAddr += ":50052"
-	}
+	return SendComplianceTxnWithContext(context.Background(), grpcAddr, txn)
+}
+
+// SendComplianceTxnWithContext is SendComplianceTxn with a context that is passed through to the gRPC call.
+func SendComplianceTxnWithContext(ctx context.Context, grpcAddr string, txn *pb.ComplianceTXN) (*emptypb.Empty, error) {
+	return SendComplianceTxnWithSecurity(ctx, grpcAddr, txn, nil)
+}
 
-	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// SendComplianceTxnWithSecurity is SendComplianceTxnWithContext with the validator dialed using security (plaintext if nil).
+func SendComplianceTxnWithSecurity(ctx context.Context, grpcAddr string, txn *pb.ComplianceTXN, security *helper.TransportSecurity) (*emptypb.Empty, error) {
+	conn, err := helper.NewConnection(grpcAddr, "50052", security)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 
 	client := pb.NewTXNServiceClient(conn)
-	response, err := client.Compliance(context.Background(), txn)
+	response, err := client.Compliance(ctx, txn)
 	if err != nil {
-		return nil, fmt.Errorf("compliance transaction failed: %v", err)
+		return nil, fmt.Errorf("compliance transaction failed: %w", zeraerr.FromGRPC(err))
 	}
 
 	return response, nil
@@ -17,13 +17,16 @@ const (
 	FeePercentage         FeeType = 2 // percentage of transaction amount
 )
 
+// partsPerPercent is the denomination of a 0-100 percentage on the network, 1% is 1e16 parts (100% is hundredPercentParts)
+var partsPerPercent = big.NewInt(1e16)
+
 type ContractFeeConfig struct {
-	Type                 FeeType  // the type of fee
-	Address              string   // where the fees go to
-	Fee                  float64  // depends on type: fixed (number of tokens) | current equivalent $xx.xx | percent (0-100) || remainder not given to burn and validator goes here
-	Burn                 float64  // Percentage burned (0-100)
-	Validator            float64  // Percentage to validator (0-100)
-	AllowedFeeInstrument []string // contractID of the contracts allowed to pay the fee instrument. //! If allowed fee instrument is not self, a calculation is done based on self/auth currency equivalent values
+	Type                 FeeType        // the type of fee
+	Address              string         // where the fees go to
+	Fee                  convert.Amount // depends on type: fixed (number of tokens) | current equivalent $xx.xx | percent (0-100) || remainder not given to burn and validator goes here
+	Burn                 convert.Amount // Percentage burned (0-100)
+	Validator            convert.Amount // Percentage to validator (0-100)
+	AllowedFeeInstrument []string       // contractID of the contracts allowed to pay the fee instrument. //! If allowed fee instrument is not self, a calculation is done based on self/auth currency equivalent values
 }
 
 func CreateContractFee(config ContractFeeConfig, parts *big.Int) (*pb.ContractFees, error) {
@@ -32,42 +35,40 @@ func CreateContractFee(config ContractFeeConfig, parts *big.Int) (*pb.ContractFe
 		return nil, fmt.Errorf("failed to decode fee address: %v", err)
 	}
 
-	var feeString, burnString, validatorString string
+	var feeString string
 
-	if config.Type == FeeFixed {
-		fee := new(big.Float).Mul(big.NewFloat(config.Fee), convert.ToBigFloat(parts))
-		feeInt := new(big.Int)
-		feeInt, _ = fee.Int(feeInt)
+	if config.Type == FeeFixed || config.Type == FeeCurrencyEquivalent || config.Type == FeePercentage {
+		scale := parts
+		if config.Type != FeeFixed {
+			// Scaled to 1e18 for network (0-100 scale * 1e16)
+			scale = partsPerPercent
+		}
 
-		feeString = feeInt.String()
-	} else if config.Type == FeeCurrencyEquivalent || config.Type == FeePercentage {
-		// Scaled to 1e18 for network (0-100 scale * 1e16)
-		fee := new(big.Float).Mul(big.NewFloat(config.Fee), convert.ToBigFloat(1e16))
-		feeInt := new(big.Int)
-		feeInt, _ = fee.Int(feeInt)
+		fee, err := config.Fee.PartsAt(scale)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fee: %v", err)
+		}
 
-		feeString = feeInt.String()
+		feeString = fee.String()
 	}
 
 	// Burn percent
-	burn := new(big.Float).Mul(big.NewFloat(config.Burn), convert.ToBigFloat(1e16))
-	burnInt := new(big.Int)
-	burnInt, _ = burn.Int(burnInt)
-
-	burnString = burnInt.String()
+	burn, err := config.Burn.PartsAt(partsPerPercent)
+	if err != nil {
+		return nil, fmt.Errorf("invalid burn percent: %v", err)
+	}
 
 	// Validator Percent
-	validator := new(big.Float).Mul(big.NewFloat(config.Validator), convert.ToBigFloat(1e16))
-	validatorInt := new(big.Int)
-	validatorInt, _ = validator.Int(validatorInt)
-
-	validatorString = validatorInt.String()
+	validator, err := config.Validator.PartsAt(partsPerPercent)
+	if err != nil {
+		return nil, fmt.Errorf("invalid validator percent: %v", err)
+	}
 
 	return &pb.ContractFees{
 		ContractFeeType:      pb.CONTRACT_FEE_TYPE(config.Type),
 		Fee:                  feeString,
-		Burn:                 burnString,
-		Validator:            validatorString,
+		Burn:                 burn.String(),
+		Validator:            validator.String(),
 		FeeAddress:           feeAddr,
 		AllowedFeeInstrument: config.AllowedFeeInstrument,
 	}, nil
@@ -9,18 +9,19 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-func CreateMaxSupply(maxSupply float64, parts *big.Int) (string, error) {
-	// Convert into parts
-	maxSupplyF := new(big.Float).Mul(big.NewFloat(maxSupply), convert.ToBigFloat(parts))
-	amount := new(big.Int)
-	amount, _ = maxSupplyF.Int(amount)
+// CreateMaxSupply converts maxSupply into parts, amounts that are not a whole number of parts are an error
+func CreateMaxSupply(maxSupply convert.Amount, parts *big.Int) (string, error) {
+	amount, err := maxSupply.PartsAt(parts)
+	if err != nil {
+		return "", fmt.Errorf("invalid max supply: %v", err)
+	}
 
 	return amount.String(), nil
 }
 
 type ReleaseScheduleConfig struct {
 	ReleaseDate *timestamppb.Timestamp // the date of the release (in UTC)
-	Amount      float64                // the amount to release
+	Amount      convert.Amount         // the amount to release in full coins (parts calculation done within helper function)
 }
 
 func CreateMaxSupplyRelease(releaseConfig []ReleaseScheduleConfig, parts *big.Int, maxSupply string) ([]*pb.MaxSupplyRelease, error) {
@@ -28,10 +29,11 @@ func CreateMaxSupplyRelease(releaseConfig []ReleaseScheduleConfig, parts *big.In
 
 	totalRelease := big.NewInt(0)
 
-	for _, release := range releaseConfig {
-		releaseParts := new(big.Float).Mul(big.NewFloat(release.Amount), convert.ToBigFloat(parts))
-		releaseAmount := new(big.Int)
-		releaseAmount, _ = releaseParts.Int(releaseAmount)
+	for i, release := range releaseConfig {
+		releaseAmount, err := release.Amount.PartsAt(parts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid release %d amount: %v", i, err)
+		}
 
 		maxSupplyRelease = append(maxSupplyRelease, &pb.MaxSupplyRelease{
 			ReleaseDate: release.ReleaseDate,
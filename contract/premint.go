@@ -1,6 +1,7 @@
 package contract
 
 import (
+	"fmt"
 	"math/big"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
@@ -9,8 +10,8 @@ import (
 )
 
 type PremintConfig struct {
-	Address string  // the address to premint in base58
-	Amount  float64 // the amount as a whole number (parts calculation done within helper function)
+	Address string         // the address to premint in base58
+	Amount  convert.Amount // the amount in full coins (parts calculation done within helper function)
 }
 
 func CreatePremint(premints []PremintConfig, parts *big.Int) ([]*pb.PreMintWallet, error) {
@@ -24,9 +25,10 @@ func CreatePremint(premints []PremintConfig, parts *big.Int) ([]*pb.PreMintWalle
 			return nil, err
 		}
 
-		amountF := new(big.Float).Mul(big.NewFloat(premint.Amount), convert.ToBigFloat(parts))
-		amount := new(big.Int)
-		amount, _ = amountF.Int(amount)
+		amount, err := premint.Amount.PartsAt(parts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid premint amount for %s: %v", premint.Address, err)
+		}
 
 		premintResult = append(premintResult, &pb.PreMintWallet{
 			Address: addr,
@@ -2,7 +2,6 @@ package contract
 
 import (
 	"fmt"
-	"strings"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
 	"github.com/ZeraVision/zera-go-sdk/helper"
@@ -66,7 +65,7 @@ func CreateRestrictedKeys(config []RestrictedConfig) ([]*pb.RestrictedKey, error
 func checkRestricted(publicKey helper.PublicKey) bool {
 
 	if publicKey.Single != nil {
-		if strings.HasPrefix(*publicKey.Single, "r_") {
+		if parsed, err := helper.ParsePublicKey(*publicKey.Single); err == nil && parsed.Restricted {
 			return true
 		}
 	}
@@ -0,0 +1,804 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/convert"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/yaml.v3"
+)
+
+// TokenSpec describes a whole instrument in human units, so a contract launch can be reviewed as a config file
+// (YAML or JSON, see ParseTokenSpec) and compiled into TokenData. Amounts are full coins as decimal numbers or
+// strings (ie 21000000 or "0.000000001") and are converted to parts exactly, percentages are 0-100.
+//
+//	type: token
+//	contractId: $TEST+0000
+//	symbol: TEST
+//	name: Test Token
+//	version: 1.0.0
+//	denomination: {parts: 1000000000, name: smolpart}
+//	maxSupply: 1000000
+//	releases:
+//	  - {date: 2026-01-01T00:00:00Z, amount: 500000}
+//	  - {date: 2027-01-01T00:00:00Z, amount: 500000}
+//	premint:
+//	  - {address: 8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR, amount: 1000}
+//	governance:
+//	  type: staggered
+//	  regularQuorum: 50.1
+//	  threshold: 51
+//	  proposalPeriod: {unit: months, voting: 2}
+//	  proposalInstruments: [$TEST+0000]
+//	  votingInstruments: [$TEST+0000]
+//	restrictedKeys:
+//	  - publicKey: r_A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7
+//	    permissions: [update_contract, mint, propose]
+//	    keyWeight: 0
+//	fees: {type: currency_equivalent, address: 8Zfv..., fee: 0.2, burn: 39.5, validator: 10.5, allowedInstruments: [$TEST+0000]}
+type TokenSpec struct {
+	Type               string                   `json:"type"`               // optional, token (default), nft or sbt
+	ContractId         string                   `json:"contractId"`         // required, ie $TEST+0000
+	Symbol             string                   `json:"symbol"`             // required, ie TEST
+	Name               string                   `json:"name"`               // required
+	Version            string                   `json:"version"`            // optional, major.minor.patch (default 1.0.0)
+	Memo               *string                  `json:"memo"`               // optional, memo in base
+	Denomination       *DenominationSpec        `json:"denomination"`       // required for tokens
+	MaxSupply          json.Number              `json:"maxSupply"`          // optional, full coins (items for nft / sbt)
+	Releases           []ReleaseSpec            `json:"releases"`           // optional, must add up to maxSupply
+	Premint            []PremintSpec            `json:"premint"`            // optional, tokens only
+	Governance         *GovernanceSpec          `json:"governance"`         // optional
+	RestrictedKeys     []KeySpec                `json:"restrictedKeys"`     // optional
+	Fees               *FeeSpec                 `json:"fees"`               // optional, contract fees on transfers
+	UpdateContractFees bool                     `json:"updateContractFees"` // optional, fees may be changed by an update
+	ExpenseRatio       []ExpenseSpec            `json:"expenseRatio"`       // optional
+	UpdateExpenseRatio bool                     `json:"updateExpenseRatio"` // optional, expense ratio may be changed by an update
+	QuashThreshold     *uint32                  `json:"quashThreshold"`     // optional
+	Compliance         [][]ComplianceConfigSpec `json:"compliance"`         // optional, any inner list satisfied (all of its entries) is compliant
+	KycStatus          bool                     `json:"kycStatus"`          // optional
+	ImmutableKycStatus bool                     `json:"immutableKycStatus"` // optional
+	CurrencyEquivalent json.Number              `json:"currencyEquivalent"` // optional, starting self currency equivalent (tokens only)
+	CustomParameters   []KeyValuePair           `json:"customParameters"`   // optional, [{key: ..., value: ...}]
+}
+
+// UpdateSpec is the update counterpart of TokenSpec, compiled into UpdateData. Unset fields are left unchanged.
+type UpdateSpec struct {
+	ContractId         string                   `json:"contractId"`         // required
+	Version            string                   `json:"version"`            // required, greater than the current version
+	Parts              json.Number              `json:"parts"`              // optional, parts per coin, required for fixed fees
+	Name               *string                  `json:"name"`               // optional
+	Memo               *string                  `json:"memo"`               // optional
+	Governance         *GovernanceSpec          `json:"governance"`         // optional
+	RestrictedKeys     []KeySpec                `json:"restrictedKeys"`     // optional, replaces the current keys
+	Fees               *FeeSpec                 `json:"fees"`               // optional
+	ExpenseRatio       []ExpenseSpec            `json:"expenseRatio"`       // optional
+	QuashThreshold     *uint32                  `json:"quashThreshold"`     // optional
+	Compliance         [][]ComplianceConfigSpec `json:"compliance"`         // optional
+	KycStatus          *bool                    `json:"kycStatus"`          // optional
+	ImmutableKycStatus *bool                    `json:"immutableKycStatus"` // optional
+	CustomParameters   []KeyValuePair           `json:"customParameters"`   // optional
+}
+
+type DenominationSpec struct {
+	Parts json.Number `json:"parts"` // parts per coin, ie 1000000000
+	Name  string      `json:"name"`  // name of a part
+}
+
+type ReleaseSpec struct {
+	Date   time.Time   `json:"date"`   // RFC 3339
+	Amount json.Number `json:"amount"` // full coins
+}
+
+type PremintSpec struct {
+	Address string      `json:"address"` // base58
+	Amount  json.Number `json:"amount"`  // full coins
+}
+
+type GovernanceSpec struct {
+	Type                string      `json:"type"`                // staged, cycle, staggered or adaptive
+	RegularQuorum       float64     `json:"regularQuorum"`       // 0-100
+	FastQuorum          *float64    `json:"fastQuorum"`          // optional, 0-100
+	Threshold           float64     `json:"threshold"`           // 0-100
+	ProposalInstruments []string    `json:"proposalInstruments"` // contracts whose holders may propose
+	VotingInstruments   []string    `json:"votingInstruments"`   // contracts whose holders may vote
+	AlwaysWinner        *bool       `json:"alwaysWinner"`        // optional, multi option proposals always pick a winner
+	AllowMulti          bool        `json:"allowMulti"`          // optional, allow multi option proposals
+	ProposalPeriod      *PeriodSpec `json:"proposalPeriod"`      // staged, cycle and staggered
+	Start               *time.Time  `json:"start"`               // staged and cycle, RFC 3339
+	Stages              []StageSpec `json:"stages"`              // staged
+}
+
+type PeriodSpec struct {
+	Unit   string `json:"unit"`   // days or months
+	Voting uint32 `json:"voting"` // voting period length
+}
+
+type StageSpec struct {
+	Unit        string `json:"unit"`        // days or months
+	Length      uint32 `json:"length"`      // stage length
+	Break       bool   `json:"break"`       // no voting during the stage
+	MaxApproved uint32 `json:"maxApproved"` // max approved proposals passing the stage
+}
+
+// KeySpec is a restricted key, exactly one of PublicKey, Inheritence, Governance, SmartContract or Multi is set.
+type KeySpec struct {
+	PublicKey     string                      `json:"publicKey"`     // r_ single key, gov_ or sc_ key
+	Inheritence   string                      `json:"inheritence"`   // contract id whose keys are inherited, ie $ZRA+0000
+	Governance    string                      `json:"governance"`    // contract id of the governing contract
+	SmartContract *helper.SmartContractHelper `json:"smartContract"` // {name: exchange, instance: 1}
+	Multi         *MultiKeySpec               `json:"multi"`         // restricted multi-key
+
+	Permissions []string `json:"permissions"` // see Permissions
+	TimeDelay   int64    `json:"timeDelay"`   // optional
+	Global      bool     `json:"global"`      // optional
+	KeyWeight   uint32   `json:"keyWeight"`   // lower is more privileged
+}
+
+type MultiKeySpec struct {
+	Keys       []helper.MultiKey        `json:"keys"`       // [{class: 1, publicKey: A_...}]
+	Patterns   [][]helper.MultiPatterns `json:"patterns"`   // [[{class: 1, required: 2}]]
+	HashTokens []string                 `json:"hashTokens"` // ie [r, c]
+}
+
+type FeeSpec struct {
+	Type               string      `json:"type"`               // fixed, currency_equivalent or percentage
+	Address            string      `json:"address"`            // base58 address receiving the fee
+	Fee                json.Number `json:"fee"`                // fixed: full coins, currency_equivalent: $, percentage: 0-100
+	Burn               json.Number `json:"burn"`               // 0-100
+	Validator          json.Number `json:"validator"`          // 0-100
+	AllowedInstruments []string    `json:"allowedInstruments"` // contracts the fee may be paid in
+}
+
+type ExpenseSpec struct {
+	Month   uint32  `json:"month"`   // 1-12
+	Day     uint32  `json:"day"`     // day of month
+	Percent float64 `json:"percent"` // 0-100, up to 4 decimals
+}
+
+type ComplianceConfigSpec struct {
+	ContractId string `json:"contractId"` // contract the compliance level is issued by
+	Level      uint32 `json:"level"`
+}
+
+// Permissions are the names of the restricted key permissions
+var Permissions = []string{"update_contract", "transfer", "quash", "mint", "propose", "vote", "compliance", "expense_ratio", "cur_equiv", "revoke"}
+
+// LoadTokenSpec reads a TokenSpec from a YAML or JSON file.
+func LoadTokenSpec(path string) (*TokenSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := ParseTokenSpec(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return spec, nil
+}
+
+// ParseTokenSpec parses a TokenSpec from YAML or JSON. Unknown fields are an error.
+func ParseTokenSpec(data []byte) (*TokenSpec, error) {
+	var spec TokenSpec
+	if err := decodeSpec(data, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// LoadUpdateSpec reads an UpdateSpec from a YAML or JSON file.
+func LoadUpdateSpec(path string) (*UpdateSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := ParseUpdateSpec(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return spec, nil
+}
+
+// ParseUpdateSpec parses an UpdateSpec from YAML or JSON. Unknown fields are an error.
+func ParseUpdateSpec(data []byte) (*UpdateSpec, error) {
+	var spec UpdateSpec
+	if err := decodeSpec(data, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// decodeSpec reads YAML (of which JSON is a subset) and decodes it through JSON, so there is a single set of
+// field names and numbers keep their exact text
+func decodeSpec(data []byte, out interface{}) error {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("failed to parse spec: %v", err)
+	}
+
+	if len(document.Content) == 0 {
+		return errors.New("spec is empty")
+	}
+
+	value, err := yamlToJSON(document.Content[0])
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(out); err != nil {
+		return fmt.Errorf("invalid spec: %v", err)
+	}
+
+	return nil
+}
+
+func yamlToJSON(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		object := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if _, exists := object[key]; exists {
+				return nil, fmt.Errorf("line %d: duplicate field %s", node.Content[i].Line, key)
+			}
+
+			value, err := yamlToJSON(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			object[key] = value
+		}
+		return object, nil
+
+	case yaml.SequenceNode:
+		array := make([]interface{}, 0, len(node.Content))
+		for _, child := range node.Content {
+			value, err := yamlToJSON(child)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		return array, nil
+
+	case yaml.AliasNode:
+		return yamlToJSON(node.Alias)
+
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			return nil, nil
+		case "!!bool":
+			var value bool
+			if err := node.Decode(&value); err != nil {
+				return nil, err
+			}
+			return value, nil
+		case "!!int", "!!float":
+			// keep the literal so amounts are not rounded through float64
+			if _, ok := new(big.Rat).SetString(node.Value); !ok {
+				return nil, fmt.Errorf("line %d: invalid number %s", node.Line, node.Value)
+			}
+			return json.Number(node.Value), nil
+		default:
+			return node.Value, nil
+		}
+	}
+
+	return nil, fmt.Errorf("line %d: unsupported yaml node", node.Line)
+}
+
+// LoadTokenData reads and compiles a TokenSpec file.
+func LoadTokenData(path string) (*TokenData, error) {
+	spec, err := LoadTokenSpec(path)
+	if err != nil {
+		return nil, err
+	}
+	return spec.TokenData()
+}
+
+// LoadUpdateData reads and compiles an UpdateSpec file.
+func LoadUpdateData(path string) (*UpdateData, error) {
+	spec, err := LoadUpdateSpec(path)
+	if err != nil {
+		return nil, err
+	}
+	return spec.UpdateData()
+}
+
+// TokenData compiles the spec with the Create* helpers.
+func (s *TokenSpec) TokenData() (*TokenData, error) {
+	if s.ContractId == "" || s.Symbol == "" || s.Name == "" {
+		return nil, errors.New("contractId, symbol and name are required")
+	}
+
+	version, err := parseVersion(s.Version, "1.0.0")
+	if err != nil {
+		return nil, err
+	}
+
+	data := &TokenData{
+		ContractVersion:    version,
+		ContractId:         s.ContractId,
+		Symbol:             s.Symbol,
+		Name:               s.Name,
+		Memo:               s.Memo,
+		UpdateExpenseRatio: s.UpdateExpenseRatio,
+		UpdateContractFees: s.UpdateContractFees,
+		QuashThreshold:     s.QuashThreshold,
+		KycStatus:          s.KycStatus,
+		ImmutableKycStatus: s.ImmutableKycStatus,
+		CustomParameters:   CreateCustomParameters(s.CustomParameters),
+	}
+
+	// Step 1: Type and denomination, nft / sbt have one part per item
+	parts := big.NewInt(1)
+	switch strings.ToLower(s.Type) {
+	case "", "token":
+		data.Type = pb.CONTRACT_TYPE_TOKEN
+
+		if s.Denomination == nil {
+			return nil, errors.New("denomination is required for tokens")
+		}
+
+		var ok bool
+		parts, ok = new(big.Int).SetString(s.Denomination.Parts.String(), 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid denomination parts %q", s.Denomination.Parts)
+		}
+
+		if data.Denomination, err = CreateDenomination(parts, s.Denomination.Name); err != nil {
+			return nil, err
+		}
+
+	case "nft":
+		data.Type = pb.CONTRACT_TYPE_NFT
+	case "sbt":
+		data.Type = pb.CONTRACT_TYPE_SBT
+	default:
+		return nil, fmt.Errorf("unknown contract type %q", s.Type)
+	}
+
+	if data.Type != pb.CONTRACT_TYPE_TOKEN && (s.Denomination != nil || len(s.Premint) > 0 || s.CurrencyEquivalent != "") {
+		return nil, fmt.Errorf("denomination, premint and currencyEquivalent are not supported for %s contracts", s.Type)
+	}
+
+	// Step 2: Supply, release schedule and premint in parts
+	if s.MaxSupply != "" {
+		if data.MaxSupply, err = toParts(s.MaxSupply, parts, "maxSupply"); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(s.Releases) > 0 {
+		if s.MaxSupply == "" {
+			return nil, errors.New("releases require maxSupply")
+		}
+
+		total := new(big.Int)
+		for i, release := range s.Releases {
+			amount, err := toParts(release.Amount, parts, fmt.Sprintf("releases[%d].amount", i))
+			if err != nil {
+				return nil, err
+			}
+
+			value, _ := new(big.Int).SetString(amount, 10)
+			total.Add(total, value)
+
+			data.MaxSupplyRelease = append(data.MaxSupplyRelease, &pb.MaxSupplyRelease{
+				ReleaseDate: timestamppb.New(release.Date),
+				Amount:      amount,
+			})
+		}
+
+		if total.String() != data.MaxSupply {
+			return nil, fmt.Errorf("total release amount %v does not match max supply %v", total, data.MaxSupply)
+		}
+	}
+
+	for i, premint := range s.Premint {
+		address, err := transcode.Base58Decode(premint.Address)
+		if err != nil || len(address) == 0 {
+			return nil, fmt.Errorf("premint[%d]: invalid address %q", i, premint.Address)
+		}
+
+		amount, err := toParts(premint.Amount, parts, fmt.Sprintf("premint[%d].amount", i))
+		if err != nil {
+			return nil, err
+		}
+
+		data.Premint = append(data.Premint, &pb.PreMintWallet{Address: address, Amount: amount})
+	}
+
+	if s.CurrencyEquivalent != "" {
+		currencyEquivalent, err := toDecimal(s.CurrencyEquivalent, "currencyEquivalent")
+		if err != nil {
+			return nil, err
+		}
+		data.CurEquivStart = &currencyEquivalent
+	}
+
+	// Step 3: Governance, keys, fees, expense ratio and compliance
+	if data.Governance, err = s.Governance.compile(); err != nil {
+		return nil, err
+	}
+
+	if data.RestrictedKeys, err = compileKeys(s.RestrictedKeys); err != nil {
+		return nil, err
+	}
+
+	if data.ContractFees, err = s.Fees.compile(parts); err != nil {
+		return nil, err
+	}
+
+	if data.ExpenseRatio, err = compileExpenseRatio(s.ExpenseRatio); err != nil {
+		return nil, err
+	}
+
+	if data.TokenCompliance, err = compileCompliance(s.Compliance); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// UpdateData compiles the spec with the Create* helpers.
+func (s *UpdateSpec) UpdateData() (*UpdateData, error) {
+	if s.ContractId == "" || s.Version == "" {
+		return nil, errors.New("contractId and version are required")
+	}
+
+	version, err := parseVersion(s.Version, "")
+	if err != nil {
+		return nil, err
+	}
+
+	data := &UpdateData{
+		ContractId:         s.ContractId,
+		ContractVersion:    version,
+		Name:               s.Name,
+		Memo:               s.Memo,
+		QuashThreshold:     s.QuashThreshold,
+		KycStatus:          s.KycStatus,
+		ImmutableKycStatus: s.ImmutableKycStatus,
+		CustomParameters:   CreateCustomParameters(s.CustomParameters),
+	}
+
+	var parts *big.Int
+	if s.Parts != "" {
+		var ok bool
+		if parts, ok = new(big.Int).SetString(s.Parts.String(), 10); !ok || parts.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid parts %q", s.Parts)
+		}
+	}
+
+	if data.Governance, err = s.Governance.compile(); err != nil {
+		return nil, err
+	}
+
+	if data.RestrictedKeys, err = compileKeys(s.RestrictedKeys); err != nil {
+		return nil, err
+	}
+
+	if data.ContractFees, err = s.Fees.compile(parts); err != nil {
+		return nil, err
+	}
+
+	if data.ExpenseRatio, err = compileExpenseRatio(s.ExpenseRatio); err != nil {
+		return nil, err
+	}
+
+	if data.TokenCompliance, err = compileCompliance(s.Compliance); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// parseVersion converts major.minor.patch to the suggested contract version number (1.1.0 = 101000)
+func parseVersion(version, fallback string) (uint64, error) {
+	if version == "" {
+		version = fallback
+	}
+
+	elements := strings.Split(version, ".")
+	if len(elements) != 3 {
+		return 0, fmt.Errorf("invalid version %q (expected major.minor.patch)", version)
+	}
+
+	var numbers [3]uint64
+	for i, element := range elements {
+		number, err := strconv.ParseUint(element, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid version %q", version)
+		}
+		numbers[i] = number
+	}
+
+	if numbers[1] > 99 || numbers[2] > 999 {
+		return 0, fmt.Errorf("invalid version %q (minor is at most 99, patch at most 999)", version)
+	}
+
+	return numbers[0]*100000 + numbers[1]*1000 + numbers[2], nil
+}
+
+// toParts converts full coins to parts, amounts that are not a whole number of parts are an error
+func toParts(amount json.Number, parts *big.Int, field string) (string, error) {
+	value, err := convert.ParseAmount(amount.String(), parts)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", field, err)
+	}
+
+	return value.PartsString(), nil
+}
+
+// toDecimal parses a plain decimal (percentages and currency equivalents), an empty number is zero
+func toDecimal(value json.Number, field string) (convert.Amount, error) {
+	if value == "" {
+		return convert.Amount{}, nil
+	}
+
+	decimal, err := convert.ParseDecimal(value.String())
+	if err != nil {
+		return convert.Amount{}, fmt.Errorf("%s: %v", field, err)
+	}
+
+	return decimal, nil
+}
+
+func (g *GovernanceSpec) compile() (*pb.Governance, error) {
+	if g == nil {
+		return nil, nil
+	}
+
+	helperType := GovernanceTypeHelper{}
+	switch strings.ToLower(g.Type) {
+	case "staged":
+		helperType.Type = Staged
+	case "cycle":
+		helperType.Type = Cycle
+	case "staggered":
+		helperType.Type = Staggared
+	case "adaptive":
+		helperType.Type = Adaptive
+	default:
+		return nil, fmt.Errorf("governance: unknown type %q", g.Type)
+	}
+
+	if g.ProposalPeriod != nil {
+		unit, err := parsePeriodType(g.ProposalPeriod.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("governance: %v", err)
+		}
+		helperType.ProposalPeriod = &ProposalPeriod{PeriodType: unit, VotingPeriod: g.ProposalPeriod.Voting}
+	}
+
+	if g.Start != nil {
+		helperType.StartTimestamp = timestamppb.New(*g.Start)
+	}
+
+	if helperType.Type != Staged && len(g.Stages) > 0 {
+		return nil, errors.New("governance: stages are only used by staged governance")
+	}
+
+	for i, stage := range g.Stages {
+		unit, err := parsePeriodType(stage.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("governance: stages[%d]: %v", i, err)
+		}
+		helperType.Stages = append(helperType.Stages, &Stage{PeriodType: unit, Length: stage.Length, Break: stage.Break, MaxApproved: stage.MaxApproved})
+	}
+
+	governance, err := CreateGovernance(helperType, g.RegularQuorum, g.FastQuorum, g.ProposalInstruments, g.VotingInstruments, g.Threshold, g.AlwaysWinner, g.AllowMulti)
+	if err != nil {
+		return nil, fmt.Errorf("governance: %v", err)
+	}
+
+	return governance, nil
+}
+
+func parsePeriodType(unit string) (ProposalPeriodType, error) {
+	switch strings.ToLower(unit) {
+	case "days":
+		return Days, nil
+	case "months":
+		return Months, nil
+	}
+	return 0, fmt.Errorf("unknown period unit %q (expected days or months)", unit)
+}
+
+func compileKeys(keys []KeySpec) ([]*pb.RestrictedKey, error) {
+	var configs []RestrictedConfig
+	for i, key := range keys {
+		config := RestrictedConfig{TimeDelay: key.TimeDelay, Global: key.Global, KeyWeight: key.KeyWeight}
+
+		set := 0
+		if key.PublicKey != "" {
+			set++
+			publicKey := key.PublicKey
+			switch {
+			case strings.HasPrefix(publicKey, "gov_"):
+				governance := strings.TrimPrefix(publicKey, "gov_")
+				config.PublicKey.Governance = &governance
+			case strings.HasPrefix(publicKey, "sc_"):
+				name, instance, ok := cutLast(strings.TrimPrefix(publicKey, "sc_"), "_")
+				number, err := strconv.ParseUint(instance, 10, 32)
+				if !ok || err != nil {
+					return nil, fmt.Errorf("restrictedKeys[%d]: invalid smart contract key %q (expected sc_<name>_<instance>)", i, publicKey)
+				}
+				config.PublicKey.SmartContract = &helper.SmartContractHelper{Name: name, Instance: uint32(number)}
+			default:
+				config.PublicKey.Single = &publicKey
+			}
+		}
+		if key.Inheritence != "" {
+			set++
+			inheritence := key.Inheritence
+			config.PublicKey.Inheritence = &inheritence
+		}
+		if key.Governance != "" {
+			set++
+			governance := key.Governance
+			config.PublicKey.Governance = &governance
+		}
+		if key.SmartContract != nil {
+			set++
+			config.PublicKey.SmartContract = key.SmartContract
+		}
+		if key.Multi != nil {
+			set++
+			multi := &helper.MultiKeyHelper{MultiKey: key.Multi.Keys, Pattern: key.Multi.Patterns}
+			for _, token := range key.Multi.HashTokens {
+				hashType, err := helper.ParseHashType(token)
+				if err != nil {
+					return nil, fmt.Errorf("restrictedKeys[%d]: %v", i, err)
+				}
+				multi.HashTokens = append(multi.HashTokens, hashType)
+			}
+			config.PublicKey.Multi = multi
+		}
+
+		if set != 1 {
+			return nil, fmt.Errorf("restrictedKeys[%d]: exactly one of publicKey, inheritence, governance, smartContract or multi is required", i)
+		}
+
+		for _, permission := range key.Permissions {
+			switch permission {
+			case "update_contract":
+				config.UpdateContract = true
+			case "transfer":
+				config.Transfer = true
+			case "quash":
+				config.Quash = true
+			case "mint":
+				config.Mint = true
+			case "propose":
+				config.Propose = true
+			case "vote":
+				config.Vote = true
+			case "compliance":
+				config.Compliance = true
+			case "expense_ratio":
+				config.ExpenseRatio = true
+			case "cur_equiv":
+				config.CurEquiv = true
+			case "revoke":
+				config.Revoke = true
+			default:
+				return nil, fmt.Errorf("restrictedKeys[%d]: unknown permission %q (one of %s)", i, permission, strings.Join(Permissions, ", "))
+			}
+		}
+
+		configs = append(configs, config)
+	}
+
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	return CreateRestrictedKeys(configs)
+}
+
+func cutLast(s, sep string) (string, string, bool) {
+	if i := strings.LastIndex(s, sep); i != -1 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+func (f *FeeSpec) compile(parts *big.Int) (*pb.ContractFees, error) {
+	if f == nil {
+		return nil, nil
+	}
+
+	config := ContractFeeConfig{
+		Address:              f.Address,
+		AllowedFeeInstrument: f.AllowedInstruments,
+	}
+
+	switch strings.ToLower(f.Type) {
+	case "fixed":
+		config.Type = FeeFixed
+	case "currency_equivalent":
+		config.Type = FeeCurrencyEquivalent
+	case "percentage":
+		config.Type = FeePercentage
+	default:
+		return nil, fmt.Errorf("fees: unknown type %q (expected fixed, currency_equivalent or percentage)", f.Type)
+	}
+
+	if config.Type == FeeFixed && parts == nil {
+		return nil, errors.New("fees: fixed fees require the parts per coin")
+	}
+
+	var err error
+	if config.Fee, err = toDecimal(f.Fee, "fees.fee"); err != nil {
+		return nil, err
+	}
+
+	if config.Burn, err = toDecimal(f.Burn, "fees.burn"); err != nil {
+		return nil, err
+	}
+
+	if config.Validator, err = toDecimal(f.Validator, "fees.validator"); err != nil {
+		return nil, err
+	}
+
+	fees, err := CreateContractFee(config, parts)
+	if err != nil {
+		return nil, fmt.Errorf("fees: %v", err)
+	}
+
+	return fees, nil
+}
+
+func compileExpenseRatio(ratios []ExpenseSpec) ([]*pb.ExpenseRatio, error) {
+	if len(ratios) == 0 {
+		return nil, nil
+	}
+
+	var configs []ExpenseRatioConfig
+	for _, ratio := range ratios {
+		configs = append(configs, ExpenseRatioConfig{Month: ratio.Month, Day: ratio.Day, Percent: ratio.Percent})
+	}
+
+	return CreateExpenseRatio(configs)
+}
+
+func compileCompliance(compliance [][]ComplianceConfigSpec) ([]*pb.TokenCompliance, error) {
+	if len(compliance) == 0 {
+		return nil, nil
+	}
+
+	var configs [][]ComplianceConfig
+	for _, outer := range compliance {
+		var inner []ComplianceConfig
+		for _, entry := range outer {
+			inner = append(inner, ComplianceConfig{ContractID: entry.ContractId, Level: entry.Level})
+		}
+		configs = append(configs, inner)
+	}
+
+	return CreateCompliance(configs)
+}
@@ -0,0 +1,94 @@
+package contract_test
+
+import (
+	"testing"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/contract"
+)
+
+func TestLoadTokenData(t *testing.T) {
+	data, err := contract.LoadTokenData("testdata/token.yaml")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if data.Type != pb.CONTRACT_TYPE_TOKEN || data.ContractVersion != 100000 || data.Denomination.Amount != "1000000000" {
+		t.Errorf("Unexpected contract identity %+v", data)
+	}
+
+	// amounts are converted exactly, not through float64
+	if data.MaxSupply != "123456789000000" {
+		t.Errorf("Expected max supply 123456789000000, got %s", data.MaxSupply)
+	}
+
+	if len(data.MaxSupplyRelease) != 2 || data.MaxSupplyRelease[0].Amount != "61728394500000" {
+		t.Errorf("Unexpected release schedule %v", data.MaxSupplyRelease)
+	}
+
+	if len(data.Premint) != 2 || data.Premint[0].Amount != "123456000000" {
+		t.Errorf("Unexpected premint %v", data.Premint)
+	}
+
+	if len(data.RestrictedKeys) != 2 || !data.RestrictedKeys[0].Mint || data.RestrictedKeys[1].PublicKey.GovernanceAuth == nil {
+		t.Errorf("Unexpected restricted keys %v", data.RestrictedKeys)
+	}
+
+	if data.Governance.GetType() != pb.GOVERNANCE_TYPE_STAGGERED || data.ContractFees.GetBurn() != "395000000000000000" {
+		t.Errorf("Unexpected governance or fees %v %v", data.Governance, data.ContractFees)
+	}
+
+	if len(data.TokenCompliance) != 2 || len(data.CustomParameters) != 1 || !data.ImmutableKycStatus {
+		t.Errorf("Unexpected compliance or parameters %v %v", data.TokenCompliance, data.CustomParameters)
+	}
+}
+
+func TestTokenSpecErrors(t *testing.T) {
+	base := `{"contractId": "$TEST+0000", "symbol": "TEST", "name": "Test", "denomination": {"parts": 1000, "name": "part"}, `
+
+	tests := map[string]string{
+		"unknown field":      base + `"maxSuply": 10}`,
+		"sub part amount":    base + `"maxSupply": 0.0001}`,
+		"release mismatch":   base + `"maxSupply": 10, "releases": [{"date": "2026-01-01T00:00:00Z", "amount": 9}]}`,
+		"unknown permission": base + `"restrictedKeys": [{"publicKey": "r_A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7", "permissions": ["admin"]}]}`,
+		"nft premint":        `{"type": "nft", "contractId": "$NFT+0000", "symbol": "NFT", "name": "Test", "premint": [{"address": "8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR", "amount": 1}]}`,
+	}
+
+	for name, spec := range tests {
+		parsed, err := contract.ParseTokenSpec([]byte(spec))
+		if err == nil {
+			_, err = parsed.TokenData()
+		}
+
+		if err == nil {
+			t.Errorf("%s: expected an error, got none", name)
+		}
+	}
+}
+
+func TestUpdateSpec(t *testing.T) {
+	spec, err := contract.ParseUpdateSpec([]byte(`
+contractId: $TEST+0000
+version: 1.0.1
+parts: 1000000000
+name: New Test Token
+kycStatus: false
+fees: {type: fixed, address: 8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR, fee: 0.000000001, burn: 50, validator: 50}
+`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, err := spec.UpdateData()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if data.ContractVersion != 100001 || *data.Name != "New Test Token" || data.KycStatus == nil || *data.KycStatus {
+		t.Errorf("Unexpected update %+v", data)
+	}
+
+	if data.ContractFees.Fee != "1" {
+		t.Errorf("Expected a fixed fee of 1 part, got %s", data.ContractFees.Fee)
+	}
+}
@@ -4,21 +4,22 @@ import (
 	"context"
 	"fmt"
 	"math/big"
-	"strings"
 	"time"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/convert"
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
-	"github.com/ZeraVision/zera-go-sdk/wallet"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// currencyEquivalentScale scales a currency equivalent value (ie $1.23) to the 1e18 scale of the network
+var currencyEquivalentScale = big.NewInt(1e18)
+
 type TokenData struct {
 	Type               pb.CONTRACT_TYPE       // Type of token (token, nft, sbt)
 	ContractVersion    uint64                 // [suggested] major (as needed) - minor (x2) - patch (x3) (ie 1.1.0 = 101000 = 1 | 01 | 000)
@@ -41,17 +42,27 @@ type TokenData struct {
 	TokenCompliance    []*pb.TokenCompliance  // Token compliance configuration (most contracts don't use this)
 	KycStatus          bool                   // If true, this contract requires KYC (compliance status) to transact.
 	ImmutableKycStatus bool                   // If true, this contract cannot change its requirement of KYC status later.
-	CurEquivStart      *float64               // A starter version of "SelfCurrencyEquiv" that can set initial on chain rate, pass in as float64
+	CurEquivStart      *convert.Amount        // A starter version of "SelfCurrencyEquiv" that can set initial on chain rate, ie convert.MustParseDecimal("1.23")
 }
 
 func CreateContractTXN(nonceInfo nonce.NonceInfo, data *TokenData, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.InstrumentContract, error) {
+	return CreateContractTXNWithContext(context.Background(), nonceInfo, data, publicKeyBase58, privateKeyBase58, feeID, feeAmountParts)
+}
+
+// CreateContractTXNWithContext is CreateContractTXN with a context that is passed through to the nonce lookup.
+func CreateContractTXNWithContext(ctx context.Context, nonceInfo nonce.NonceInfo, data *TokenData, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.InstrumentContract, error) {
+	return CreateContractTXNWithSigner(ctx, nonceInfo, data, helper.NewPrivateKeySigner(publicKeyBase58, privateKeyBase58), feeID, feeAmountParts)
+}
+
+// CreateContractTXNWithSigner is CreateContractTXN with the transaction signed by signer instead of a base58 private key.
+func CreateContractTXNWithSigner(ctx context.Context, nonceInfo nonce.NonceInfo, data *TokenData, signer helper.Signer, feeID string, feeAmountParts string) (*pb.InstrumentContract, error) {
 	// Step 1: Decode public key
-	_, _, pubKeyBytes, err := transcode.Base58DecodePublicKey(publicKeyBase58)
+	txnPublicKey, err := helper.TxnPublicKey(signer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode public key: %v", err)
 	}
 
-	nonce, err := nonce.GetNonce(nonceInfo, 5)
+	nonce, err := nonce.GetNonceWithContext(ctx, nonceInfo, nonce.DefaultConcurrency)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nonce: %v", err)
@@ -63,9 +74,7 @@ func CreateContractTXN(nonceInfo nonce.NonceInfo, data *TokenData, publicKeyBase
 
 	// Step 2: Create BaseTXN
 	base := &pb.BaseTXN{
-		PublicKey: &pb.PublicKey{
-			Single: pubKeyBytes,
-		},
+		PublicKey: txnPublicKey,
 		FeeId:     feeID,
 		FeeAmount: feeAmountParts,
 		Timestamp: timestamppb.New(time.Now().UTC()),
@@ -76,14 +85,13 @@ func CreateContractTXN(nonceInfo nonce.NonceInfo, data *TokenData, publicKeyBase
 	var startCurequivStr *string
 
 	if data.CurEquivStart != nil {
-		if *data.CurEquivStart < 0 {
-			return nil, fmt.Errorf("CurEquivStart must be greater than 0")
-		}
-
 		// Convert to format network expects (1e18 scale)
-		startCurequiv := new(big.Float).Mul(big.NewFloat(*data.CurEquivStart), big.NewFloat(1e18))
+		startCurequiv, err := data.CurEquivStart.PartsAt(currencyEquivalentScale)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CurEquivStart: %v", err)
+		}
 
-		startCurequivStrValue := startCurequiv.Text('f', 0)
+		startCurequivStrValue := startCurequiv.String()
 		startCurequivStr = &startCurequivStrValue
 	}
 
@@ -125,12 +133,7 @@ func CreateContractTXN(nonceInfo nonce.NonceInfo, data *TokenData, publicKeyBase
 		return nil, fmt.Errorf("failed to serialize contract transaction: %v", err)
 	}
 
-	keyType, err := wallet.DetermineKeyType(publicKeyBase58)
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine key type: %v", err)
-	}
-
-	signature, err := helper.Sign(privateKeyBase58, byteDataNoSig, keyType)
+	signature, err := helper.SignWith(signer, byteDataNoSig)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign contract transaction: %v", err)
@@ -154,21 +157,26 @@ func CreateContractTXN(nonceInfo nonce.NonceInfo, data *TokenData, publicKeyBase
 
 // SendInstrumentContract submits an instrument contract to the network via gRPC
 func SendInstrumentContract(grpcAddr string, txn *pb.InstrumentContract) (*emptypb.Empty, error) {
+	return SendInstrumentContractWithContext(context.Background(), grpcAddr, txn)
+}
 
-	if !strings.Contains(grpcAddr, ":") {
-		grpcAddr += ":50052"
-	}
+// SendInstrumentContractWithContext is SendInstrumentContract with a context that is passed through to the gRPC call.
+func SendInstrumentContractWithContext(ctx context.Context, grpcAddr string, txn *pb.InstrumentContract) (*emptypb.Empty, error) {
+	return SendInstrumentContractWithSecurity(ctx, grpcAddr, txn, nil)
+}
 
-	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// SendInstrumentContractWithSecurity is SendInstrumentContractWithContext with the validator dialed using security (plaintext if nil).
+func SendInstrumentContractWithSecurity(ctx context.Context, grpcAddr string, txn *pb.InstrumentContract, security *helper.TransportSecurity) (*emptypb.Empty, error) {
+	conn, err := helper.NewConnection(grpcAddr, "50052", security)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 
 	client := pb.NewTXNServiceClient(conn)
-	response, err := client.Contract(context.Background(), txn)
+	response, err := client.Contract(ctx, txn)
 	if err != nil {
-		return nil, fmt.Errorf("token transaction failed: %v", err)
+		return nil, fmt.Errorf("token transaction failed: %w", zeraerr.FromGRPC(err))
 	}
 
 	return response, nil
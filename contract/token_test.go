@@ -1,6 +1,7 @@
 package contract_test
 
 import (
+	"math/big"
 	"testing"
 	"time"
 
@@ -49,7 +50,7 @@ func TestTokenCreation(t *testing.T) {
 	var quashThreshold *uint32 = nil
 	kycStatus := false
 	immutableKycStatus := true
-	var curEquivalentStart *float64 = nil
+	var curEquivalentStart *convert.Amount = nil
 
 	// Core token data object
 	tokenData := &contract.TokenData{
@@ -120,21 +121,30 @@ func TestTokenCreation(t *testing.T) {
 	}
 
 	// Required: Total supply cap
-	maxSupply := 123456.789
+	maxSupply, err := convert.ParseAmount("123456.789", bigParts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
 	tokenData.MaxSupply, err = contract.CreateMaxSupply(maxSupply, bigParts)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
 	// Optional: Scheduled release of max supply over time
+	halfSupply, err := maxSupply.Quo(big.NewRat(2, 1), convert.RoundExact)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
 	releaseConfig := []contract.ReleaseScheduleConfig{
 		{
 			ReleaseDate: timestamppb.New(time.Now()),
-			Amount:      maxSupply / 2,
+			Amount:      halfSupply,
 		},
 		{
 			ReleaseDate: timestamppb.New(time.Now().AddDate(0, 6, 0)),
-			Amount:      maxSupply / 2,
+			Amount:      halfSupply,
 		},
 	}
 
@@ -147,9 +157,9 @@ func TestTokenCreation(t *testing.T) {
 	feeConfig := contract.ContractFeeConfig{
 		Type:      contract.FeeCurrencyEquivalent,
 		Address:   fromAddr,
-		Fee:       0.20,
-		Burn:      39.5,
-		Validator: 10.5,
+		Fee:       convert.MustParseDecimal("0.20"),
+		Burn:      convert.MustParseDecimal("39.5"),
+		Validator: convert.MustParseDecimal("10.5"),
 		AllowedFeeInstrument: []string{
 			"$TEST+0000",
 			"TEST+0001",
@@ -194,8 +204,8 @@ func TestTokenCreation(t *testing.T) {
 
 	// Optional: Premint tokens to specific accounts
 	premintConfig := []contract.PremintConfig{
-		{Address: fromAddr, Amount: 123.456},
-		{Address: "Hv3KUwrmR8C8XVSxuJFJrQqeDixeDnakUTkUUMZkFCUS", Amount: 789},
+		{Address: fromAddr, Amount: convert.MustParseDecimal("123.456")},
+		{Address: "Hv3KUwrmR8C8XVSxuJFJrQqeDixeDnakUTkUUMZkFCUS", Amount: convert.MustParseDecimal("789")},
 	}
 
 	tokenData.Premint, err = contract.CreatePremint(premintConfig, bigParts)
@@ -3,16 +3,13 @@ package contract
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
-	"github.com/ZeraVision/zera-go-sdk/wallet"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -35,17 +32,29 @@ type UpdateData struct {
 }
 
 func UpdateContractTXN(nonceInfo nonce.NonceInfo, data *UpdateData, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.ContractUpdateTXN, error) {
+	return UpdateContractTXNWithContext(context.Background(), nonceInfo, data, publicKeyBase58, privateKeyBase58, feeID, feeAmountParts)
+}
+
+// UpdateContractTXNWithContext is UpdateContractTXN with a context that is passed through to the nonce lookup.
+func UpdateContractTXNWithContext(ctx context.Context, nonceInfo nonce.NonceInfo, data *UpdateData, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.ContractUpdateTXN, error) {
+	return UpdateContractTXNWithSigner(ctx, nonceInfo, data, helper.NewPrivateKeySigner(publicKeyBase58, privateKeyBase58), feeID, feeAmountParts)
+}
+
+// UpdateContractTXNWithSigner is UpdateContractTXN with the transaction signed by signer instead of a base58 private key.
+func UpdateContractTXNWithSigner(ctx context.Context, nonceInfo nonce.NonceInfo, data *UpdateData, signer helper.Signer, feeID string, feeAmountParts string) (*pb.ContractUpdateTXN, error) {
+	publicKeyBase58 := signer.PublicKey()
+
 	// Step 1: Decode public key
-	prefix, _, pubKeyBytes, err := transcode.Base58DecodePublicKey(publicKeyBase58)
+	txnPublicKey, err := helper.TxnPublicKey(signer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode public key: %v", err)
 	}
 
-	if !strings.HasPrefix(string(prefix), "r_") {
+	if !helper.IsRestricted(txnPublicKey) {
 		return nil, fmt.Errorf("public key %s is not a restricted key", publicKeyBase58)
 	}
 
-	nonce, err := nonce.GetNonce(nonceInfo, 5)
+	nonce, err := nonce.GetNonceWithContext(ctx, nonceInfo, nonce.DefaultConcurrency)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nonce: %v", err)
@@ -57,9 +66,7 @@ func UpdateContractTXN(nonceInfo nonce.NonceInfo, data *UpdateData, publicKeyBas
 
 	// Step 2: Create BaseTXN
 	base := &pb.BaseTXN{
-		PublicKey: &pb.PublicKey{
-			Single: pubKeyBytes,
-		},
+		PublicKey: txnPublicKey,
 		FeeId:     feeID,
 		FeeAmount: feeAmountParts,
 		Timestamp: timestamppb.New(time.Now().UTC()),
@@ -90,12 +97,7 @@ func UpdateContractTXN(nonceInfo nonce.NonceInfo, data *UpdateData, publicKeyBas
 		return nil, fmt.Errorf("failed to serialize contract transaction: %v", err)
 	}
 
-	keyType, err := wallet.DetermineKeyType(publicKeyBase58)
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine key type: %v", err)
-	}
-
-	signature, err := helper.Sign(privateKeyBase58, byteDataNoSig, keyType)
+	signature, err := helper.SignWith(signer, byteDataNoSig)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign contract transaction: %v", err)
@@ -119,21 +121,26 @@ func UpdateContractTXN(nonceInfo nonce.NonceInfo, data *UpdateData, publicKeyBas
 
 // SendUpdate submits an instrument contract to the network via gRPC
 func SendUpdate(grpcAddr string, txn *pb.ContractUpdateTXN) (*emptypb.Empty, error) {
+	return SendUpdateWithContext(context.Background(), grpcAddr, txn)
+}
 
-	if !strings.Contains(grpcAddr, ":") {
-		grpcAddr += ":50052"
-	}
+// SendUpdateWithContext is SendUpdate with a context that is passed through to the gRPC call.
+func SendUpdateWithContext(ctx context.Context, grpcAddr string, txn *pb.ContractUpdateTXN) (*emptypb.Empty, error) {
+	return SendUpdateWithSecurity(ctx, grpcAddr, txn, nil)
+}
 
-	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// SendUpdateWithSecurity is SendUpdateWithContext with the validator dialed using security (plaintext if nil).
+func SendUpdateWithSecurity(ctx context.Context, grpcAddr string, txn *pb.ContractUpdateTXN, security *helper.TransportSecurity) (*emptypb.Empty, error) {
+	conn, err := helper.NewConnection(grpcAddr, "50052", security)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 
 	client := pb.NewTXNServiceClient(conn)
-	response, err := client.ContractUpdate(context.Background(), txn)
+	response, err := client.ContractUpdate(ctx, txn)
 	if err != nil {
-		return nil, fmt.Errorf("token transaction failed: %v", err)
+		return nil, fmt.Errorf("token transaction failed: %w", zeraerr.FromGRPC(err))
 	}
 
 	return response, nil
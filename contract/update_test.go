@@ -7,6 +7,7 @@ import (
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
 	"github.com/ZeraVision/zera-go-sdk/contract"
+	"github.com/ZeraVision/zera-go-sdk/convert"
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
 	"github.com/ZeraVision/zera-go-sdk/testvars"
@@ -110,9 +111,9 @@ func TestTokenUpdate(t *testing.T) {
 	feeConfig := contract.ContractFeeConfig{
 		Type:      contract.FeeCurrencyEquivalent,
 		Address:   fromAddr,
-		Fee:       0.20,
-		Burn:      39.5,
-		Validator: 10.5,
+		Fee:       convert.MustParseDecimal("0.20"),
+		Burn:      convert.MustParseDecimal("39.5"),
+		Validator: convert.MustParseDecimal("10.5"),
 		AllowedFeeInstrument: []string{
 			"$TEST+0000",
 			"TEST+0001",
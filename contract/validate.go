@@ -0,0 +1,425 @@
+package contract
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"google.golang.org/protobuf/proto"
+)
+
+type Severity int
+
+const (
+	SeverityError   Severity = iota // the network will reject the contract
+	SeverityWarning                 // accepted, but likely not what was intended
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Finding is one rule violation found by Validate / ValidateUpdate.
+type Finding struct {
+	Severity Severity
+	Rule     string // ie contract-id, fee-split, release-schedule
+	Field    string // path of the offending field, ie RestrictedKeys[1].KeyWeight
+	Message  string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s (%s): %s", f.Severity, f.Field, f.Rule, f.Message)
+}
+
+type Findings []Finding
+
+// Errors returns the findings with SeverityError.
+func (f Findings) Errors() Findings {
+	var errs Findings
+	for _, finding := range f {
+		if finding.Severity == SeverityError {
+			errs = append(errs, finding)
+		}
+	}
+	return errs
+}
+
+// Err returns an error listing every SeverityError finding, or nil if there are none.
+func (f Findings) Err() error {
+	errs := f.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(errs))
+	for i, finding := range errs {
+		messages[i] = finding.String()
+	}
+	return fmt.Errorf("contract has %d error(s): %s", len(errs), strings.Join(messages, "; "))
+}
+
+func (f *Findings) add(severity Severity, rule, field, format string, args ...interface{}) {
+	*f = append(*f, Finding{Severity: severity, Rule: rule, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+var contractIDPattern = regexp.MustCompile(`^\$([A-Z]+)\+(\d{4})$`)
+
+// hundredPercentParts is 100% of a fee or burn / validator percentage on the network, 1e18 parts (100 * partsPerPercent)
+var hundredPercentParts = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// Validate checks a TokenData for mistakes that would otherwise only show when the network rejects the
+// InstrumentContract (or silently strips a field). It does not contact the network.
+func Validate(data *TokenData) Findings {
+	var findings Findings
+
+	// Step 1: Identity
+	validateContractID(&findings, "ContractId", data.ContractId)
+	if match := contractIDPattern.FindStringSubmatch(data.ContractId); match != nil && data.Symbol != match[1] {
+		findings.add(SeverityWarning, "symbol", "Symbol", "symbol %q does not match contract id %s", data.Symbol, data.ContractId)
+	}
+
+	if data.Name == "" {
+		findings.add(SeverityError, "name", "Name", "name is required")
+	}
+
+	if data.ContractVersion == 0 {
+		findings.add(SeverityWarning, "version", "ContractVersion", "version 0 leaves no room below for updates, 1.0.0 is 100000")
+	}
+
+	// Step 2: Type specific fields, nft / sbt fields that CreateContractTXN strips
+	if data.Type == pb.CONTRACT_TYPE_TOKEN {
+		if data.Denomination == nil {
+			findings.add(SeverityError, "denomination", "Denomination", "tokens require a denomination")
+		} else if parts, ok := new(big.Int).SetString(data.Denomination.Amount, 10); !ok || parts.Sign() <= 0 {
+			findings.add(SeverityError, "denomination", "Denomination.Amount", "parts per coin must be a positive integer, got %q", data.Denomination.Amount)
+		}
+	} else {
+		if data.Denomination != nil {
+			findings.add(SeverityWarning, "stripped-field", "Denomination", "%s contracts have no denomination, it is removed from the transaction", data.Type)
+		}
+		if len(data.Premint) > 0 {
+			findings.add(SeverityWarning, "stripped-field", "Premint", "%s contracts can not premint, it is removed from the transaction", data.Type)
+		}
+		if data.CurEquivStart != nil {
+			findings.add(SeverityWarning, "stripped-field", "CurEquivStart", "%s contracts have no currency equivalent, it is removed from the transaction", data.Type)
+		}
+	}
+
+	if data.CurEquivStart != nil {
+		if _, err := data.CurEquivStart.PartsAt(currencyEquivalentScale); err != nil {
+			findings.add(SeverityError, "currency-equivalent", "CurEquivStart", "%v", err)
+		}
+	}
+
+	// Step 3: Supply, release schedule and premint
+	var maxSupply *big.Int
+	if data.MaxSupply != "" {
+		maxSupply = parseAmount(&findings, "MaxSupply", data.MaxSupply)
+	}
+
+	validateReleases(&findings, data.MaxSupplyRelease, maxSupply)
+
+	if data.Type == pb.CONTRACT_TYPE_TOKEN {
+		total := new(big.Int)
+		for i, premint := range data.Premint {
+			field := fmt.Sprintf("Premint[%d]", i)
+			if len(premint.Address) == 0 {
+				findings.add(SeverityError, "premint", field+".Address", "address is required")
+			}
+			if amount := parseAmount(&findings, field+".Amount", premint.Amount); amount != nil {
+				total.Add(total, amount)
+			}
+		}
+
+		if maxSupply != nil && total.Cmp(maxSupply) > 0 {
+			findings.add(SeverityError, "premint", "Premint", "premint total %s exceeds max supply %s", total, maxSupply)
+		}
+	}
+
+	// Step 4: Governance, keys, fees, expense ratio
+	validateGovernance(&findings, data.Governance)
+	validateRestrictedKeys(&findings, data.RestrictedKeys, data.QuashThreshold)
+	validateFees(&findings, data.ContractFees)
+	validateExpenseRatio(&findings, data.ExpenseRatio)
+	validateCompliance(&findings, data.TokenCompliance)
+
+	if len(data.ExpenseRatio) == 0 && data.UpdateExpenseRatio {
+		findings.add(SeverityWarning, "expense-ratio", "UpdateExpenseRatio", "expense ratio may be updated but none is set")
+	}
+
+	return findings
+}
+
+// ValidateUpdate checks an UpdateData like Validate. Only the fields that are set are checked.
+func ValidateUpdate(data *UpdateData) Findings {
+	var findings Findings
+
+	validateContractID(&findings, "ContractId", data.ContractId)
+
+	if data.ContractVersion == 0 {
+		findings.add(SeverityError, "version", "ContractVersion", "the version must be greater than the current version")
+	}
+
+	if data.Name != nil && *data.Name == "" {
+		findings.add(SeverityError, "name", "Name", "name can not be changed to empty")
+	}
+
+	validateGovernance(&findings, data.Governance)
+	if len(data.RestrictedKeys) > 0 {
+		validateRestrictedKeys(&findings, data.RestrictedKeys, data.QuashThreshold)
+	}
+	validateFees(&findings, data.ContractFees)
+	validateExpenseRatio(&findings, data.ExpenseRatio)
+	validateCompliance(&findings, data.TokenCompliance)
+
+	if data.ImmutableKycStatus != nil && *data.ImmutableKycStatus && data.KycStatus == nil {
+		findings.add(SeverityWarning, "kyc", "ImmutableKycStatus", "the kyc status is made immutable without setting it in the same update")
+	}
+
+	return findings
+}
+
+func validateContractID(findings *Findings, field, contractID string) {
+	if !contractIDPattern.MatchString(contractID) {
+		findings.add(SeverityError, "contract-id", field, "%q is not a contract id ($LETTERS+0000)", contractID)
+	}
+}
+
+func parseAmount(findings *Findings, field, amount string) *big.Int {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok || value.Sign() < 0 {
+		findings.add(SeverityError, "amount", field, "%q is not an amount in parts", amount)
+		return nil
+	}
+	return value
+}
+
+func validateReleases(findings *Findings, releases []*pb.MaxSupplyRelease, maxSupply *big.Int) {
+	if len(releases) == 0 {
+		return
+	}
+
+	if maxSupply == nil {
+		findings.add(SeverityError, "release-schedule", "MaxSupplyRelease", "a release schedule requires a max supply")
+	}
+
+	total := new(big.Int)
+	for i, release := range releases {
+		field := fmt.Sprintf("MaxSupplyRelease[%d]", i)
+		if amount := parseAmount(findings, field+".Amount", release.Amount); amount != nil {
+			total.Add(total, amount)
+		}
+
+		if release.ReleaseDate == nil {
+			findings.add(SeverityError, "release-schedule", field+".ReleaseDate", "release date is required")
+		} else if i > 0 && releases[i-1].ReleaseDate != nil && !release.ReleaseDate.AsTime().After(releases[i-1].ReleaseDate.AsTime()) {
+			findings.add(SeverityError, "release-schedule", field+".ReleaseDate", "releases must be in increasing date order")
+		}
+	}
+
+	if maxSupply != nil && total.Cmp(maxSupply) != 0 {
+		findings.add(SeverityError, "release-schedule", "MaxSupplyRelease", "releases add up to %s, max supply is %s", total, maxSupply)
+	}
+}
+
+func validateGovernance(findings *Findings, governance *pb.Governance) {
+	if governance == nil {
+		return
+	}
+
+	if governance.RegularQuorum > 10000 || governance.GetFastQuorum() > 10000 {
+		findings.add(SeverityError, "governance", "Governance", "quorum is above 100%%")
+	}
+
+	if governance.Threshold > 1000 {
+		findings.add(SeverityError, "governance", "Governance.Threshold", "threshold is above 100%%")
+	}
+
+	if len(governance.VotingInstrument) == 0 {
+		findings.add(SeverityError, "governance", "Governance.VotingInstrument", "no contract is allowed to vote")
+	}
+
+	for i, contractID := range governance.VotingInstrument {
+		validateContractID(findings, fmt.Sprintf("Governance.VotingInstrument[%d]", i), contractID)
+	}
+
+	for i, contractID := range governance.AllowedProposalInstrument {
+		validateContractID(findings, fmt.Sprintf("Governance.AllowedProposalInstrument[%d]", i), contractID)
+	}
+
+	periodic := governance.Type == pb.GOVERNANCE_TYPE_STAGED || governance.Type == pb.GOVERNANCE_TYPE_CYCLE || governance.Type == pb.GOVERNANCE_TYPE_STAGGERED
+
+	switch {
+	case periodic && (governance.ProposalPeriod == nil || governance.GetVotingPeriod() == 0):
+		findings.add(SeverityError, "governance-period", "Governance.ProposalPeriod", "%s governance requires a proposal period and voting period", governance.Type)
+	case !periodic && governance.ProposalPeriod != nil:
+		findings.add(SeverityError, "governance-period", "Governance.ProposalPeriod", "%s governance has no proposal period", governance.Type)
+	}
+
+	if (governance.Type == pb.GOVERNANCE_TYPE_STAGED || governance.Type == pb.GOVERNANCE_TYPE_CYCLE) && governance.StartTimestamp == nil {
+		findings.add(SeverityError, "governance-period", "Governance.StartTimestamp", "%s governance requires a start timestamp", governance.Type)
+	}
+
+	if governance.Type != pb.GOVERNANCE_TYPE_STAGED {
+		if len(governance.StageLength) > 0 {
+			findings.add(SeverityWarning, "governance-stage", "Governance.StageLength", "stages are only used by staged governance")
+		}
+		return
+	}
+
+	if len(governance.StageLength) == 0 {
+		findings.add(SeverityError, "governance-stage", "Governance.StageLength", "staged governance requires stages")
+	}
+
+	for i, stage := range governance.StageLength {
+		field := fmt.Sprintf("Governance.StageLength[%d]", i)
+
+		// a stage in months requires a proposal period in months
+		if stage.Period == pb.PROPOSAL_PERIOD_MONTHS && governance.GetProposalPeriod() != pb.PROPOSAL_PERIOD_MONTHS {
+			findings.add(SeverityError, "governance-stage", field+".Period", "stages in months require a proposal period in months")
+		}
+
+		if stage.Length == 0 {
+			findings.add(SeverityError, "governance-stage", field+".Length", "stage length must be greater than 0")
+		}
+
+		if !stage.Break && stage.MaxApproved == 0 {
+			findings.add(SeverityError, "governance-stage", field+".MaxApproved", "max approved must be greater than 0")
+		}
+	}
+}
+
+func validateRestrictedKeys(findings *Findings, keys []*pb.RestrictedKey, quashThreshold *uint32) {
+	seen := make(map[string]int)
+	quashKeys := 0
+	var updateKeys []*pb.RestrictedKey
+	var lowest *pb.RestrictedKey
+
+	for i, key := range keys {
+		field := fmt.Sprintf("RestrictedKeys[%d]", i)
+
+		if !restrictable(key.PublicKey) {
+			findings.add(SeverityError, "restricted-key", field+".PublicKey", "only r_, multi-key (r), gov_, sc_ and inherited keys can be restricted")
+		}
+
+		if id, err := (proto.MarshalOptions{Deterministic: true}).Marshal(key.GetPublicKey()); err == nil {
+			if previous, ok := seen[string(id)]; ok {
+				findings.add(SeverityError, "restricted-key", field+".PublicKey", "duplicate of RestrictedKeys[%d]", previous)
+			}
+			seen[string(id)] = i
+		}
+
+		if key.Quash {
+			quashKeys++
+		}
+
+		if key.UpdateContract {
+			updateKeys = append(updateKeys, key)
+		}
+
+		if lowest == nil || key.KeyWeight < lowest.KeyWeight {
+			lowest = key
+		}
+	}
+
+	if len(updateKeys) == 0 {
+		findings.add(SeverityWarning, "update-key", "RestrictedKeys", "no key has UpdateContract, the contract can never be updated")
+	}
+
+	// lower weight is more privileged, a key more privileged than every update key can not be removed by an update
+	if lowest != nil && len(updateKeys) > 0 && !lowest.UpdateContract {
+		minUpdate := updateKeys[0].KeyWeight
+		for _, key := range updateKeys {
+			if key.KeyWeight < minUpdate {
+				minUpdate = key.KeyWeight
+			}
+		}
+		if lowest.KeyWeight < minUpdate {
+			findings.add(SeverityWarning, "key-weight", "RestrictedKeys", "a key with weight %d is more privileged than every UpdateContract key (lowest weight %d) and can not be removed", lowest.KeyWeight, minUpdate)
+		}
+	}
+
+	if quashThreshold != nil && int(*quashThreshold) > quashKeys {
+		findings.add(SeverityError, "quash", "QuashThreshold", "threshold %d is above the %d quash key(s)", *quashThreshold, quashKeys)
+	}
+}
+
+func restrictable(publicKey *pb.PublicKey) bool {
+	switch {
+	case publicKey == nil:
+		return false
+	case publicKey.GovernanceAuth != nil || publicKey.SmartContractAuth != nil:
+		return true
+	case publicKey.Multi != nil:
+		return helper.IsRestricted(publicKey)
+	case contractIDPattern.Match(publicKey.Single):
+		return true // inherited keys of another contract
+	}
+	return helper.IsRestricted(publicKey)
+}
+
+func validateFees(findings *Findings, fees *pb.ContractFees) {
+	if fees == nil {
+		return
+	}
+
+	if len(fees.FeeAddress) == 0 {
+		findings.add(SeverityError, "fee", "ContractFees.FeeAddress", "fee address is required")
+	}
+
+	fee := parseAmount(findings, "ContractFees.Fee", fees.Fee)
+	if fee != nil && fees.ContractFeeType == pb.CONTRACT_FEE_TYPE_PERCENTAGE && fee.Cmp(hundredPercentParts) > 0 {
+		findings.add(SeverityError, "fee", "ContractFees.Fee", "percentage fee is above 100%%")
+	}
+
+	burn := parseAmount(findings, "ContractFees.Burn", fees.Burn)
+	validator := parseAmount(findings, "ContractFees.Validator", fees.Validator)
+	if burn != nil && validator != nil && new(big.Int).Add(burn, validator).Cmp(hundredPercentParts) > 0 {
+		findings.add(SeverityError, "fee-split", "ContractFees", "burn and validator percentages add up to more than 100%%")
+	}
+
+	if len(fees.AllowedFeeInstrument) == 0 {
+		findings.add(SeverityWarning, "fee", "ContractFees.AllowedFeeInstrument", "no instrument is allowed to pay the fee")
+	}
+
+	for i, contractID := range fees.AllowedFeeInstrument {
+		validateContractID(findings, fmt.Sprintf("ContractFees.AllowedFeeInstrument[%d]", i), contractID)
+	}
+}
+
+func validateExpenseRatio(findings *Findings, ratios []*pb.ExpenseRatio) {
+	for i, ratio := range ratios {
+		field := fmt.Sprintf("ExpenseRatio[%d]", i)
+
+		if ratio.Month < 1 || ratio.Month > 12 {
+			findings.add(SeverityError, "expense-ratio", field+".Month", "month must be 1-12")
+		}
+
+		if ratio.Day < 1 || ratio.Day > 31 {
+			findings.add(SeverityError, "expense-ratio", field+".Day", "day must be 1-31")
+		}
+
+		// percent is scaled by 10000
+		if ratio.Percent > 100*10000 {
+			findings.add(SeverityError, "expense-ratio", field+".Percent", "percent is above 100%%")
+		}
+	}
+}
+
+func validateCompliance(findings *Findings, compliance []*pb.TokenCompliance) {
+	for i, outer := range compliance {
+		if len(outer.Compliance) == 0 {
+			findings.add(SeverityWarning, "compliance", fmt.Sprintf("TokenCompliance[%d]", i), "an empty compliance group is satisfied by every wallet")
+		}
+
+		for j, inner := range outer.Compliance {
+			validateContractID(findings, fmt.Sprintf("TokenCompliance[%d].Compliance[%d].ContractId", i, j), inner.ContractId)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package contract_test
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/contract"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestValidate(t *testing.T) {
+	data, err := contract.LoadTokenData("testdata/token.yaml")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := contract.Validate(data).Err(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// break every rule once
+	data.ContractId = "TEST+0000"
+	data.Premint = append(data.Premint, &pb.PreMintWallet{Address: []byte{1}, Amount: "999999999999999999"})
+	data.MaxSupplyRelease[0].ReleaseDate, data.MaxSupplyRelease[1].ReleaseDate = data.MaxSupplyRelease[1].ReleaseDate, timestamppb.New(time.Unix(0, 0))
+	data.MaxSupplyRelease[1].Amount = "1"
+	data.ContractFees.Validator = "700000000000000000"
+	data.RestrictedKeys[0].PublicKey = &pb.PublicKey{Single: []byte("A_c_not_restricted")}
+	data.Governance.ProposalPeriod = nil
+	quash := uint32(2)
+	data.QuashThreshold = &quash
+
+	findings := contract.Validate(data)
+
+	rules := make(map[string]bool)
+	for _, finding := range findings.Errors() {
+		rules[finding.Rule] = true
+	}
+
+	for _, rule := range []string{"contract-id", "premint", "release-schedule", "fee-split", "restricted-key", "governance-period", "quash"} {
+		if !rules[rule] {
+			t.Errorf("Expected a %s finding, got %v", rule, findings)
+		}
+	}
+}
+
+func TestValidateStripped(t *testing.T) {
+	data := &contract.TokenData{
+		Type:            pb.CONTRACT_TYPE_NFT,
+		ContractVersion: 100000,
+		ContractId:      "$ART+0000",
+		Symbol:          "ART",
+		Name:            "Art",
+		Denomination:    &pb.CoinDenomination{Amount: "1"},
+	}
+
+	findings := contract.Validate(data)
+	if findings.Err() != nil {
+		t.Fatalf("Expected no error, got %v", findings.Err())
+	}
+
+	var stripped, update bool
+	for _, finding := range findings {
+		stripped = stripped || finding.Rule == "stripped-field"
+		update = update || finding.Rule == "update-key"
+	}
+
+	if !stripped || !update {
+		t.Errorf("Expected stripped-field and update-key warnings, got %v", findings)
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	days := pb.PROPOSAL_PERIOD_DAYS
+	voting := uint32(7)
+
+	findings := contract.ValidateUpdate(&contract.UpdateData{
+		ContractId:      "$TEST+0000",
+		ContractVersion: 100001,
+		Governance: &pb.Governance{
+			Type:             pb.GOVERNANCE_TYPE_STAGED,
+			VotingInstrument: []string{"$TEST+0000"},
+			ProposalPeriod:   &days,
+			VotingPeriod:     &voting,
+			StartTimestamp:   timestamppb.Now(),
+			StageLength:      []*pb.Stage{{Period: pb.PROPOSAL_PERIOD_MONTHS, Length: 1, MaxApproved: 1}},
+		},
+	})
+
+	if len(findings.Errors()) != 1 || findings.Errors()[0].Rule != "governance-stage" {
+		t.Errorf("Expected a governance-stage error, got %v", findings)
+	}
+}
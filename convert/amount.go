@@ -0,0 +1,390 @@
+package convert
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode decides what happens to a value that is not a whole number of parts
+type RoundingMode int
+
+const (
+	RoundExact    RoundingMode = iota // an error instead of losing precision
+	RoundDown                         // toward zero (truncate)
+	RoundUp                           // away from zero
+	RoundHalfUp                       // to the nearest part, ties away from zero
+	RoundHalfEven                     // to the nearest part, ties to the even part
+)
+
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundExact:
+		return "exact"
+	case RoundDown:
+		return "down"
+	case RoundUp:
+		return "up"
+	case RoundHalfUp:
+		return "half-up"
+	case RoundHalfEven:
+		return "half-even"
+	default:
+		return fmt.Sprintf("RoundingMode(%d)", int(m))
+	}
+}
+
+// ErrPrecision is returned when a value is not a whole number of parts and RoundExact is used
+var ErrPrecision = errors.New("precision would be lost")
+
+// ErrDenomination is returned by PartsIn for an amount at another denomination
+var ErrDenomination = errors.New("amount is at a different parts per coin")
+
+var decimalPattern = regexp.MustCompile(`^(\d+)(?:\.(\d+))?$`)
+
+// Amount is an exact, non-negative decimal amount of coins tied to a denomination (the parts per coin).
+// The zero value is zero and converts to any denomination.
+type Amount struct {
+	parts        *big.Int
+	partsPerCoin *big.Int
+}
+
+// NewAmount is an amount of parts at partsPerCoin
+func NewAmount(parts *big.Int, partsPerCoin *big.Int) (Amount, error) {
+	if partsPerCoin == nil || partsPerCoin.Sign() <= 0 {
+		return Amount{}, fmt.Errorf("invalid parts per coin %v", partsPerCoin)
+	}
+
+	if parts == nil || parts.Sign() < 0 {
+		return Amount{}, fmt.Errorf("invalid parts %v", parts)
+	}
+
+	return Amount{parts: new(big.Int).Set(parts), partsPerCoin: new(big.Int).Set(partsPerCoin)}, nil
+}
+
+// ParseAmount parses a decimal amount of full coins (ie "1.23") at partsPerCoin.
+// Values that are not a whole number of parts are an error.
+func ParseAmount(value string, partsPerCoin *big.Int) (Amount, error) {
+	return ParseAmountRounded(value, partsPerCoin, RoundExact)
+}
+
+// MustParseAmount is ParseAmount that panics on an invalid value, for literals
+func MustParseAmount(value string, partsPerCoin *big.Int) Amount {
+	amount, err := ParseAmount(value, partsPerCoin)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// ParseAmountRounded is ParseAmount with values that are not a whole number of parts rounded by mode
+func ParseAmountRounded(value string, partsPerCoin *big.Int, mode RoundingMode) (Amount, error) {
+	if partsPerCoin == nil || partsPerCoin.Sign() <= 0 {
+		return Amount{}, fmt.Errorf("invalid parts per coin %v", partsPerCoin)
+	}
+
+	coins, err := parseDecimal(value)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	parts, err := round(coins.Mul(coins, new(big.Rat).SetInt(partsPerCoin)), mode)
+	if err != nil {
+		return Amount{}, fmt.Errorf("%s is not a whole number of parts at %s parts per coin: %w", value, partsPerCoin, err)
+	}
+
+	return Amount{parts: parts, partsPerCoin: new(big.Int).Set(partsPerCoin)}, nil
+}
+
+// ParseDecimal parses a plain decimal (ie a percentage or a currency equivalent value) with a denomination of its
+// own precision, "12.345" is 12345 parts at 1000 parts per coin.
+func ParseDecimal(value string) (Amount, error) {
+	match := decimalPattern.FindStringSubmatch(value)
+	if match == nil {
+		return Amount{}, fmt.Errorf("invalid decimal %q", value)
+	}
+
+	parts, _ := new(big.Int).SetString(match[1]+match[2], 10)
+	return Amount{parts: parts, partsPerCoin: pow10(len(match[2]))}, nil
+}
+
+// MustParseDecimal is ParseDecimal that panics on an invalid value, for literals
+func MustParseDecimal(value string) Amount {
+	amount, err := ParseDecimal(value)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// AmountFromFloat converts a float64 through its shortest decimal representation (1.1 is "1.1", not
+// 1.100000000000000088817841970012523), values that are not a whole number of parts are an error.
+func AmountFromFloat(value float64, partsPerCoin *big.Int) (Amount, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+		return Amount{}, fmt.Errorf("invalid amount %v", value)
+	}
+
+	return ParseAmount(strconv.FormatFloat(value, 'f', -1, 64), partsPerCoin)
+}
+
+// Parts is the amount in parts
+func (a Amount) Parts() *big.Int {
+	if a.parts == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(a.parts)
+}
+
+// PartsString is the amount in parts as a base 10 string, as used by transactions
+func (a Amount) PartsString() string {
+	return a.Parts().String()
+}
+
+// PartsPerCoin is the denomination of the amount, nil for the zero value
+func (a Amount) PartsPerCoin() *big.Int {
+	if a.partsPerCoin == nil {
+		return nil
+	}
+	return new(big.Int).Set(a.partsPerCoin)
+}
+
+// Rat is the amount in full coins
+func (a Amount) Rat() *big.Rat {
+	if a.parts == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).SetFrac(a.parts, a.partsPerCoin)
+}
+
+func (a Amount) IsZero() bool {
+	return a.parts == nil || a.parts.Sign() == 0
+}
+
+// Cmp compares the values of a and b (in full coins), -1 if a < b, 0 if a == b and +1 if a > b
+func (a Amount) Cmp(b Amount) int {
+	return a.Rat().Cmp(b.Rat())
+}
+
+// Convert is the amount at another denomination, rounded by mode
+func (a Amount) Convert(partsPerCoin *big.Int, mode RoundingMode) (Amount, error) {
+	if partsPerCoin == nil || partsPerCoin.Sign() <= 0 {
+		return Amount{}, fmt.Errorf("invalid parts per coin %v", partsPerCoin)
+	}
+
+	value := a.Rat()
+	parts, err := round(value.Mul(value, new(big.Rat).SetInt(partsPerCoin)), mode)
+	if err != nil {
+		return Amount{}, fmt.Errorf("%s is not a whole number of parts at %s parts per coin: %w", a, partsPerCoin, err)
+	}
+
+	return Amount{parts: parts, partsPerCoin: new(big.Int).Set(partsPerCoin)}, nil
+}
+
+// PartsAt is the amount in parts at partsPerCoin, an error if it is not a whole number of parts
+func (a Amount) PartsAt(partsPerCoin *big.Int) (*big.Int, error) {
+	converted, err := a.Convert(partsPerCoin, RoundExact)
+	if err != nil {
+		return nil, err
+	}
+	return converted.parts, nil
+}
+
+// PartsIn is the amount in parts, which must already be at partsPerCoin (the zero value is at any denomination).
+// Unlike PartsAt, an amount at another denomination (ie convert.ParseDecimal("10")) is an error, not converted.
+func (a Amount) PartsIn(partsPerCoin *big.Int) (*big.Int, error) {
+	if partsPerCoin == nil || partsPerCoin.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid parts per coin %v", partsPerCoin)
+	}
+
+	if a.partsPerCoin != nil && a.partsPerCoin.Cmp(partsPerCoin) != 0 {
+		return nil, fmt.Errorf("%w: %s is at %s parts per coin, expected %s", ErrDenomination, a, a.partsPerCoin, partsPerCoin)
+	}
+
+	return a.PartsAt(partsPerCoin)
+}
+
+// Add is a + b, both amounts must have the same denomination
+func (a Amount) Add(b Amount) (Amount, error) {
+	partsPerCoin, err := commonDenomination(a, b)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	return Amount{parts: new(big.Int).Add(a.Parts(), b.Parts()), partsPerCoin: partsPerCoin}, nil
+}
+
+// Sub is a - b, both amounts must have the same denomination and the result may not be negative
+func (a Amount) Sub(b Amount) (Amount, error) {
+	partsPerCoin, err := commonDenomination(a, b)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	parts := new(big.Int).Sub(a.Parts(), b.Parts())
+	if parts.Sign() < 0 {
+		return Amount{}, fmt.Errorf("%s is less than %s", a, b)
+	}
+
+	return Amount{parts: parts, partsPerCoin: partsPerCoin}, nil
+}
+
+// Mul is a * factor in the denomination of a, rounded by mode
+func (a Amount) Mul(factor *big.Rat, mode RoundingMode) (Amount, error) {
+	if factor == nil || factor.Sign() < 0 {
+		return Amount{}, fmt.Errorf("invalid factor %v", factor)
+	}
+
+	parts, err := round(new(big.Rat).Mul(new(big.Rat).SetInt(a.Parts()), factor), mode)
+	if err != nil {
+		return Amount{}, fmt.Errorf("%s * %s is not a whole number of parts: %w", a, factor.RatString(), err)
+	}
+
+	return Amount{parts: parts, partsPerCoin: a.PartsPerCoin()}, nil
+}
+
+// Quo is a / divisor in the denomination of a, rounded by mode
+func (a Amount) Quo(divisor *big.Rat, mode RoundingMode) (Amount, error) {
+	if divisor == nil || divisor.Sign() <= 0 {
+		return Amount{}, fmt.Errorf("invalid divisor %v", divisor)
+	}
+
+	return a.Mul(new(big.Rat).Inv(divisor), mode)
+}
+
+// Format formats the amount in full coins with exactly decimals digits after the point, rounded by mode
+func (a Amount) Format(decimals int, mode RoundingMode) (string, error) {
+	if decimals < 0 {
+		return "", fmt.Errorf("invalid decimals %d", decimals)
+	}
+
+	value := a.Rat()
+	scaled, err := round(value.Mul(value, new(big.Rat).SetInt(pow10(decimals))), mode)
+	if err != nil {
+		return "", fmt.Errorf("%s has more than %d decimals: %w", a.String(), decimals, err)
+	}
+
+	digits := scaled.String()
+	if decimals == 0 {
+		return digits, nil
+	}
+
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	return digits[:len(digits)-decimals] + "." + digits[len(digits)-decimals:], nil
+}
+
+// String formats the amount in full coins without trailing zeros, exact unless the denomination has no finite
+// decimal representation (ie 3 parts per coin), which is rounded half even to 18 decimals
+func (a Amount) String() string {
+	decimals, exact := decimalPlaces(a.Rat().Denom())
+	if !exact {
+		decimals = 18
+	}
+
+	formatted, _ := a.Format(decimals, RoundHalfEven)
+	if !exact && strings.Contains(formatted, ".") {
+		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+	}
+
+	return formatted
+}
+
+// commonDenomination is the denomination shared by a and b, the zero value takes on the denomination of the other amount
+func commonDenomination(a, b Amount) (*big.Int, error) {
+	switch {
+	case a.partsPerCoin == nil:
+		return b.PartsPerCoin(), nil
+	case b.partsPerCoin == nil:
+		return a.PartsPerCoin(), nil
+	case a.partsPerCoin.Cmp(b.partsPerCoin) != 0:
+		return nil, fmt.Errorf("denominations differ (%s and %s parts per coin)", a.partsPerCoin, b.partsPerCoin)
+	default:
+		return a.PartsPerCoin(), nil
+	}
+}
+
+func parseDecimal(value string) (*big.Rat, error) {
+	if !decimalPattern.MatchString(value) {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+
+	coins, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+
+	return coins, nil
+}
+
+// round rounds value to an integer by mode
+func round(value *big.Rat, mode RoundingMode) (*big.Int, error) {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient, nil
+	}
+
+	away := false
+	switch mode {
+	case RoundExact:
+		return nil, ErrPrecision
+	case RoundDown:
+	case RoundUp:
+		away = true
+	case RoundHalfUp, RoundHalfEven:
+		// compare twice the remainder with the denominator to find the nearest integer
+		half := new(big.Int).Abs(remainder)
+		half.Lsh(half, 1)
+
+		switch half.Cmp(value.Denom()) {
+		case 1:
+			away = true
+		case 0:
+			away = mode == RoundHalfUp || quotient.Bit(0) == 1
+		}
+	default:
+		return nil, fmt.Errorf("unknown rounding mode %v", mode)
+	}
+
+	if away {
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	}
+
+	return quotient, nil
+}
+
+// decimalPlaces is the number of decimals needed to represent 1/denominator exactly, false if it never terminates
+func decimalPlaces(denominator *big.Int) (int, bool) {
+	remaining := new(big.Int).Set(denominator)
+	twos, fives := 0, 0
+
+	for remaining.Bit(0) == 0 && remaining.Sign() > 0 {
+		remaining.Rsh(remaining, 1)
+		twos++
+	}
+
+	five := big.NewInt(5)
+	for {
+		quotient, remainder := new(big.Int).QuoRem(remaining, five, new(big.Int))
+		if remainder.Sign() != 0 {
+			break
+		}
+		remaining = quotient
+		fives++
+	}
+
+	if remaining.Cmp(big.NewInt(1)) != 0 {
+		return 0, false
+	}
+
+	return max(twos, fives), true
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,196 @@
+package convert_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ZeraVision/zera-go-sdk/convert"
+)
+
+func TestParseAmount(t *testing.T) {
+	parts := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+	// 18 decimals and a supply beyond float64 precision are exact
+	amount, err := convert.ParseAmount("123456789012345678.123456789012345678", parts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if amount.PartsString() != "123456789012345678123456789012345678" {
+		t.Fatalf("Expected 123456789012345678123456789012345678 parts, got %s", amount.PartsString())
+	}
+
+	if amount.String() != "123456789012345678.123456789012345678" {
+		t.Fatalf("Expected the amount to format back to its input, got %s", amount.String())
+	}
+
+	// one decimal too many is an error instead of truncation
+	_, err = convert.ParseAmount("0.0000000001", big.NewInt(1_000_000_000))
+	if !errors.Is(err, convert.ErrPrecision) {
+		t.Fatalf("Expected precision error, got %v", err)
+	}
+
+	for _, invalid := range []string{"", "-1", "1e9", "1/3", "1.", ".5", "1,5", " 1"} {
+		if _, err := convert.ParseAmount(invalid, parts); err == nil {
+			t.Fatalf("Expected error for %q", invalid)
+		}
+	}
+
+	if _, err := convert.ParseAmount("1", big.NewInt(0)); err == nil {
+		t.Fatalf("Expected error for zero parts per coin")
+	}
+}
+
+func TestRounding(t *testing.T) {
+	tests := []struct {
+		value string
+		mode  convert.RoundingMode
+		parts string
+	}{
+		{"1.25", convert.RoundDown, "12"},
+		{"1.25", convert.RoundUp, "13"},
+		{"1.25", convert.RoundHalfUp, "13"},
+		{"1.25", convert.RoundHalfEven, "12"},
+		{"1.35", convert.RoundHalfEven, "14"},
+		{"1.26", convert.RoundHalfEven, "13"},
+		{"1.24", convert.RoundHalfUp, "12"},
+		{"1.2", convert.RoundExact, "12"},
+	}
+
+	for _, test := range tests {
+		amount, err := convert.ParseAmountRounded(test.value, big.NewInt(10), test.mode)
+		if err != nil {
+			t.Fatalf("Expected no error for %s %v, got %v", test.value, test.mode, err)
+		}
+
+		if amount.PartsString() != test.parts {
+			t.Fatalf("Expected %s rounded %v to be %s parts, got %s", test.value, test.mode, test.parts, amount.PartsString())
+		}
+	}
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	parts := big.NewInt(1_000_000_000)
+
+	a, err := convert.ParseAmount("1.5", parts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	b, err := convert.ParseAmount("0.000000001", parts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	sum, err := a.Add(b)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if sum.String() != "1.500000001" {
+		t.Fatalf("Expected 1.500000001, got %s", sum)
+	}
+
+	difference, err := sum.Sub(a)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if difference.Cmp(b) != 0 {
+		t.Fatalf("Expected %s, got %s", b, difference)
+	}
+
+	if _, err := b.Sub(a); err == nil {
+		t.Fatalf("Expected error for a negative result")
+	}
+
+	// a third of 1.5 is exact, a third of 1.500000001 is not
+	third, err := a.Quo(big.NewRat(3, 1), convert.RoundExact)
+	if err != nil || third.String() != "0.5" {
+		t.Fatalf("Expected 0.5, got %s (%v)", third, err)
+	}
+
+	if _, err := sum.Quo(big.NewRat(3, 1), convert.RoundExact); !errors.Is(err, convert.ErrPrecision) {
+		t.Fatalf("Expected precision error, got %v", err)
+	}
+
+	// denominations are not mixed implicitly
+	other, err := convert.ParseAmount("1", big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := a.Add(other); err == nil {
+		t.Fatalf("Expected error for different denominations")
+	}
+
+	converted, err := other.Convert(parts, convert.RoundExact)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if converted.PartsString() != "1000000000" {
+		t.Fatalf("Expected 1000000000 parts, got %s", converted.PartsString())
+	}
+
+	// PartsIn only takes amounts already at the denomination
+	if inParts, err := a.PartsIn(parts); err != nil || inParts.String() != "1500000000" {
+		t.Fatalf("Expected 1500000000 parts, got %v (%v)", inParts, err)
+	}
+
+	if _, err := other.PartsIn(parts); !errors.Is(err, convert.ErrDenomination) {
+		t.Fatalf("Expected denomination error, got %v", err)
+	}
+
+	if _, err := convert.MustParseDecimal("10").PartsIn(parts); !errors.Is(err, convert.ErrDenomination) {
+		t.Fatalf("Expected denomination error for a decimal, got %v", err)
+	}
+
+	// the zero value is zero at any denomination
+	var zero convert.Amount
+	if total, err := zero.Add(a); err != nil || total.Cmp(a) != 0 {
+		t.Fatalf("Expected %s, got %s (%v)", a, total, err)
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	percent, err := convert.ParseDecimal("39.5")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	scaled, err := percent.PartsAt(big.NewInt(1e16))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if scaled.String() != "395000000000000000" {
+		t.Fatalf("Expected 395000000000000000, got %s", scaled)
+	}
+
+	if _, err := convert.MustParseDecimal("0.0000000000000000001").PartsAt(big.NewInt(1e16)); !errors.Is(err, convert.ErrPrecision) {
+		t.Fatalf("Expected precision error, got %v", err)
+	}
+
+	// float64 values go through their shortest representation
+	fromFloat, err := convert.AmountFromFloat(0.1, big.NewInt(10))
+	if err != nil || fromFloat.PartsString() != "1" {
+		t.Fatalf("Expected 1 part, got %s (%v)", fromFloat.PartsString(), err)
+	}
+
+	formatted, err := convert.MustParseDecimal("2.345").Format(2, convert.RoundHalfEven)
+	if err != nil || formatted != "2.34" {
+		t.Fatalf("Expected 2.34, got %s (%v)", formatted, err)
+	}
+
+	// a denomination without a finite decimal representation
+	third, err := convert.NewAmount(big.NewInt(1), big.NewInt(3))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if third.String() != "0.333333333333333333" {
+		t.Fatalf("Expected 0.333333333333333333, got %s", third)
+	}
+}
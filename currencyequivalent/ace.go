@@ -4,15 +4,13 @@ package currencyequivalent
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -26,13 +24,25 @@ type AceData struct {
 }
 
 func CreateAceTxn(nonceInfo nonce.NonceInfo, data []AceData, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.AuthorizedCurrencyEquiv, error) {
+	return CreateAceTxnWithContext(context.Background(), nonceInfo, data, publicKeyBase58, privateKeyBase58, feeID, feeAmountParts)
+}
+
+// CreateAceTxnWithContext is CreateAceTxn with a context that is passed through to the nonce lookup.
+func CreateAceTxnWithContext(ctx context.Context, nonceInfo nonce.NonceInfo, data []AceData, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.AuthorizedCurrencyEquiv, error) {
+	return CreateAceTxnWithSigner(ctx, nonceInfo, data, helper.NewPrivateKeySigner(publicKeyBase58, privateKeyBase58), feeID, feeAmountParts)
+}
+
+// CreateAceTxnWithSigner is CreateAceTxn with the transaction signed by signer instead of a base58 private key.
+func CreateAceTxnWithSigner(ctx context.Context, nonceInfo nonce.NonceInfo, data []AceData, signer helper.Signer, feeID string, feeAmountParts string) (*pb.AuthorizedCurrencyEquiv, error) {
+	publicKeyBase58 := signer.PublicKey()
+
 	// Step 1: Decode public key
-	_, _, pubKeyBytes, err := transcode.Base58DecodePublicKey(publicKeyBase58)
+	txnPublicKey, err := helper.TxnPublicKey(signer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode public key: %v", err)
 	}
 
-	nonce, err := nonce.GetNonce(nonceInfo, 5)
+	nonce, err := nonce.GetNonceWithContext(ctx, nonceInfo, nonce.DefaultConcurrency)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nonce: %v", err)
@@ -44,9 +54,7 @@ func CreateAceTxn(nonceInfo nonce.NonceInfo, data []AceData, publicKeyBase58 str
 
 	// Step 2: Create BaseTXN
 	base := &pb.BaseTXN{
-		PublicKey: &pb.PublicKey{
-			Single: pubKeyBytes,
-		},
+		PublicKey: txnPublicKey,
 		FeeId:     feeID,
 		FeeAmount: feeAmountParts,
 		Timestamp: timestamppb.New(time.Now().UTC()),
@@ -78,30 +86,11 @@ func CreateAceTxn(nonceInfo nonce.NonceInfo, data []AceData, publicKeyBase58 str
 
 	// Step 5: Verify and determine key
 	// Check to ensure its a restricted key
-	if !strings.HasPrefix(publicKeyBase58, "r_") {
+	if !helper.IsRestricted(txnPublicKey) {
 		return nil, fmt.Errorf("public key is not a restricted key (r_): %s", publicKeyBase58)
 	}
 
-	// Find the substring between "r_" and the next "_"
-	pubBeyParts := strings.SplitN(publicKeyBase58, "_", 3) // Split into at most 3 parts
-	var keyLetter string
-	if len(pubBeyParts) > 2 {
-		keyLetter = pubBeyParts[1]
-	} else {
-		return nil, fmt.Errorf("invalid public key format: %s", publicKeyBase58)
-	}
-
-	// Ed25519
-	var keyType helper.KeyType
-	if keyLetter == "A" {
-		keyType = helper.ED25519
-	} else if keyLetter == "B" {
-		keyType = helper.ED448
-	} else {
-		return nil, fmt.Errorf("unknown key type for public key: %s", publicKeyBase58)
-	}
-
-	signature, err := helper.Sign(privateKeyBase58, byteDataNoSig, keyType)
+	signature, err := helper.SignWith(signer, byteDataNoSig)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign mint transaction: %v", err)
@@ -125,21 +114,26 @@ func CreateAceTxn(nonceInfo nonce.NonceInfo, data []AceData, publicKeyBase58 str
 }
 
 func SendAceTXN(grpcAddr string, txn *pb.AuthorizedCurrencyEquiv) (*emptypb.Empty, error) {
+	return SendAceTXNWithContext(context.Background(), grpcAddr, txn)
+}
 
-	if !strings.Contains(grpcAddr, ":") {
-		grpcAddr += ":50052"
-	}
+// SendAceTXNWithContext is SendAceTXN with a context that is passed through to the gRPC call.
+func SendAceTXNWithContext(ctx context.Context, grpcAddr string, txn *pb.AuthorizedCurrencyEquiv) (*emptypb.Empty, error) {
+	return SendAceTXNWithSecurity(ctx, grpcAddr, txn, nil)
+}
 
-	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// SendAceTXNWithSecurity is SendAceTXNWithContext with the validator dialed using security (plaintext if nil).
+func SendAceTXNWithSecurity(ctx context.Context, grpcAddr string, txn *pb.AuthorizedCurrencyEquiv, security *helper.TransportSecurity) (*emptypb.Empty, error) {
+	conn, err := helper.NewConnection(grpcAddr, "50052", security)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 
 	client := pb.NewTXNServiceClient(conn)
-	response, err := client.AuthCurrencyEquiv(context.Background(), txn)
+	response, err := client.AuthCurrencyEquiv(ctx, txn)
 	if err != nil {
-		return nil, fmt.Errorf("ACE transaction failed: %v", err)
+		return nil, fmt.Errorf("ACE transaction failed: %w", zeraerr.FromGRPC(err))
 	}
 
 	return response, nil
@@ -3,15 +3,13 @@ package currencyequivalent
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -23,13 +21,25 @@ type SelfData struct {
 }
 
 func CreateSelfCurrencyEquivalentTxn(nonceInfo nonce.NonceInfo, data []SelfData, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.SelfCurrencyEquiv, error) {
+	return CreateSelfCurrencyEquivalentTxnWithContext(context.Background(), nonceInfo, data, publicKeyBase58, privateKeyBase58, feeID, feeAmountParts)
+}
+
+// CreateSelfCurrencyEquivalentTxnWithContext is CreateSelfCurrencyEquivalentTxn with a context that is passed through to the nonce lookup.
+func CreateSelfCurrencyEquivalentTxnWithContext(ctx context.Context, nonceInfo nonce.NonceInfo, data []SelfData, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.SelfCurrencyEquiv, error) {
+	return CreateSelfCurrencyEquivalentTxnWithSigner(ctx, nonceInfo, data, helper.NewPrivateKeySigner(publicKeyBase58, privateKeyBase58), feeID, feeAmountParts)
+}
+
+// CreateSelfCurrencyEquivalentTxnWithSigner is CreateSelfCurrencyEquivalentTxn with the transaction signed by signer instead of a base58 private key.
+func CreateSelfCurrencyEquivalentTxnWithSigner(ctx context.Context, nonceInfo nonce.NonceInfo, data []SelfData, signer helper.Signer, feeID string, feeAmountParts string) (*pb.SelfCurrencyEquiv, error) {
+	publicKeyBase58 := signer.PublicKey()
+
 	// Step 1: Decode public key
-	_, _, pubKeyBytes, err := transcode.Base58DecodePublicKey(publicKeyBase58)
+	txnPublicKey, err := helper.TxnPublicKey(signer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode public key: %v", err)
 	}
 
-	nonce, err := nonce.GetNonce(nonceInfo, 5)
+	nonce, err := nonce.GetNonceWithContext(ctx, nonceInfo, nonce.DefaultConcurrency)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nonce: %v", err)
@@ -41,9 +51,7 @@ func CreateSelfCurrencyEquivalentTxn(nonceInfo nonce.NonceInfo, data []SelfData,
 
 	// Step 2: Create BaseTXN
 	base := &pb.BaseTXN{
-		PublicKey: &pb.PublicKey{
-			Single: pubKeyBytes,
-		},
+		PublicKey: txnPublicKey,
 		FeeId:     feeID,
 		FeeAmount: feeAmountParts,
 		Timestamp: timestamppb.New(time.Now().UTC()),
@@ -73,30 +81,11 @@ func CreateSelfCurrencyEquivalentTxn(nonceInfo nonce.NonceInfo, data []SelfData,
 
 	// Step 5: Verify and determine key
 	// Check to ensure its a restricted key
-	if !strings.HasPrefix(publicKeyBase58, "r_") {
+	if !helper.IsRestricted(txnPublicKey) {
 		return nil, fmt.Errorf("public key is not a restricted key (r_): %s", publicKeyBase58)
 	}
 
-	// Find the substring between "r_" and the next "_"
-	pubBeyParts := strings.SplitN(publicKeyBase58, "_", 3) // Split into at most 3 parts
-	var keyLetter string
-	if len(pubBeyParts) > 2 {
-		keyLetter = pubBeyParts[1]
-	} else {
-		return nil, fmt.Errorf("invalid public key format: %s", publicKeyBase58)
-	}
-
-	// Ed25519
-	var keyType helper.KeyType
-	if keyLetter == "A" {
-		keyType = helper.ED25519
-	} else if keyLetter == "B" {
-		keyType = helper.ED448
-	} else {
-		return nil, fmt.Errorf("unknown key type for public key: %s", publicKeyBase58)
-	}
-
-	signature, err := helper.Sign(privateKeyBase58, byteDataNoSig, keyType)
+	signature, err := helper.SignWith(signer, byteDataNoSig)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign mint transaction: %v", err)
@@ -119,20 +108,26 @@ func CreateSelfCurrencyEquivalentTxn(nonceInfo nonce.NonceInfo, data []SelfData,
 }
 
 func SendSelfCurrencyEquivalentTXN(grpcAddr string, txn *pb.SelfCurrencyEquiv) (*emptypb.Empty, error) {
-	if !strings.Contains(grpcAddr, ":") {
-		grpcAddr += ":50052"
-	}
+	return SendSelfCurrencyEquivalentTXNWithContext(context.Background(), grpcAddr, txn)
+}
+
+// SendSelfCurrencyEquivalentTXNWithContext is SendSelfCurrencyEquivalentTXN with a context that is passed through to the gRPC call.
+func SendSelfCurrencyEquivalentTXNWithContext(ctx context.Context, grpcAddr string, txn *pb.SelfCurrencyEquiv) (*emptypb.Empty, error) {
+	return SendSelfCurrencyEquivalentTXNWithSecurity(ctx, grpcAddr, txn, nil)
+}
 
-	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// SendSelfCurrencyEquivalentTXNWithSecurity is SendSelfCurrencyEquivalentTXNWithContext with the validator dialed using security (plaintext if nil).
+func SendSelfCurrencyEquivalentTXNWithSecurity(ctx context.Context, grpcAddr string, txn *pb.SelfCurrencyEquiv, security *helper.TransportSecurity) (*emptypb.Empty, error) {
+	conn, err := helper.NewConnection(grpcAddr, "50052", security)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 
 	client := pb.NewTXNServiceClient(conn)
-	response, err := client.CurrencyEquiv(context.Background(), txn)
+	response, err := client.CurrencyEquiv(ctx, txn)
 	if err != nil {
-		return nil, fmt.Errorf("currency equivalent transaction failed: %v", err)
+		return nil, fmt.Errorf("currency equivalent transaction failed: %w", zeraerr.FromGRPC(err))
 	}
 
 	return response, nil
@@ -3,21 +3,31 @@ package expenseratio
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func ExpenseRatioTxn(nonceInfo nonce.NonceInfo, symbol string, calledAddrs []string, recipient string, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.ExpenseRatioTXN, error) {
+	return ExpenseRatioTxnWithContext(context.Background(), nonceInfo, symbol, calledAddrs, recipient, publicKeyBase58, privateKeyBase58, feeID, feeAmountParts)
+}
+
+// ExpenseRatioTxnWithContext is ExpenseRatioTxn with a context that is passed through to the nonce lookup.
+func ExpenseRatioTxnWithContext(ctx context.Context, nonceInfo nonce.NonceInfo, symbol string, calledAddrs []string, recipient string, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.ExpenseRatioTXN, error) {
+	return ExpenseRatioTxnWithSigner(ctx, nonceInfo, symbol, calledAddrs, recipient, helper.NewPrivateKeySigner(publicKeyBase58, privateKeyBase58), feeID, feeAmountParts)
+}
+
+// ExpenseRatioTxnWithSigner is ExpenseRatioTxn with the transaction signed by signer instead of a base58 private key.
+func ExpenseRatioTxnWithSigner(ctx context.Context, nonceInfo nonce.NonceInfo, symbol string, calledAddrs []string, recipient string, signer helper.Signer, feeID string, feeAmountParts string) (*pb.ExpenseRatioTXN, error) {
+	publicKeyBase58 := signer.PublicKey()
+
 	// Step 1: Decode recipient address
 	recipientBytes, err := transcode.Base58Decode(recipient)
 	if err != nil {
@@ -34,12 +44,12 @@ func ExpenseRatioTxn(nonceInfo nonce.NonceInfo, symbol string, calledAddrs []str
 	}
 
 	// Step 2: Decode public key
-	_, _, pubKeyBytes, err := transcode.Base58DecodePublicKey(publicKeyBase58)
+	txnPublicKey, err := helper.TxnPublicKey(signer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode public key: %v", err)
 	}
 
-	nonce, err := nonce.GetNonce(nonceInfo, 5)
+	nonce, err := nonce.GetNonceWithContext(ctx, nonceInfo, nonce.DefaultConcurrency)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nonce: %v", err)
@@ -51,9 +61,7 @@ func ExpenseRatioTxn(nonceInfo nonce.NonceInfo, symbol string, calledAddrs []str
 
 	// Step 3: Create BaseTXN
 	base := &pb.BaseTXN{
-		PublicKey: &pb.PublicKey{
-			Single: pubKeyBytes,
-		},
+		PublicKey: txnPublicKey,
 		FeeId:     feeID,
 		FeeAmount: feeAmountParts,
 		Timestamp: timestamppb.New(time.Now().UTC()),
@@ -76,30 +84,11 @@ func ExpenseRatioTxn(nonceInfo nonce.NonceInfo, symbol string, calledAddrs []str
 
 	// Step 6: Verify and determine key
 	// Check to ensure its a restricted key
-	if !strings.HasPrefix(publicKeyBase58, "r_") {
+	if !helper.IsRestricted(txnPublicKey) {
 		return nil, fmt.Errorf("public key is not a restricted key (r_): %s", publicKeyBase58)
 	}
 
-	// Find the substring between "r_" and the next "_"
-	pubBeyParts := strings.SplitN(publicKeyBase58, "_", 3) // Split into at most 3 parts
-	var keyLetter string
-	if len(pubBeyParts) > 2 {
-		keyLetter = pubBeyParts[1]
-	} else {
-		return nil, fmt.Errorf("invalid public key format: %s", publicKeyBase58)
-	}
-
-	// Ed25519
-	var keyType helper.KeyType
-	if keyLetter == "A" {
-		keyType = helper.ED25519
-	} else if keyLetter == "B" {
-		keyType = helper.ED448
-	} else {
-		return nil, fmt.Errorf("unknown key type for public key: %s", publicKeyBase58)
-	}
-
-	signature, err := helper.Sign(privateKeyBase58, byteDataNoSig, keyType)
+	signature, err := helper.SignWith(signer, byteDataNoSig)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign expense ratio transaction: %v", err)
@@ -123,20 +112,26 @@ func ExpenseRatioTxn(nonceInfo nonce.NonceInfo, symbol string, calledAddrs []str
 
 // SendExpenseRatioTXN submits a ExpenseRatioTXN to the network via gRPC
 func SendExpenseRatioTXN(grpcAddr string, txn *pb.ExpenseRatioTXN) (*emptypb.Empty, error) {
-	if !strings.Contains(grpcAddr, ":") {
-		grpcAddr += ":50052"
-	}
+	return SendExpenseRatioTXNWithContext(context.Background(), grpcAddr, txn)
+}
+
+// SendExpenseRatioTXNWithContext is SendExpenseRatioTXN with a context that is passed through to the gRPC call.
+func SendExpenseRatioTXNWithContext(ctx context.Context, grpcAddr string, txn *pb.ExpenseRatioTXN) (*emptypb.Empty, error) {
+	return SendExpenseRatioTXNWithSecurity(ctx, grpcAddr, txn, nil)
+}
 
-	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// SendExpenseRatioTXNWithSecurity is SendExpenseRatioTXNWithContext with the validator dialed using security (plaintext if nil).
+func SendExpenseRatioTXNWithSecurity(ctx context.Context, grpcAddr string, txn *pb.ExpenseRatioTXN, security *helper.TransportSecurity) (*emptypb.Empty, error) {
+	conn, err := helper.NewConnection(grpcAddr, "50052", security)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 
 	client := pb.NewTXNServiceClient(conn)
-	response, err := client.ExpenseRatio(context.Background(), txn)
+	response, err := client.ExpenseRatio(ctx, txn)
 	if err != nil {
-		return nil, fmt.Errorf("expense ratio transaction failed: %v", err)
+		return nil, fmt.Errorf("expense ratio transaction failed: %w", zeraerr.FromGRPC(err))
 	}
 
 	return response, nil
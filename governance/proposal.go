@@ -3,28 +3,36 @@ package governance
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func CreateProposalTxn(nonceInfo nonce.NonceInfo, symbol string, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string, title, synopsis, body string, options []string, startTimestamp *timestamppb.Timestamp, endTimestamp *timestamppb.Timestamp, txns []*pb.GovernanceTXN) (*pb.GovernanceProposal, error) {
+	return CreateProposalTxnWithContext(context.Background(), nonceInfo, symbol, publicKeyBase58, privateKeyBase58, feeID, feeAmountParts, title, synopsis, body, options, startTimestamp, endTimestamp, txns)
+}
+
+// CreateProposalTxnWithContext is CreateProposalTxn with a context that is passed through to the nonce lookup.
+func CreateProposalTxnWithContext(ctx context.Context, nonceInfo nonce.NonceInfo, symbol string, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string, title, synopsis, body string, options []string, startTimestamp *timestamppb.Timestamp, endTimestamp *timestamppb.Timestamp, txns []*pb.GovernanceTXN) (*pb.GovernanceProposal, error) {
+	return CreateProposalTxnWithSigner(ctx, nonceInfo, symbol, helper.NewPrivateKeySigner(publicKeyBase58, privateKeyBase58), feeID, feeAmountParts, title, synopsis, body, options, startTimestamp, endTimestamp, txns)
+}
+
+// CreateProposalTxnWithSigner is CreateProposalTxn with the transaction signed by signer instead of a base58 private key.
+func CreateProposalTxnWithSigner(ctx context.Context, nonceInfo nonce.NonceInfo, symbol string, signer helper.Signer, feeID string, feeAmountParts string, title, synopsis, body string, options []string, startTimestamp *timestamppb.Timestamp, endTimestamp *timestamppb.Timestamp, txns []*pb.GovernanceTXN) (*pb.GovernanceProposal, error) {
 	// Step 1: Decode public key
-	_, _, pubKeyBytes, err := transcode.Base58DecodePublicKey(publicKeyBase58)
+	txnPublicKey, err := helper.TxnPublicKey(signer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode public key: %v", err)
 	}
 
-	nonce, err := nonce.GetNonce(nonceInfo, 5)
+	nonce, err := nonce.GetNonceWithContext(ctx, nonceInfo, nonce.DefaultConcurrency)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nonce: %v", err)
@@ -36,9 +44,7 @@ func CreateProposalTxn(nonceInfo nonce.NonceInfo, symbol string, publicKeyBase58
 
 	// Step 2: Create BaseTXN
 	base := &pb.BaseTXN{
-		PublicKey: &pb.PublicKey{
-			Single: pubKeyBytes,
-		},
+		PublicKey: txnPublicKey,
 		FeeId:     feeID,
 		FeeAmount: feeAmountParts,
 		Timestamp: timestamppb.New(time.Now().UTC()),
@@ -64,14 +70,8 @@ func CreateProposalTxn(nonceInfo nonce.NonceInfo, symbol string, publicKeyBase58
 		return nil, fmt.Errorf("failed to serialize proposal transaction: %v", err)
 	}
 
-	keyType, err := helper.DetermineKeyType(publicKeyBase58)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine key type: %v", err)
-	}
-
 	// Step 5: Sign the transaction
-	signature, err := helper.Sign(privateKeyBase58, byteDataNoSig, keyType)
+	signature, err := helper.SignWith(signer, byteDataNoSig)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign proposal transaction: %v", err)
@@ -95,20 +95,26 @@ func CreateProposalTxn(nonceInfo nonce.NonceInfo, symbol string, publicKeyBase58
 
 // SendProposal submits a proposal to the network via gRPC
 func SendProposal(grpcAddr string, txn *pb.GovernanceProposal) (*emptypb.Empty, error) {
-	if !strings.Contains(grpcAddr, ":") {
-		grpcAddr += ":50052"
-	}
+	return SendProposalWithContext(context.Background(), grpcAddr, txn)
+}
+
+// SendProposalWithContext is SendProposal with a context that is passed through to the gRPC call.
+func SendProposalWithContext(ctx context.Context, grpcAddr string, txn *pb.GovernanceProposal) (*emptypb.Empty, error) {
+	return SendProposalWithSecurity(ctx, grpcAddr, txn, nil)
+}
 
-	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// SendProposalWithSecurity is SendProposalWithContext with the validator dialed using security (plaintext if nil).
+func SendProposalWithSecurity(ctx context.Context, grpcAddr string, txn *pb.GovernanceProposal, security *helper.TransportSecurity) (*emptypb.Empty, error) {
+	conn, err := helper.NewConnection(grpcAddr, "50052", security)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 
 	client := pb.NewTXNServiceClient(conn)
-	response, err := client.GovernProposal(context.Background(), txn)
+	response, err := client.GovernProposal(ctx, txn)
 	if err != nil {
-		return nil, fmt.Errorf("proposal transaction failed: %v", err)
+		return nil, fmt.Errorf("proposal transaction failed: %w", zeraerr.FromGRPC(err))
 	}
 
 	return response, nil
@@ -3,15 +3,13 @@ package governance
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -36,6 +34,16 @@ import (
 // - *pb.GovernanceVote: The constructed governance vote transaction.
 // - error: An error if any step in the process fails.
 func CreateVoteTxn(nonceInfo nonce.NonceInfo, symbol string, proposalID string, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string, support *bool, voteOption *uint32) (*pb.GovernanceVote, error) {
+	return CreateVoteTxnWithContext(context.Background(), nonceInfo, symbol, proposalID, publicKeyBase58, privateKeyBase58, feeID, feeAmountParts, support, voteOption)
+}
+
+// CreateVoteTxnWithContext is CreateVoteTxn with a context that is passed through to the nonce lookup.
+func CreateVoteTxnWithContext(ctx context.Context, nonceInfo nonce.NonceInfo, symbol string, proposalID string, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string, support *bool, voteOption *uint32) (*pb.GovernanceVote, error) {
+	return CreateVoteTxnWithSigner(ctx, nonceInfo, symbol, proposalID, helper.NewPrivateKeySigner(publicKeyBase58, privateKeyBase58), feeID, feeAmountParts, support, voteOption)
+}
+
+// CreateVoteTxnWithSigner is CreateVoteTxn with the transaction signed by signer instead of a base58 private key.
+func CreateVoteTxnWithSigner(ctx context.Context, nonceInfo nonce.NonceInfo, symbol string, proposalID string, signer helper.Signer, feeID string, feeAmountParts string, support *bool, voteOption *uint32) (*pb.GovernanceVote, error) {
 	// Step 1: proposalID (from hex)
 	proposalBytes, err := transcode.HexDecode(proposalID)
 	if err != nil {
@@ -43,12 +51,12 @@ func CreateVoteTxn(nonceInfo nonce.NonceInfo, symbol string, proposalID string,
 	}
 
 	// Step 2: Decode public key
-	_, _, pubKeyBytes, err := transcode.Base58DecodePublicKey(publicKeyBase58)
+	txnPublicKey, err := helper.TxnPublicKey(signer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode public key: %v", err)
 	}
 
-	nonce, err := nonce.GetNonce(nonceInfo, 5)
+	nonce, err := nonce.GetNonceWithContext(ctx, nonceInfo, nonce.DefaultConcurrency)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nonce: %v", err)
@@ -60,9 +68,7 @@ func CreateVoteTxn(nonceInfo nonce.NonceInfo, symbol string, proposalID string,
 
 	// Step 3: Create BaseTXN
 	base := &pb.BaseTXN{
-		PublicKey: &pb.PublicKey{
-			Single: pubKeyBytes,
-		},
+		PublicKey: txnPublicKey,
 		FeeId:     feeID,
 		FeeAmount: feeAmountParts,
 		Timestamp: timestamppb.New(time.Now().UTC()),
@@ -84,14 +90,8 @@ func CreateVoteTxn(nonceInfo nonce.NonceInfo, symbol string, proposalID string,
 		return nil, fmt.Errorf("failed to serialize vote transaction: %v", err)
 	}
 
-	keyType, err := helper.DetermineKeyType(publicKeyBase58)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine key type: %v", err)
-	}
-
 	// Step 6: Sign the transaction
-	signature, err := helper.Sign(privateKeyBase58, byteDataNoSig, keyType)
+	signature, err := helper.SignWith(signer, byteDataNoSig)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign vote transaction: %v", err)
@@ -115,20 +115,26 @@ func CreateVoteTxn(nonceInfo nonce.NonceInfo, symbol string, proposalID string,
 
 // SendVoteTxn submits a vote to the network via gRPC
 func SendVoteTxn(grpcAddr string, txn *pb.GovernanceVote) (*emptypb.Empty, error) {
-	if !strings.Contains(grpcAddr, ":") {
-		grpcAddr += ":50052"
-	}
+	return SendVoteTxnWithContext(context.Background(), grpcAddr, txn)
+}
+
+// SendVoteTxnWithContext is SendVoteTxn with a context that is passed through to the gRPC call.
+func SendVoteTxnWithContext(ctx context.Context, grpcAddr string, txn *pb.GovernanceVote) (*emptypb.Empty, error) {
+	return SendVoteTxnWithSecurity(ctx, grpcAddr, txn, nil)
+}
 
-	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// SendVoteTxnWithSecurity is SendVoteTxnWithContext with the validator dialed using security (plaintext if nil).
+func SendVoteTxnWithSecurity(ctx context.Context, grpcAddr string, txn *pb.GovernanceVote, security *helper.TransportSecurity) (*emptypb.Empty, error) {
+	conn, err := helper.NewConnection(grpcAddr, "50052", security)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 
 	client := pb.NewTXNServiceClient(conn)
-	response, err := client.GovernVote(context.Background(), txn)
+	response, err := client.GovernVote(ctx, txn)
 	if err != nil {
-		return nil, fmt.Errorf("vote transaction failed: %v", err)
+		return nil, fmt.Errorf("vote transaction failed: %w", zeraerr.FromGRPC(err))
 	}
 
 	return response, nil
@@ -1,21 +1,244 @@
 package helper
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"strings"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
 )
 
-func DetermineKeyType(publicKeyBase58 string) (KeyType, error) {
+// Public key lengths per curve
+const (
+	ED25519PublicKeySize = 32
+	ED448PublicKeySize   = 57
+)
+
+// ParsedPublicKey is a public key string broken into its parts. Supported forms:
+//
+//	[r_]<A|B>_<hash tokens>_<base58 key>   ie r_A_c_FPXd... (the hash chain may be empty for multi-key members, ie A_FPXd...)
+//	gov_<contract id>                      ie gov_$ZRA+0000
+//	sc_<name>_<instance>                   ie sc_exchange_1
+type ParsedPublicKey struct {
+	Restricted    bool       // r_ prefix
+	KeyType       KeyType    // ED25519 / ED448, SPECIAL for gov_ / sc_ keys
+	HashTokens    []HashType // hash chain used for the wallet address, ie [BLAKE3] for A_c_
+	Key           []byte     // raw curve public key
+	Governance    string     // contract id of a gov_ key
+	SmartContract string     // name_instance of an sc_ key
+}
+
+// ParsePublicKey parses a base58 public key as used by the builders (ie r_A_c_FPXd..., gov_$ZRA+0000).
+func ParsePublicKey(publicKeyBase58 string) (*ParsedPublicKey, error) {
+	if publicKeyBase58 == "" {
+		return nil, errors.New("public key is empty")
+	}
+
+	if special, ok := parseSpecial(publicKeyBase58); ok {
+		return special, nil
+	}
+
+	parsed := &ParsedPublicKey{}
+	rest := publicKeyBase58
+	if strings.HasPrefix(rest, "r_") {
+		parsed.Restricted = true
+		rest = rest[2:]
+	}
+
+	parts := strings.Split(rest, "_")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("public key %s has no key type prefix", publicKeyBase58)
+	}
+
+	if err := parsed.parsePrefix(parts[0], parts[1:len(parts)-1]); err != nil {
+		return nil, fmt.Errorf("public key %s: %v", publicKeyBase58, err)
+	}
+
+	key, err := transcode.Base58Decode(parts[len(parts)-1])
+	if err != nil {
+		return nil, fmt.Errorf("public key %s: could not decode key: %v", publicKeyBase58, err)
+	}
+
+	parsed.Key = key
+	if err := parsed.checkLength(); err != nil {
+		return nil, fmt.Errorf("public key %s: %v", publicKeyBase58, err)
+	}
+
+	return parsed, nil
+}
+
+// ParsePublicKeyBytes parses a public key in transaction form, the prefix followed by the raw key (ie "A_c_" + 32 bytes).
+func ParsePublicKeyBytes(publicKey []byte) (*ParsedPublicKey, error) {
+	if len(publicKey) == 0 {
+		return nil, errors.New("public key is empty")
+	}
+
+	if special, ok := parseSpecial(string(publicKey)); ok {
+		return special, nil
+	}
+
+	parsed := &ParsedPublicKey{}
+	rest := publicKey
+	if bytes.HasPrefix(rest, []byte("r_")) {
+		parsed.Restricted = true
+		rest = rest[2:]
+	}
+
+	// the raw key may contain '_', so its length is taken from the key type
+	var size int
+	switch {
+	case bytes.HasPrefix(rest, []byte("A_")):
+		size = ED25519PublicKeySize
+	case bytes.HasPrefix(rest, []byte("B_")):
+		size = ED448PublicKeySize
+	default:
+		return nil, errors.New("public key has an unknown key type prefix")
+	}
 
-	publicKeyBase58 = strings.TrimPrefix(publicKeyBase58, "r_")
+	if len(rest) < size+2 {
+		return nil, errors.New("invalid public key length")
+	}
+
+	prefix := strings.TrimSuffix(string(rest[:len(rest)-size]), "_")
+	parts := strings.Split(prefix, "_")
+
+	if err := parsed.parsePrefix(parts[0], parts[1:]); err != nil {
+		return nil, err
+	}
+
+	parsed.Key = append([]byte(nil), rest[len(rest)-size:]...)
+	return parsed, nil
+}
+
+func parseSpecial(publicKey string) (*ParsedPublicKey, bool) {
+	if strings.HasPrefix(publicKey, "gov_") {
+		return &ParsedPublicKey{KeyType: SPECIAL, Governance: strings.TrimPrefix(publicKey, "gov_")}, true
+	}
+
+	if strings.HasPrefix(publicKey, "sc_") {
+		return &ParsedPublicKey{KeyType: SPECIAL, SmartContract: strings.TrimPrefix(publicKey, "sc_")}, true
+	}
 
-	keyLetter := strings.Split(publicKeyBase58, "_")[0]
+	return nil, false
+}
+
+func (p *ParsedPublicKey) parsePrefix(keyLetter string, hashTokens []string) error {
 	switch keyLetter {
 	case "A":
-		return ED25519, nil
+		p.KeyType = ED25519
 	case "B":
-		return ED448, nil
+		p.KeyType = ED448
 	default:
+		return fmt.Errorf("unknown key type %q", keyLetter)
+	}
+
+	for _, token := range hashTokens {
+		hashType, err := ParseHashType(token)
+		if err != nil || hashType == RESTRICTED {
+			return fmt.Errorf("unknown hash token %q", token)
+		}
+		p.HashTokens = append(p.HashTokens, hashType)
+	}
+
+	return nil
+}
+
+func (p *ParsedPublicKey) checkLength() error {
+	switch {
+	case p.KeyType == ED25519 && len(p.Key) != ED25519PublicKeySize:
+		return fmt.Errorf("invalid key length %d for ED25519", len(p.Key))
+	case p.KeyType == ED448 && len(p.Key) != ED448PublicKeySize:
+		return fmt.Errorf("invalid key length %d for ED448", len(p.Key))
+	}
+	return nil
+}
+
+// prefix is the part before the raw key, ie r_A_c_
+func (p *ParsedPublicKey) prefix() string {
+	var prefix strings.Builder
+	if p.Restricted {
+		prefix.WriteString("r_")
+	}
+
+	if p.KeyType == ED448 {
+		prefix.WriteString("B_")
+	} else {
+		prefix.WriteString("A_")
+	}
+
+	for _, hashType := range p.HashTokens {
+		prefix.WriteString(hashType.String() + "_")
+	}
+
+	return prefix.String()
+}
+
+// String formats the key as ParsePublicKey accepts it.
+func (p *ParsedPublicKey) String() string {
+	switch {
+	case p.Governance != "":
+		return "gov_" + p.Governance
+	case p.SmartContract != "":
+		return "sc_" + p.SmartContract
+	}
+	return p.prefix() + transcode.Base58Encode(p.Key)
+}
+
+// Bytes returns the key in transaction form (ie "A_c_" + 32 bytes).
+func (p *ParsedPublicKey) Bytes() []byte {
+	switch {
+	case p.Governance != "":
+		return []byte("gov_" + p.Governance)
+	case p.SmartContract != "":
+		return []byte("sc_" + p.SmartContract)
+	}
+	return append([]byte(p.prefix()), p.Key...)
+}
+
+// Special reports whether the key is a gov_ / sc_ key, which authorizes without a signature.
+func (p *ParsedPublicKey) Special() bool {
+	return p.KeyType == SPECIAL
+}
+
+// Proto returns the key as it is put in transactions.
+func (p *ParsedPublicKey) Proto() *pb.PublicKey {
+	switch {
+	case p.Governance != "":
+		return &pb.PublicKey{GovernanceAuth: p.Bytes()}
+	case p.SmartContract != "":
+		return &pb.PublicKey{SmartContractAuth: p.Bytes()}
+	}
+	return &pb.PublicKey{Single: p.Bytes()}
+}
+
+// ParseHashType parses a hash token (ie "c" for BLAKE3).
+func ParseHashType(token string) (HashType, error) {
+	for _, hashType := range []HashType{RESTRICTED, BLAKE3, SHA3_256, SHA3_512} {
+		if hashType.String() == token {
+			return hashType, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported hash token %q", token)
+}
+
+// IsRestricted reports whether a transaction key is restricted (r_ single keys, multi-keys whose hash tokens start with r).
+func IsRestricted(publicKey *pb.PublicKey) bool {
+	if multi := publicKey.GetMulti(); multi != nil {
+		return len(multi.HashTokens) > 0 && multi.HashTokens[0] == RESTRICTED.String()
+	}
+
+	parsed, err := ParsePublicKeyBytes(publicKey.GetSingle())
+	return err == nil && parsed.Restricted
+}
+
+// Deprecated: use ParsePublicKey.
+func DetermineKeyType(publicKeyBase58 string) (KeyType, error) {
+	parsed, err := ParsePublicKey(publicKeyBase58)
+	if err != nil {
 		return 0, fmt.Errorf("unknown key type for public key: %s", publicKeyBase58)
 	}
+
+	return parsed.KeyType, nil
 }
@@ -0,0 +1,75 @@
+package helper_test
+
+import (
+	"testing"
+
+	"github.com/ZeraVision/zera-go-sdk/helper"
+)
+
+func TestParsePublicKey(t *testing.T) {
+	tests := []struct {
+		publicKey  string
+		restricted bool
+		keyType    helper.KeyType
+		hashTokens []helper.HashType
+		keySize    int
+	}{
+		{"A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7", false, helper.ED25519, []helper.HashType{helper.BLAKE3}, 32},
+		{"r_A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7", true, helper.ED25519, []helper.HashType{helper.BLAKE3}, 32},
+		{"A_c_b_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7", false, helper.ED25519, []helper.HashType{helper.BLAKE3, helper.SHA3_512}, 32},
+		{"A_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7", false, helper.ED25519, nil, 32},
+		{"B_a_8TZAaoUWbGvkxaWdWBXJ3mVHXVXLDJgtbeexkBzj5ySjpru7yZvfuKwGGHt2gtFpQfQCaRnBPU43bV", false, helper.ED448, []helper.HashType{helper.SHA3_256}, 57},
+		{"gov_$ZRA+0000", false, helper.SPECIAL, nil, 0},
+		{"sc_exchange_1", false, helper.SPECIAL, nil, 0},
+	}
+
+	for _, tt := range tests {
+		parsed, err := helper.ParsePublicKey(tt.publicKey)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.publicKey, err)
+		}
+
+		if parsed.Restricted != tt.restricted || parsed.KeyType != tt.keyType || len(parsed.Key) != tt.keySize || len(parsed.HashTokens) != len(tt.hashTokens) {
+			t.Errorf("%s: unexpected parse %+v", tt.publicKey, parsed)
+		}
+
+		for i := range tt.hashTokens {
+			if parsed.HashTokens[i] != tt.hashTokens[i] {
+				t.Errorf("%s: expected hash tokens %v, got %v", tt.publicKey, tt.hashTokens, parsed.HashTokens)
+			}
+		}
+
+		if parsed.String() != tt.publicKey {
+			t.Errorf("Expected %s, got %s", tt.publicKey, parsed.String())
+		}
+
+		// transaction form round trip
+		fromBytes, err := helper.ParsePublicKeyBytes(parsed.Bytes())
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.publicKey, err)
+		}
+
+		if fromBytes.String() != tt.publicKey {
+			t.Errorf("Expected %s, got %s", tt.publicKey, fromBytes.String())
+		}
+	}
+
+	if parsed, _ := helper.ParsePublicKey("gov_$ZRA+0000"); parsed.Proto().GovernanceAuth == nil {
+		t.Error("Expected gov_ keys to use GovernanceAuth")
+	}
+}
+
+func TestParsePublicKeyInvalid(t *testing.T) {
+	for _, publicKey := range []string{
+		"",
+		"FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7",     // no prefix
+		"C_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7", // unknown key type
+		"A_x_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7", // unknown hash token
+		"B_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7", // ED25519 length for ED448
+		"A_c_0OIl", // not base58
+	} {
+		if _, err := helper.ParsePublicKey(publicKey); err == nil {
+			t.Errorf("%q: expected an error, got none", publicKey)
+		}
+	}
+}
@@ -2,11 +2,12 @@ package helper
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
-	"github.com/ZeraVision/zera-go-sdk/transcode"
 )
 
 // Only one of these should be set at a time
@@ -48,10 +49,13 @@ func GeneratePublicKey(publicKey PublicKey) (*pb.PublicKey, error) {
 	pubKey := &pb.PublicKey{}
 
 	if publicKey.Single != nil {
-		_, _, pubKeyByte, _ := transcode.Base58DecodePublicKey(*publicKey.Single)
+		parsed, err := ParsePublicKey(*publicKey.Single)
+		if err != nil {
+			return nil, err
+		}
 
 		pubKey = &pb.PublicKey{
-			Single: []byte(pubKeyByte),
+			Single: parsed.Bytes(),
 		}
 	}
 
@@ -80,7 +84,10 @@ func GeneratePublicKey(publicKey PublicKey) (*pb.PublicKey, error) {
 
 		// Public keys in format class_keytype_decodedpublickey
 		for _, key := range publicKey.Multi.MultiKey {
-			_, _, pubKeyByte, _ := transcode.Base58DecodePublicKey(key.PublicKey)
+			pubKeyByte, err := multiKeyMember(key.PublicKey)
+			if err != nil {
+				return nil, err
+			}
 
 			pubKey.Multi.PublicKeys = append(pubKey.Multi.PublicKeys, pubKeyByte)
 		}
@@ -144,3 +151,24 @@ func checkPublicKeyCount(publicKey PublicKey) int {
 
 	return count
 }
+
+// multiKeyMember decodes a multi-key member, with or without its class prefix (ie 1_A_FPXd... or A_FPXd...)
+func multiKeyMember(member string) ([]byte, error) {
+	var class string
+	if prefix, rest, ok := strings.Cut(member, "_"); ok {
+		if _, err := strconv.ParseUint(prefix, 10, 32); err == nil {
+			class, member = prefix+"_", rest
+		}
+	}
+
+	parsed, err := ParsePublicKey(member)
+	if err != nil {
+		return nil, fmt.Errorf("invalid multi-key member: %v", err)
+	}
+
+	if parsed.Restricted || parsed.Special() {
+		return nil, fmt.Errorf("invalid multi-key member %s: only ed25519 and ed448 keys can be members", member)
+	}
+
+	return append([]byte(class), parsed.Bytes()...), nil
+}
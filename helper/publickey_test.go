@@ -83,6 +83,22 @@ func TestGeneratePublicKey_MultiKey(t *testing.T) {
 	compareResult(t, result, "12710a22415fd5c908ae57a79d4e820f61b20b618fdb782e87a46eaef580ec94c6815b82f90a0a3b425f3e6467cc0cb5654788058c44db412b47d31f897ead16fc609b621e356c9455ed0fc6e1c59feab234f6385c331f84453bd6a06b6ad218545a001a080a02010212020101220163220161")
 }
 
+func TestGeneratePublicKey_InvalidMultiKey(t *testing.T) {
+	for _, member := range []string{"1_A_0OIl", "1_gov_$ZRA+0000", "r_A_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7", "C_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"} {
+		publicKey := helper.PublicKey{
+			Multi: &helper.MultiKeyHelper{
+				MultiKey:   []helper.MultiKey{{Class: 1, PublicKey: member}},
+				Pattern:    [][]helper.MultiPatterns{{{Class: 1, Required: 1}}},
+				HashTokens: []helper.HashType{helper.BLAKE3},
+			},
+		}
+
+		if _, err := helper.GeneratePublicKey(publicKey); err == nil {
+			t.Errorf("Expected an error for member %s, got none", member)
+		}
+	}
+}
+
 func TestGeneratePublicKey_SmartContractKey(t *testing.T) {
 	smartContract := helper.SmartContractHelper{
 		Name:     "TestContract",
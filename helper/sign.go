@@ -47,7 +47,7 @@ func Sign(privateKeyBase58 string, payload []byte, keyType KeyType) ([]byte, err
 
 // Verify checks the signature of a payload using the given public key.
 func Verify(publicKeyBase58 string, payload []byte, signature []byte) (bool, error) {
-	_, publicKeyByte, _, err := transcode.Base58DecodePublicKey(publicKeyBase58)
+	parsed, err := ParsePublicKey(publicKeyBase58)
 	if err != nil {
 		return false, fmt.Errorf("could not decode public key: %v", err)
 	}
@@ -60,19 +60,8 @@ func Verify(publicKeyBase58 string, payload []byte, signature []byte) (bool, err
 		return false, errors.New("signature cannot be empty")
 	}
 
-	var keyType KeyType
-	if len(publicKeyBase58) > 0 {
-		switch publicKeyBase58[0] {
-		case 'A':
-			keyType = ED25519
-		case 'B':
-			keyType = ED448
-		default:
-			return false, errors.New("unsupported key type")
-		}
-	} else {
-		return false, errors.New("public key is empty")
-	}
+	keyType := parsed.KeyType
+	publicKeyByte := parsed.Key
 
 	switch keyType {
 	case ED25519:
@@ -84,3 +84,61 @@ func testSignature(t *testing.T, address, testPublic, testPrivate string, keyTyp
 		t.Errorf("Signature verification failed")
 	}
 }
+
+// countingSigner wraps another signer, like a policy or audit layer would
+type countingSigner struct {
+	helper.Signer
+	signed int
+}
+
+func (s *countingSigner) Sign(payload []byte) ([]byte, error) {
+	s.signed++
+	return s.Signer.Sign(payload)
+}
+
+func TestPrivateKeySigner(t *testing.T) {
+	testPublic := "A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"
+	testPrivate := "2ap5CkCekErkqJ4UuSGAW1BmRRRNr8hXaebudv1j8TY6mJMSsbnniakorFGmetE4aegsyQAD8WX1N8Q2Y45YEBDs"
+
+	signer := helper.NewPrivateKeySigner(testPublic, testPrivate)
+	if signer.KeyType() != helper.ED25519 {
+		t.Fatalf("Expected ED25519, got %d", signer.KeyType())
+	}
+
+	signature, err := signer.Sign([]byte("payload"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if ok, err := helper.Verify(testPublic, []byte("payload"), signature); !ok || err != nil {
+		t.Fatalf("Expected valid signature, got %v", err)
+	}
+
+	// Governance keys are not signed
+	signature, err = helper.SignWith(helper.NewPrivateKeySigner("gov_$ZRA+0000", ""), []byte("payload"))
+	if err != nil || signature != nil {
+		t.Errorf("Expected no signature for governance key, got %x (%v)", signature, err)
+	}
+
+	if _, err := helper.NewPrivateKeySigner("Z_c_unknown", testPrivate).Sign([]byte("payload")); err == nil {
+		t.Error("Expected an error for unknown key type, got none")
+	}
+
+	// Custom signers plug into the builders
+	wrapped := &countingSigner{Signer: signer}
+
+	txn, err := transfer.CreateCoinTxn(
+		nonce.NonceInfo{Override: []uint64{5}},
+		parts.PartsInfo{Symbol: "$ZRA+0000", Override: big.NewInt(1000000000)},
+		[]transfer.Inputs{{B58Address: "8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR", Signer: wrapped, Amount: "1", FeePercent: 100}},
+		map[string]string{"outputAddr1": "1"},
+		"$ZRA+0000", "1000000000", nil, nil, 5,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if wrapped.signed != 1 || len(txn.Auth.Signature) != 1 {
+		t.Errorf("Expected the custom signer to sign once, got %d signatures by %d calls", len(txn.Auth.Signature), wrapped.signed)
+	}
+}
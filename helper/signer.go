@@ -0,0 +1,103 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+)
+
+// Signer signs transaction payloads on behalf of a single key. Every transaction builder accepts a Signer
+// (the CreateXxxWithSigner variants / transfer.Inputs.Signer), so key material can live in a KMS, a remote
+// signing service or behind a policy check instead of being passed around as base58 strings.
+type Signer interface {
+	PublicKey() string                   // base58 public key as used by the builders (ie r_A_c_..., gov_$ZRA+0000)
+	KeyType() KeyType                    // SPECIAL for gov_ / sc_ keys, which are not signed
+	Sign(payload []byte) ([]byte, error) // signature over the serialized transaction
+}
+
+// TxnKeySigner is implemented by signers that do not authenticate with a single key, ie multisig.Account.
+// Builders put TxnPublicKey in the transaction instead of decoding PublicKey.
+type TxnKeySigner interface {
+	Signer
+	TxnPublicKey() (*pb.PublicKey, error)
+}
+
+// TxnPublicKey returns the public key a builder puts in the transaction for signer.
+func TxnPublicKey(signer Signer) (*pb.PublicKey, error) {
+	if s, ok := signer.(TxnKeySigner); ok {
+		return s.TxnPublicKey()
+	}
+
+	parsed, err := ParsePublicKey(signer.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.Proto(), nil
+}
+
+// PrivateKeySigner signs with a base58 encoded private key held in memory, like Sign.
+type PrivateKeySigner struct {
+	publicKey  string
+	privateKey string
+	keyType    KeyType
+}
+
+// NewPrivateKeySigner creates an in memory signer, the key type is taken from the public key prefix.
+func NewPrivateKeySigner(publicKeyBase58, privateKeyBase58 string) *PrivateKeySigner {
+	return NewPrivateKeySignerWithKeyType(publicKeyBase58, privateKeyBase58, signerKeyType(publicKeyBase58))
+}
+
+// NewPrivateKeySignerWithKeyType creates an in memory signer with an explicit key type.
+func NewPrivateKeySignerWithKeyType(publicKeyBase58, privateKeyBase58 string, keyType KeyType) *PrivateKeySigner {
+	return &PrivateKeySigner{
+		publicKey:  publicKeyBase58,
+		privateKey: privateKeyBase58,
+		keyType:    keyType,
+	}
+}
+
+func (s *PrivateKeySigner) PublicKey() string { return s.publicKey }
+
+func (s *PrivateKeySigner) KeyType() KeyType { return s.keyType }
+
+func (s *PrivateKeySigner) Sign(payload []byte) ([]byte, error) {
+	if s.keyType == Unknown {
+		return nil, fmt.Errorf("unknown key type for public key: %s", s.publicKey)
+	}
+
+	return Sign(s.privateKey, payload, s.keyType)
+}
+
+// signerKeyType is the key type of a parsed key, with "special" keys resolving to SPECIAL (no signature)
+func signerKeyType(publicKeyBase58 string) KeyType {
+	if strings.HasPrefix(publicKeyBase58, "special") {
+		return SPECIAL
+	}
+
+	parsed, err := ParsePublicKey(publicKeyBase58)
+	if err != nil {
+		return Unknown
+	}
+
+	return parsed.KeyType
+}
+
+// SignWith signs payload with signer, returning no signature for SPECIAL keys.
+func SignWith(signer Signer, payload []byte) ([]byte, error) {
+	if signer == nil {
+		return nil, errors.New("signer is required")
+	}
+
+	if len(payload) == 0 {
+		return nil, errors.New("payload cannot be empty")
+	}
+
+	if signer.KeyType() == SPECIAL {
+		return nil, nil
+	}
+
+	return signer.Sign(payload)
+}
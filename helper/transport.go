@@ -0,0 +1,111 @@
+package helper
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TransportSecurity configures how connections to validators (gRPC) and the indexer (HTTP) are secured.
+// A nil *TransportSecurity keeps the default behaviour: plaintext gRPC and a default http.Client.
+type TransportSecurity struct {
+	TLS               bool                          // dial validators with TLS (ie when behind a TLS terminating gateway)
+	RootCAs           *x509.CertPool                // optional, CA pool to verify servers (system pool if nil)
+	Certificates      []tls.Certificate             // optional, client certificates for mTLS
+	ServerName        string                        // optional, overrides the name used to verify the validator certificate
+	PerRPCCredentials credentials.PerRPCCredentials // optional, attached to every gRPC call (ie gateway auth tokens)
+	HTTPTransport     http.RoundTripper             // optional, used for indexer requests (overrides RootCAs / Certificates for HTTP)
+
+	Dialer func(ctx context.Context, addr string) (net.Conn, error) // optional, opens validator connections instead of dialing the network (ie an in-process server, see zeratest)
+}
+
+// DialOptions returns the grpc options to dial a validator with.
+func (s *TransportSecurity) DialOptions() []grpc.DialOption {
+	if s == nil {
+		return []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+
+	var opts []grpc.DialOption
+
+	if s.TLS {
+		config := s.tlsConfig()
+		config.ServerName = s.ServerName
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(config)))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	if s.PerRPCCredentials != nil {
+		opts = append(opts, grpc.WithPerRPCCredentials(s.PerRPCCredentials))
+	}
+
+	if s.Dialer != nil {
+		opts = append(opts, grpc.WithContextDialer(s.Dialer))
+	}
+
+	return opts
+}
+
+// HTTPClient returns the client to use for indexer requests.
+func (s *TransportSecurity) HTTPClient() *http.Client {
+	if s == nil {
+		return &http.Client{}
+	}
+
+	if s.HTTPTransport != nil {
+		return &http.Client{Transport: s.HTTPTransport}
+	}
+
+	if s.RootCAs == nil && len(s.Certificates) == 0 {
+		return &http.Client{}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = s.tlsConfig()
+
+	return &http.Client{Transport: transport}
+}
+
+func (s *TransportSecurity) tlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		RootCAs:      s.RootCAs,
+		Certificates: s.Certificates,
+	}
+}
+
+// LoadCertPool reads one or more PEM encoded CA files into a new pool.
+func LoadCertPool(pemFiles ...string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+
+	for _, file := range pemFiles {
+		pemData, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %s: %v", file, err)
+		}
+
+		if !pool.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("no certificates found in %s", file)
+		}
+	}
+
+	return pool, nil
+}
+
+// NewConnection creates a gRPC connection to grpcAddr, appending defaultPort if no port is given.
+func NewConnection(grpcAddr string, defaultPort string, security *TransportSecurity) (*grpc.ClientConn, error) {
+	if !strings.Contains(grpcAddr, ":") {
+		grpcAddr += ":" + defaultPort
+	}
+
+	return grpc.NewClient(grpcAddr, security.DialOptions()...)
+}
@@ -0,0 +1,505 @@
+// Package inspect decodes serialized transactions (ie received from a partner) and describes them for review:
+// addresses and public keys in base58, amounts in coins of their contract, fees, nonces, memo, and whether the
+// signatures and Base.Hash are valid.
+//
+//	txn, err := inspect.DecodeString(hexOrBase64, "") // type detected, or ie "CoinTXN"
+//	report, err := inspect.Inspect(txn, nil)
+//	fmt.Println(report)
+package inspect
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/convert"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/multisig"
+	"github.com/ZeraVision/zera-go-sdk/offline"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/ZeraVision/zera-go-sdk/wallet"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrAmbiguous is returned by Decode when the bytes are a valid encoding of more than one transaction type
+// (ie a MintTXN and an NFTTXN share their wire format), pass the type to decode them.
+var ErrAmbiguous = errors.New("ambiguous transaction type")
+
+// types are the transactions that can be decoded
+var types = []txn{
+	&pb.CoinTXN{},
+	&pb.MintTXN{},
+	&pb.ItemizedMintTXN{},
+	&pb.NFTTXN{},
+	&pb.InstrumentContract{},
+	&pb.ContractUpdateTXN{},
+	&pb.GovernanceProposal{},
+	&pb.GovernanceVote{},
+	&pb.AllowanceTXN{},
+	&pb.ComplianceTXN{},
+	&pb.ExpenseRatioTXN{},
+	&pb.SelfCurrencyEquiv{},
+	&pb.AuthorizedCurrencyEquiv{},
+}
+
+// defaultParts are the parts per coin of contracts that are always known
+var defaultParts = map[string]*big.Int{
+	"$ZRA+0000": big.NewInt(1_000_000_000),
+}
+
+// txn is implemented by every transaction type (all of them carry a BaseTXN)
+type txn interface {
+	proto.Message
+	GetBase() *pb.BaseTXN
+}
+
+// Report describes a transaction.
+type Report struct {
+	Type       string      `json:"type"`       // protobuf message name, ie CoinTXN
+	Hash       string      `json:"hash"`       // hex of Base.Hash
+	HashValid  bool        `json:"hashValid"`  // Base.Hash is the SHA3-256 of the signed transaction
+	Signed     bool        `json:"signed"`     // every signature is valid and every key is authorized (multi-keys meet one of their patterns)
+	Signatures []Signature `json:"signatures"` // one per signing key, in transaction order (gov_ / sc_ keys do not sign)
+	Keys       []Key       `json:"keys"`       // keys authorizing the transaction (CoinTXN inputs or the base key)
+	FeeID      string      `json:"feeId"`
+	Fee        string      `json:"fee"` // base fee, ie 0.001 $ZRA+0000 (1000000 parts)
+	Memo       *string     `json:"memo,omitempty"`
+	Timestamp  time.Time   `json:"timestamp"`
+	Fields     []Field     `json:"fields"` // every field that is set, in declaration order
+}
+
+// Signature is the check of one signature
+type Signature struct {
+	Input     int    `json:"input"`           // index of the transaction key it belongs to
+	PublicKey string `json:"publicKey"`       // base58 public key (the member key of a multi-key)
+	Valid     bool   `json:"valid"`           // false if missing or invalid
+	Error     string `json:"error,omitempty"` // why it is not valid
+}
+
+// Key is a key authorizing the transaction
+type Key struct {
+	PublicKey string `json:"publicKey"` // ie A_c_..., gov_$ZRA+0000, or the members of a multi-key
+	Address   string `json:"address"`   // wallet address, base58 (gov_ / sc_ addresses as is)
+	Nonce     uint64 `json:"nonce"`
+}
+
+// Field is a human readable field of the transaction
+type Field struct {
+	Name  string `json:"name"` // path of the field, ie output_transfers[0].amount
+	Value string `json:"value"`
+}
+
+// Valid reports whether the hash and every signature are valid.
+func (r *Report) Valid() bool {
+	return r.HashValid && r.Signed
+}
+
+func (r *Report) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(w, "type\t%s\n", r.Type)
+	fmt.Fprintf(w, "hash\t%s (%s)\n", r.Hash, validity(r.HashValid))
+	fmt.Fprintf(w, "signatures\t%s\n", validity(r.Signed))
+	for _, signature := range r.Signatures {
+		status := validity(signature.Valid)
+		if signature.Error != "" {
+			status += ": " + signature.Error
+		}
+		fmt.Fprintf(w, "\tkey %d %s (%s)\n", signature.Input, signature.PublicKey, status)
+	}
+
+	for i, key := range r.Keys {
+		fmt.Fprintf(w, "key %d\t%s, address %s, nonce %d\n", i, key.PublicKey, key.Address, key.Nonce)
+	}
+
+	fmt.Fprintf(w, "fee\t%s\n", r.Fee)
+	if r.Memo != nil {
+		fmt.Fprintf(w, "memo\t%q\n", *r.Memo)
+	}
+	fmt.Fprintf(w, "timestamp\t%s\n", r.Timestamp.Format(time.RFC3339Nano))
+
+	fmt.Fprintln(w)
+	for _, field := range r.Fields {
+		fmt.Fprintf(w, "%s\t%s\n", field.Name, field.Value)
+	}
+
+	w.Flush()
+	return b.String()
+}
+
+// Decode decodes a serialized transaction. txnType is the message name (ie CoinTXN, case insensitive), or empty to
+// detect it (see Detect).
+func Decode(data []byte, txnType string) (proto.Message, error) {
+	if txnType != "" {
+		for _, candidate := range types {
+			name := candidate.ProtoReflect().Descriptor().Name()
+			full := candidate.ProtoReflect().Descriptor().FullName()
+
+			if !strings.EqualFold(txnType, string(name)) && !strings.EqualFold(txnType, string(full)) {
+				continue
+			}
+
+			message := candidate.ProtoReflect().New().Interface()
+			if err := proto.Unmarshal(data, message); err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %v", name, err)
+			}
+			return message, nil
+		}
+
+		return nil, fmt.Errorf("unsupported transaction type %q", txnType)
+	}
+
+	detected := Detect(data)
+	switch len(detected) {
+	case 0:
+		return nil, errors.New("not a supported transaction")
+	case 1:
+		return Decode(data, detected[0])
+	default:
+		return nil, fmt.Errorf("%w: could be %s", ErrAmbiguous, strings.Join(detected, ", "))
+	}
+}
+
+// DecodeString is Decode for hex (with or without 0x) or base64 (standard or url, padded or not).
+func DecodeString(encoded string, txnType string) (proto.Message, error) {
+	data, err := decodeText(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data, txnType)
+}
+
+// Detect returns the names of the transaction types data is a valid encoding of: it decodes without unknown fields,
+// has a signing key, and encodes back to exactly data.
+func Detect(data []byte) []string {
+	var detected []string
+	for _, candidate := range types {
+		message := candidate.ProtoReflect().New().Interface()
+		if err := proto.Unmarshal(data, message); err != nil || hasUnknown(message.ProtoReflect()) {
+			continue
+		}
+
+		if keys, _ := txnKeys(message.(txn)); len(keys) == 0 || keys[0] == nil {
+			continue
+		}
+
+		encoded, err := proto.Marshal(message)
+		if err != nil || !bytes.Equal(encoded, data) {
+			continue
+		}
+
+		detected = append(detected, string(message.ProtoReflect().Descriptor().Name()))
+	}
+	return detected
+}
+
+// Inspect describes a transaction. partsPerCoin gives the denomination of contracts by id to format amounts in
+// coins, $ZRA+0000 is known (amounts of other contracts are shown in parts).
+func Inspect(transaction proto.Message, partsPerCoin map[string]*big.Int) (*Report, error) {
+	t, ok := transaction.(txn)
+	if !ok || t.GetBase() == nil {
+		return nil, fmt.Errorf("unsupported transaction type %T", transaction)
+	}
+
+	i := &inspector{parts: make(map[string]*big.Int)}
+	for contractID, parts := range defaultParts {
+		i.parts[contractID] = parts
+	}
+	for contractID, parts := range partsPerCoin {
+		i.parts[contractID] = parts
+	}
+
+	if withContract, ok := transaction.(interface{ GetContractId() string }); ok {
+		i.contractID = withContract.GetContractId()
+	}
+
+	// a new contract brings its own denomination
+	if contract, ok := transaction.(*pb.InstrumentContract); ok {
+		if parts, ok := new(big.Int).SetString(contract.GetCoinDenomination().GetAmount(), 10); ok && parts.Sign() > 0 {
+			i.parts[contract.GetContractId()] = parts
+		}
+	}
+
+	base := t.GetBase()
+	report := &Report{
+		Type:      string(transaction.ProtoReflect().Descriptor().Name()),
+		Hash:      transcode.HexEncode(base.GetHash()),
+		FeeID:     base.GetFeeId(),
+		Fee:       i.amount(base.GetFeeAmount(), base.GetFeeId()),
+		Memo:      base.Memo,
+		Timestamp: base.GetTimestamp().AsTime(),
+	}
+
+	// Step 1: Hash and signatures
+	verification, err := offline.Check(t)
+	if err != nil {
+		return nil, err
+	}
+
+	report.HashValid = verification.HashValid
+	report.Signed = verification.Signed()
+
+	for _, check := range verification.Signatures {
+		signature := Signature{Input: check.Input, PublicKey: check.PublicKey, Valid: check.Err == nil}
+		if check.Err != nil {
+			signature.Error = check.Err.Error()
+		}
+		report.Signatures = append(report.Signatures, signature)
+	}
+
+	// Step 2: Keys and nonces
+	keys, nonces := txnKeys(t)
+	for k, key := range keys {
+		described := Key{PublicKey: publicKeyString(key), Address: keyAddress(key)}
+		if k < len(nonces) {
+			described.Nonce = nonces[k]
+		}
+		report.Keys = append(report.Keys, described)
+	}
+
+	// Step 3: Every field
+	i.walk("", transaction.ProtoReflect())
+	report.Fields = i.fields
+
+	return report, nil
+}
+
+// txnKeys returns the keys authorizing a transaction with their nonces, the inputs of a CoinTXN or the base key
+func txnKeys(t txn) ([]*pb.PublicKey, []uint64) {
+	if coin, ok := t.(*pb.CoinTXN); ok {
+		return coin.GetAuth().GetPublicKey(), coin.GetAuth().GetNonce()
+	}
+	return []*pb.PublicKey{t.GetBase().GetPublicKey()}, []uint64{t.GetBase().GetNonce()}
+}
+
+// inspector collects the fields of a transaction
+type inspector struct {
+	contractID string              // contract of the transaction
+	parts      map[string]*big.Int // parts per coin by contract id
+	fields     []Field
+}
+
+func (i *inspector) add(name, value string) {
+	i.fields = append(i.fields, Field{Name: name, Value: value})
+}
+
+// walk adds the fields of a message that are set, in declaration order
+func (i *inspector) walk(prefix string, m protoreflect.Message) {
+	fields := m.Descriptor().Fields()
+	for f := 0; f < fields.Len(); f++ {
+		fd := fields.Get(f)
+		if !m.Has(fd) {
+			continue
+		}
+
+		name := prefix + string(fd.Name())
+
+		if fd.IsList() {
+			list := m.Get(fd).List()
+			for l := 0; l < list.Len(); l++ {
+				i.value(fmt.Sprintf("%s[%d]", name, l), m, fd, list.Get(l))
+			}
+			continue
+		}
+
+		i.value(name, m, fd, m.Get(fd))
+	}
+}
+
+// value adds one field value, parent is the message holding it
+func (i *inspector) value(name string, parent protoreflect.Message, fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		switch message := v.Message().Interface().(type) {
+		case *pb.PublicKey:
+			i.add(name, fmt.Sprintf("%s (address %s)", publicKeyString(message), keyAddress(message)))
+		case *timestamppb.Timestamp:
+			i.add(name, message.AsTime().UTC().Format(time.RFC3339Nano))
+		default:
+			i.walk(name+".", v.Message())
+		}
+
+	case protoreflect.BytesKind:
+		if strings.Contains(string(fd.Name()), "address") {
+			i.add(name, displayAddress(v.Bytes()))
+		} else {
+			i.add(name, transcode.HexEncode(v.Bytes()))
+		}
+
+	case protoreflect.EnumKind:
+		if value := fd.Enum().Values().ByNumber(v.Enum()); value != nil {
+			i.add(name, string(value.Name()))
+		} else {
+			i.add(name, fmt.Sprint(v.Enum()))
+		}
+
+	case protoreflect.StringKind:
+		if contractID, ok := i.denomination(parent, fd); ok {
+			i.add(name, i.amount(v.String(), contractID))
+		} else {
+			i.add(name, v.String())
+		}
+
+	default:
+		i.add(name, fmt.Sprint(v.Interface()))
+	}
+}
+
+// denomination is the contract an amount field of a message is in, false if the field is not an amount in parts
+func (i *inspector) denomination(parent protoreflect.Message, fd protoreflect.FieldDescriptor) (string, bool) {
+	field := fd.Name()
+
+	switch message := parent.Interface().(type) {
+	case *pb.BaseTXN:
+		return message.GetFeeId(), field == "fee_amount"
+	case *pb.InputTransfers, *pb.OutputTransfers, *pb.MintTXN, *pb.MaxSupplyRelease, *pb.PreMintWallet:
+		return i.contractID, field == "amount"
+	case *pb.AllowanceTXN:
+		return i.contractID, field == "allowed_amount"
+	case *pb.InstrumentContract:
+		return i.contractID, field == "max_supply"
+	case *pb.CoinTXN:
+		return message.GetContractFeeId(), field == "contract_fee_amount"
+	case *pb.NFTTXN:
+		return message.GetContractFeeId(), field == "contract_fee_amount"
+	}
+
+	return "", false
+}
+
+// amount formats parts of a contract, in coins if its denomination is known
+func (i *inspector) amount(value string, contractID string) string {
+	parts, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return value
+	}
+
+	partsPerCoin, known := i.parts[contractID]
+	if !known {
+		return fmt.Sprintf("%s parts of %s", parts, contractID)
+	}
+
+	amount, err := convert.NewAmount(parts, partsPerCoin)
+	if err != nil {
+		return fmt.Sprintf("%s parts of %s", parts, contractID)
+	}
+
+	return fmt.Sprintf("%s %s (%s parts)", amount, contractID, parts)
+}
+
+// publicKeyString is the base58 form of a transaction key, the members of a multi-key
+func publicKeyString(key *pb.PublicKey) string {
+	switch {
+	case key.GetMulti() != nil:
+		members, err := multisig.Members(key.GetMulti())
+		if err != nil {
+			return "invalid multi-key: " + err.Error()
+		}
+
+		var keys []string
+		for _, member := range members {
+			keys = append(keys, fmt.Sprintf("%d_%s", member.Class, member.PublicKey))
+		}
+		return fmt.Sprintf("multi-key [%s] hash %s", strings.Join(keys, ", "), strings.Join(key.GetMulti().GetHashTokens(), "_"))
+
+	case key.GetGovernanceAuth() != nil:
+		return string(key.GetGovernanceAuth())
+
+	case key.GetSmartContractAuth() != nil:
+		return string(key.GetSmartContractAuth())
+	}
+
+	parsed, err := helper.ParsePublicKeyBytes(key.GetSingle())
+	if err != nil {
+		return "invalid key " + transcode.HexEncode(key.GetSingle())
+	}
+	return parsed.String()
+}
+
+func keyAddress(key *pb.PublicKey) string {
+	_, address, err := wallet.GetAddressFromKey(key)
+	if errors.Is(err, wallet.ErrMultiKeyAddress) {
+		return "unknown (multi-key)"
+	}
+	if err != nil {
+		return "invalid: " + err.Error()
+	}
+	return address
+}
+
+// displayAddress is base58 for wallet addresses and the key itself for gov_ / sc_ addresses
+func displayAddress(address []byte) string {
+	if bytes.HasPrefix(address, []byte("gov_")) || bytes.HasPrefix(address, []byte("sc_")) || bytes.HasPrefix(address, []byte("$")) {
+		return string(address)
+	}
+	return transcode.Base58Encode(address)
+}
+
+// hasUnknown reports whether a decoded message, or one nested in it, has fields its type does not declare
+func hasUnknown(m protoreflect.Message) bool {
+	if len(m.GetUnknown()) > 0 {
+		return true
+	}
+
+	unknown := false
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind {
+			return true
+		}
+
+		switch {
+		case fd.IsList():
+			for l := 0; l < v.List().Len() && !unknown; l++ {
+				unknown = hasUnknown(v.List().Get(l).Message())
+			}
+		case fd.IsMap():
+			v.Map().Range(func(_ protoreflect.MapKey, value protoreflect.Value) bool {
+				if fd.MapValue().Kind() == protoreflect.MessageKind {
+					unknown = hasUnknown(value.Message())
+				}
+				return !unknown
+			})
+		default:
+			unknown = hasUnknown(v.Message())
+		}
+
+		return !unknown
+	})
+
+	return unknown
+}
+
+// decodeText decodes hex (preferred, a hex string is also valid base64) or base64
+func decodeText(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(encoded, "0x"), "0X")
+
+	if data, err := hex.DecodeString(trimmed); err == nil {
+		return data, nil
+	}
+
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if data, err := encoding.DecodeString(encoded); err == nil {
+			return data, nil
+		}
+	}
+
+	return nil, errors.New("transaction is neither hex nor base64")
+}
+
+func validity(valid bool) string {
+	if valid {
+		return "valid"
+	}
+	return "INVALID"
+}
@@ -0,0 +1,207 @@
+package inspect_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/inspect"
+	"github.com/ZeraVision/zera-go-sdk/mint"
+	"github.com/ZeraVision/zera-go-sdk/nonce"
+	"github.com/ZeraVision/zera-go-sdk/parts"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/ZeraVision/zera-go-sdk/transfer"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	TEST_ADDRESS = "8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR"
+	TEST_PUBLIC  = "A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"
+	TEST_PRIVATE = "2ap5CkCekErkqJ4UuSGAW1BmRRRNr8hXaebudv1j8TY6mJMSsbnniakorFGmetE4aegsyQAD8WX1N8Q2Y45YEBDs"
+
+	RECIPIENT_ADDRESS = "Hv3KUwrmR8C8XVSxuJFJrQqeDixeDnakUTkUUMZkFCUS"
+)
+
+func mintTxn(t *testing.T) *pb.MintTXN {
+	signer := helper.NewPrivateKeySigner("r_"+TEST_PUBLIC, TEST_PRIVATE)
+
+	txn, err := mint.CreateMintTxnWithSigner(context.Background(), nonce.NonceInfo{Override: []uint64{3}}, "$TEST+0000", "2500", RECIPIENT_ADDRESS, signer, "$ZRA+0000", "1000000")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return txn
+}
+
+func field(report *inspect.Report, name string) string {
+	for _, field := range report.Fields {
+		if field.Name == name {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+func TestInspectCoin(t *testing.T) {
+	txn, err := transfer.CreateCoinTxnWithContext(context.Background(), nonce.NonceInfo{Override: []uint64{7}}, parts.PartsInfo{Symbol: "$ZRA+0000", Override: big.NewInt(1_000_000_000)},
+		[]transfer.Inputs{{B58Address: TEST_ADDRESS, Signer: helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE), Amount: "1.5", FeePercent: 100}},
+		map[string]string{RECIPIENT_ADDRESS: "1.5"},
+		"$ZRA+0000", "1000000", nil, nil, 1,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, err := proto.Marshal(txn)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// the type is detected from the bytes
+	decoded, err := inspect.DecodeString(transcode.HexEncode(data), "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !proto.Equal(decoded, txn) {
+		t.Fatalf("Expected the decoded transaction to equal the original")
+	}
+
+	report, err := inspect.Inspect(decoded, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.Type != "CoinTXN" || !report.Valid() {
+		t.Fatalf("Expected a valid CoinTXN, got %s (hash %v, signed %v)", report.Type, report.HashValid, report.Signed)
+	}
+
+	if len(report.Keys) != 1 || report.Keys[0].Address != TEST_ADDRESS || report.Keys[0].PublicKey != TEST_PUBLIC || report.Keys[0].Nonce != 7 {
+		t.Fatalf("Expected key %s of %s with nonce 7, got %+v", TEST_PUBLIC, TEST_ADDRESS, report.Keys)
+	}
+
+	if report.Fee != "0.001 $ZRA+0000 (1000000 parts)" {
+		t.Errorf("Expected a fee of 0.001 $ZRA+0000, got %s", report.Fee)
+	}
+
+	if value := field(report, "output_transfers[0].wallet_address"); value != RECIPIENT_ADDRESS {
+		t.Errorf("Expected output to %s, got %s", RECIPIENT_ADDRESS, value)
+	}
+
+	if value := field(report, "output_transfers[0].amount"); value != "1.5 $ZRA+0000 (1500000000 parts)" {
+		t.Errorf("Expected an output of 1.5 $ZRA+0000, got %s", value)
+	}
+
+	if !strings.Contains(report.String(), RECIPIENT_ADDRESS) {
+		t.Errorf("Expected the text report to show the recipient, got\n%s", report)
+	}
+}
+
+func TestInspectTampered(t *testing.T) {
+	txn := mintTxn(t)
+
+	data, err := proto.Marshal(txn)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// MintTXN, ItemizedMintTXN and NFTTXN share a wire format
+	if _, err := inspect.Decode(data, ""); !errors.Is(err, inspect.ErrAmbiguous) {
+		t.Fatalf("Expected an ambiguous type, got %v", err)
+	}
+
+	decoded, err := inspect.DecodeString(base64.StdEncoding.EncodeToString(data), "minttxn")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	report, err := inspect.Inspect(decoded, map[string]*big.Int{"$TEST+0000": big.NewInt(100)})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !report.Valid() || report.Keys[0].PublicKey != "r_"+TEST_PUBLIC || report.Keys[0].Nonce != 3 {
+		t.Fatalf("Expected a valid mint by r_%s with nonce 3, got %+v", TEST_PUBLIC, report)
+	}
+
+	if value := field(report, "amount"); value != "25 $TEST+0000 (2500 parts)" {
+		t.Errorf("Expected 25 $TEST+0000, got %s", value)
+	}
+
+	// a different amount than was signed
+	tampered := proto.Clone(txn).(*pb.MintTXN)
+	tampered.Amount = "250000"
+
+	report, err = inspect.Inspect(tampered, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.Signed || report.HashValid || report.Signatures[0].Valid {
+		t.Errorf("Expected invalid signature and hash, got signed %v, hash %v", report.Signed, report.HashValid)
+	}
+
+	if value := field(report, "amount"); value != "250000 parts of $TEST+0000" {
+		t.Errorf("Expected an amount in parts for an unknown denomination, got %s", value)
+	}
+
+	// a hash that does not belong to the transaction
+	rehashed := proto.Clone(txn).(*pb.MintTXN)
+	rehashed.Base.Hash = transcode.SHA3256([]byte("other"))
+
+	report, err = inspect.Inspect(rehashed, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !report.Signed || report.HashValid {
+		t.Errorf("Expected a valid signature and an invalid hash, got signed %v, hash %v", report.Signed, report.HashValid)
+	}
+
+	// no signature at all
+	unsigned := proto.Clone(txn).(*pb.MintTXN)
+	unsigned.Base.Signature = nil
+
+	report, err = inspect.Inspect(unsigned, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.Signed || !strings.Contains(report.Signatures[0].Error, "missing") {
+		t.Errorf("Expected a missing signature, got %+v", report.Signatures)
+	}
+}
+
+func TestDecodeString(t *testing.T) {
+	data, err := proto.Marshal(mintTxn(t))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, encoded := range []string{
+		"0x" + transcode.HexEncode(data),
+		strings.ToUpper(transcode.HexEncode(data)),
+		base64.RawURLEncoding.EncodeToString(data),
+		" " + base64.StdEncoding.EncodeToString(data) + "\n",
+	} {
+		if _, err := inspect.DecodeString(encoded, "MintTXN"); err != nil {
+			t.Fatalf("Expected no error for %q, got %v", encoded, err)
+		}
+	}
+
+	if _, err := inspect.DecodeString("not a transaction!", ""); err == nil {
+		t.Fatalf("Expected error for text that is neither hex nor base64")
+	}
+
+	if _, err := inspect.Decode(data, "Unknown"); err == nil {
+		t.Fatalf("Expected error for an unsupported type")
+	}
+
+	if _, err := inspect.Decode([]byte("garbage"), ""); err == nil {
+		t.Fatalf("Expected error for bytes that are not a transaction")
+	}
+}
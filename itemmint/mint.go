@@ -3,18 +3,15 @@ package itemmint
 import (
 	"context"
 	"fmt"
-	"math"
 	"math/big"
-	"strings"
 	"time"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/convert"
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
-	"github.com/ZeraVision/zera-go-sdk/wallet"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -38,6 +35,47 @@ func CreateItemMintTxn(
 	votingWeight *big.Int,
 	contractFees *pb.ItemContractFees,
 ) (*pb.ItemizedMintTXN, error) {
+	return CreateItemMintTxnWithContext(context.Background(), nonceInfo, contractId, itemId, recipient, publicKeyBase58, privateKeyBase58, feeID, feeAmountParts, parameters, expiry, validFrom, votingWeight, contractFees)
+}
+
+// CreateItemMintTxnWithContext is CreateItemMintTxn with a context that is passed through to the nonce lookup.
+func CreateItemMintTxnWithContext(
+	ctx context.Context,
+	nonceInfo nonce.NonceInfo,
+	contractId string,
+	itemId *big.Int,
+	recipient string,
+	publicKeyBase58 string,
+	privateKeyBase58 string,
+	feeID string,
+	feeAmountParts string,
+	parameters []*pb.KeyValuePair,
+	expiry *uint64,
+	validFrom *uint64,
+	votingWeight *big.Int,
+	contractFees *pb.ItemContractFees,
+) (*pb.ItemizedMintTXN, error) {
+	return CreateItemMintTxnWithSigner(ctx, nonceInfo, contractId, itemId, recipient, helper.NewPrivateKeySigner(publicKeyBase58, privateKeyBase58), feeID, feeAmountParts, parameters, expiry, validFrom, votingWeight, contractFees)
+}
+
+// CreateItemMintTxnWithSigner is CreateItemMintTxn with the transaction signed by signer instead of a base58 private key.
+func CreateItemMintTxnWithSigner(
+	ctx context.Context,
+	nonceInfo nonce.NonceInfo,
+	contractId string,
+	itemId *big.Int,
+	recipient string,
+	signer helper.Signer,
+	feeID string,
+	feeAmountParts string,
+	parameters []*pb.KeyValuePair,
+	expiry *uint64,
+	validFrom *uint64,
+	votingWeight *big.Int,
+	contractFees *pb.ItemContractFees,
+) (*pb.ItemizedMintTXN, error) {
+	publicKeyBase58 := signer.PublicKey()
+
 	// Step 1: Decode recipient address
 	recipientBytes, err := transcode.Base58Decode(recipient)
 	if err != nil {
@@ -45,12 +83,12 @@ func CreateItemMintTxn(
 	}
 
 	// Step 2: Decode public key
-	_, _, pubKeyBytes, err := transcode.Base58DecodePublicKey(publicKeyBase58)
+	txnPublicKey, err := helper.TxnPublicKey(signer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode public key: %v", err)
 	}
 
-	nonceArr, err := nonce.GetNonce(nonceInfo, 5)
+	nonceArr, err := nonce.GetNonceWithContext(ctx, nonceInfo, nonce.DefaultConcurrency)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nonce: %v", err)
 	}
@@ -60,21 +98,13 @@ func CreateItemMintTxn(
 
 	// Step 3: Create BaseTXN
 	base := &pb.BaseTXN{
-		PublicKey: &pb.PublicKey{},
+		PublicKey: txnPublicKey,
 		FeeId:     feeID,
 		FeeAmount: feeAmountParts,
 		Timestamp: timestamppb.New(time.Now().UTC()),
 		Nonce:     nonceArr[0],
 	}
 
-	if strings.HasPrefix(publicKeyBase58, "gov_") {
-		base.PublicKey.GovernanceAuth = pubKeyBytes
-	} else if strings.HasPrefix(publicKeyBase58, "sc_") {
-		base.PublicKey.SmartContractAuth = pubKeyBytes
-	} else {
-		base.PublicKey.Single = pubKeyBytes
-	}
-
 	// Step 4: Construct ItemizedMintTXN
 	itemMintTxn := &pb.ItemizedMintTXN{
 		Base:             base,
@@ -102,16 +132,11 @@ func CreateItemMintTxn(
 	}
 
 	// Step 6: Verify and determine key
-	if !strings.HasPrefix(publicKeyBase58, "r_") && !strings.HasPrefix(publicKeyBase58, "gov_") && !strings.HasPrefix(publicKeyBase58, "sc_") {
+	if !helper.IsRestricted(txnPublicKey) && txnPublicKey.GovernanceAuth == nil && txnPublicKey.SmartContractAuth == nil {
 		return nil, fmt.Errorf("not possible to do restricted logic (requires r_, gov_, or sc_ key): %s", publicKeyBase58)
 	}
 
-	keyType, err := wallet.DetermineKeyType(publicKeyBase58)
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine key type: %v", err)
-	}
-
-	signature, err := helper.Sign(privateKeyBase58, byteDataNoSig, keyType)
+	signature, err := helper.SignWith(signer, byteDataNoSig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign item mint transaction: %v", err)
 	}
@@ -134,43 +159,52 @@ func CreateItemMintTxn(
 
 // SendMintTXN submits a MintTXN to the network via gRPC
 func SendItemMintTXN(grpcAddr string, txn *pb.ItemizedMintTXN) (*emptypb.Empty, error) {
-	if !strings.Contains(grpcAddr, ":") {
-		grpcAddr += ":50052"
-	}
+	return SendItemMintTXNWithContext(context.Background(), grpcAddr, txn)
+}
+
+// SendItemMintTXNWithContext is SendItemMintTXN with a context that is passed through to the gRPC call.
+func SendItemMintTXNWithContext(ctx context.Context, grpcAddr string, txn *pb.ItemizedMintTXN) (*emptypb.Empty, error) {
+	return SendItemMintTXNWithSecurity(ctx, grpcAddr, txn, nil)
+}
 
-	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// SendItemMintTXNWithSecurity is SendItemMintTXNWithContext with the validator dialed using security (plaintext if nil).
+func SendItemMintTXNWithSecurity(ctx context.Context, grpcAddr string, txn *pb.ItemizedMintTXN, security *helper.TransportSecurity) (*emptypb.Empty, error) {
+	conn, err := helper.NewConnection(grpcAddr, "50052", security)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 
 	client := pb.NewTXNServiceClient(conn)
-	response, err := client.ItemMint(context.Background(), txn)
+	response, err := client.ItemMint(ctx, txn)
 	if err != nil {
-		return nil, fmt.Errorf("item mint transaction failed: %v", err)
+		return nil, fmt.Errorf("item mint transaction failed: %w", zeraerr.FromGRPC(err))
 	}
 
 	return response, nil
 }
 
 // BuildItemContractFees builds a *pb.ItemContractFees from user-friendly types.
-// fee: the fee amount as float64 (e.g., 1.23 for $1.23)
+// fee: the currency equivalent fee (e.g., convert.MustParseDecimal("1.23") for $1.23)
 // feeAddressB58: the fee address in base58 encoding
-// burnPercent: burn percent (0-100), e.g., 25.5 for 25.5%
-// validatorPercent: validator percent (0-100), e.g., 10 for 10%
+// burnPercent: burn percent (0-100), e.g., convert.MustParseDecimal("25.5") for 25.5%
+// validatorPercent: validator percent (0-100), e.g., convert.MustParseDecimal("10") for 10%
 // allowedFeeInstruments: allowed fee instrument strings
+// Values with more precision than the network scale (1e18) are an error.
 func BuildItemContractFees(
-	fee float64,
+	fee convert.Amount,
 	feeAddressB58 string,
-	burnPercent float64,
-	validatorPercent float64,
+	burnPercent convert.Amount,
+	validatorPercent convert.Amount,
 	allowedFeeInstruments []string,
 ) (*pb.ItemContractFees, error) {
-	const quintillion = 1e18
+	quintillion := big.NewInt(1e18)
 
 	// Convert fee to string in quintillion units
-	feeInt := int64(math.Round(fee * quintillion))
-	feeStr := fmt.Sprintf("%d", feeInt)
+	feeInt, err := fee.PartsAt(quintillion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fee: %v", err)
+	}
 
 	// Decode fee address from base58
 	feeAddrBytes, err := transcode.Base58Decode(feeAddressB58)
@@ -178,18 +212,24 @@ func BuildItemContractFees(
 		return nil, fmt.Errorf("failed to decode fee address: %v", err)
 	}
 
-	// Convert burn and validator percent to string in quintillion units
-	burnInt := int64(math.Round((burnPercent / 100.0) * quintillion))
-	burnStr := fmt.Sprintf("%d", burnInt)
+	// Convert burn and validator percent to string in quintillion units (100% = 1e18)
+	partsPerPercent := big.NewInt(1e16)
 
-	validatorInt := int64(math.Round((validatorPercent / 100.0) * quintillion))
-	validatorStr := fmt.Sprintf("%d", validatorInt)
+	burnInt, err := burnPercent.PartsAt(partsPerPercent)
+	if err != nil {
+		return nil, fmt.Errorf("invalid burn percent: %v", err)
+	}
+
+	validatorInt, err := validatorPercent.PartsAt(partsPerPercent)
+	if err != nil {
+		return nil, fmt.Errorf("invalid validator percent: %v", err)
+	}
 
 	return &pb.ItemContractFees{
-		Fee:                  feeStr,
+		Fee:                  feeInt.String(),
 		FeeAddress:           feeAddrBytes,
-		Burn:                 burnStr,
-		Validator:            validatorStr,
+		Burn:                 burnInt.String(),
+		Validator:            validatorInt.String(),
 		AllowedFeeInstrument: allowedFeeInstruments,
 	}, nil
 }
@@ -0,0 +1,183 @@
+package keystore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ZeraVision/zera-go-sdk/helper"
+)
+
+// Keyring is a directory of key files (<name>.json). Keys are listed from their metadata without a passphrase
+// and unlocked individually for signing.
+type Keyring struct {
+	dir string
+
+	mu       sync.RWMutex
+	unlocked map[string]*Key
+}
+
+// OpenKeyring opens (and creates if needed) a keyring directory, readable by the owner only.
+func OpenKeyring(dir string) (*Keyring, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	return &Keyring{dir: dir, unlocked: make(map[string]*Key)}, nil
+}
+
+func (k *Keyring) path(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid key name %q", name)
+	}
+	return filepath.Join(k.dir, name+".json"), nil
+}
+
+// List returns the metadata of every key (PrivateKey is not set), sorted by name.
+func (k *Keyring) List() ([]*Key, error) {
+	paths, err := filepath.Glob(filepath.Join(k.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*Key
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := parseFile(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		keys = append(keys, file.Key())
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+
+	return keys, nil
+}
+
+// Import encrypts key into the keyring, an existing key with the same name is not replaced.
+func (k *Keyring) Import(key *Key, passphrase string, options Options) error {
+	path, err := k.path(key.Name)
+	if err != nil {
+		return err
+	}
+
+	data, err := EncryptWithOptions(key, passphrase, options)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("key %s already exists", key.Name)
+		}
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(data)
+	return err
+}
+
+// ImportFile copies an encrypted key file into the keyring under its own name, without decrypting it.
+func (k *Keyring) ImportFile(data []byte) (*Key, error) {
+	file, err := parseFile(data)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := k.path(file.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("key %s already exists", file.Name)
+		}
+		return nil, err
+	}
+	defer out.Close()
+
+	if _, err := out.Write(data); err != nil {
+		return nil, err
+	}
+
+	return file.Key(), nil
+}
+
+// Export returns the decrypted key, ie to back it up or move it elsewhere.
+func (k *Keyring) Export(name, passphrase string) (*Key, error) {
+	path, err := k.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadFile(path, passphrase)
+}
+
+// ExportFile returns the encrypted key file as stored.
+func (k *Keyring) ExportFile(name string) ([]byte, error) {
+	path, err := k.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(path)
+}
+
+// Unlock decrypts a key and keeps it in memory until Lock, the returned signer can be passed to any builder.
+func (k *Keyring) Unlock(name, passphrase string) (helper.Signer, error) {
+	key, err := k.Export(name, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	k.unlocked[name] = key
+	k.mu.Unlock()
+
+	return key.Signer(), nil
+}
+
+// Signer returns the signer of an unlocked key.
+func (k *Keyring) Signer(name string) (helper.Signer, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.unlocked[name]
+	if !ok {
+		return nil, fmt.Errorf("key %s is locked", name)
+	}
+
+	return key.Signer(), nil
+}
+
+// Lock forgets an unlocked key. Signers already handed out keep working.
+func (k *Keyring) Lock(name string) {
+	k.mu.Lock()
+	delete(k.unlocked, name)
+	k.mu.Unlock()
+}
+
+// Delete removes a key file from the keyring.
+func (k *Keyring) Delete(name string) error {
+	path, err := k.path(name)
+	if err != nil {
+		return err
+	}
+
+	k.Lock(name)
+	return os.Remove(path)
+}
@@ -0,0 +1,357 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/wallet"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Version of the key file format. Version 2 authenticates the address, hash type and encryption options with the
+// key, files of other versions (including version 1, scrypt + AES-256-GCM only) are rejected with
+// ErrUnsupportedVersion.
+const Version = 2
+
+var ErrUnsupportedVersion = errors.New("unsupported keystore version")
+
+const (
+	KDFScrypt   = "scrypt"
+	KDFArgon2id = "argon2id"
+
+	CipherAESGCM            = "aes-256-gcm"
+	CipherXChaCha20Poly1305 = "xchacha20-poly1305"
+)
+
+const (
+	// scrypt cost, ~100ms and 64MB per unlock
+	scryptN = 1 << 16
+	scryptR = 8
+	scryptP = 1
+
+	// Argon2id cost (RFC 9106 second recommended option), 64MB per unlock
+	argonTime    = 3
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+
+	derivedKeyLen = 32
+)
+
+// Key is a decrypted key
+type Key struct {
+	Name       string          // label used to look the key up (ie by the remote signer)
+	PublicKey  string          // base58 public key as used by the builders (ie r_A_c_...)
+	PrivateKey string          // base58 private key
+	KeyType    helper.KeyType  // ED25519 or ED448
+	HashType   helper.HashType // optional, address hash (taken from the public key if not set)
+	Address    string          // optional, base58 wallet address (derived from the public key if not set)
+}
+
+// Options select the encryption of a key file, the zero value is scrypt + AES-256-GCM.
+type Options struct {
+	KDF    string // optional, KDFScrypt (default) or KDFArgon2id
+	Cipher string // optional, CipherAESGCM (default) or CipherXChaCha20Poly1305
+}
+
+// File is the JSON layout of an encrypted key file
+type File struct {
+	Version   int             `json:"version"`
+	Name      string          `json:"name"`
+	PublicKey string          `json:"publicKey"`
+	KeyType   helper.KeyType  `json:"keyType"`
+	HashType  helper.HashType `json:"hashType"`
+	Address   string          `json:"address"`
+	Crypto    Crypto          `json:"crypto"`
+}
+
+type Crypto struct {
+	KDF        string    `json:"kdf"` // scrypt or argon2id
+	KDFParams  KDFParams `json:"kdfParams"`
+	Cipher     string    `json:"cipher"`     // aes-256-gcm or xchacha20-poly1305
+	Nonce      string    `json:"nonce"`      // hex
+	Ciphertext string    `json:"ciphertext"` // hex, sealed base58 private key
+}
+
+type KDFParams struct {
+	N       int    `json:"n,omitempty"`       // scrypt
+	R       int    `json:"r,omitempty"`       // scrypt
+	P       int    `json:"p,omitempty"`       // scrypt
+	Time    uint32 `json:"time,omitempty"`    // argon2id
+	Memory  uint32 `json:"memory,omitempty"`  // argon2id, KiB
+	Threads uint8  `json:"threads,omitempty"` // argon2id
+	Salt    string `json:"salt"`              // hex
+}
+
+// Signer returns an in memory signer for the key.
+func (k *Key) Signer() helper.Signer {
+	return helper.NewPrivateKeySignerWithKeyType(k.PublicKey, k.PrivateKey, k.KeyType)
+}
+
+// Encrypt seals key with a key derived from passphrase, using scrypt and AES-256-GCM.
+func Encrypt(key *Key, passphrase string) ([]byte, error) {
+	return EncryptWithOptions(key, passphrase, Options{})
+}
+
+// EncryptWithOptions is Encrypt with a choice of KDF and cipher. The private key must belong to the public key.
+func EncryptWithOptions(key *Key, passphrase string, options Options) ([]byte, error) {
+	if key.Name == "" || key.PublicKey == "" || key.PrivateKey == "" {
+		return nil, errors.New("name, public key and private key are required")
+	}
+
+	file, err := newFile(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.KDF == "" {
+		options.KDF = KDFScrypt
+	}
+
+	if options.Cipher == "" {
+		options.Cipher = CipherAESGCM
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %v", err)
+	}
+
+	params := KDFParams{Salt: hex.EncodeToString(salt)}
+	switch options.KDF {
+	case KDFScrypt:
+		params.N, params.R, params.P = scryptN, scryptR, scryptP
+	case KDFArgon2id:
+		params.Time, params.Memory, params.Threads = argonTime, argonMemory, argonThreads
+	default:
+		return nil, fmt.Errorf("unsupported kdf %s", options.KDF)
+	}
+
+	file.Crypto = Crypto{KDF: options.KDF, KDFParams: params, Cipher: options.Cipher}
+
+	aead, err := file.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+
+	// the public metadata is authenticated so it can not be swapped to another key
+	ciphertext := aead.Seal(nil, nonce, []byte(key.PrivateKey), file.additionalData())
+
+	file.Crypto.Nonce = hex.EncodeToString(nonce)
+	file.Crypto.Ciphertext = hex.EncodeToString(ciphertext)
+
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// newFile fills the metadata of a key file and checks the key pair
+func newFile(key *Key) (*File, error) {
+	parsed, err := helper.ParsePublicKey(key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Special() || len(parsed.HashTokens) == 0 {
+		return nil, fmt.Errorf("public key %s can not be stored (requires an ED25519 / ED448 key with a hash prefix)", key.PublicKey)
+	}
+
+	if key.KeyType != 0 && key.KeyType != parsed.KeyType {
+		return nil, fmt.Errorf("key type %d does not match public key %s", key.KeyType, key.PublicKey)
+	}
+
+	_, address, err := wallet.GetAddressFromKey(parsed.Proto())
+	if err != nil {
+		return nil, err
+	}
+
+	if key.Address != "" && key.Address != address {
+		return nil, fmt.Errorf("address %s does not match public key %s", key.Address, key.PublicKey)
+	}
+
+	// refuse keys that do not belong together
+	signature, err := helper.Sign(key.PrivateKey, []byte(key.Name), parsed.KeyType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	if ok, err := helper.Verify(key.PublicKey, []byte(key.Name), signature); !ok {
+		return nil, fmt.Errorf("private key does not match public key: %v", err)
+	}
+
+	return &File{
+		Version:   Version,
+		Name:      key.Name,
+		PublicKey: key.PublicKey,
+		KeyType:   parsed.KeyType,
+		HashType:  parsed.HashTokens[0],
+		Address:   address,
+	}, nil
+}
+
+// Decrypt opens an encrypted key file.
+func Decrypt(data []byte, passphrase string) (*Key, error) {
+	file, err := parseFile(data)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := file.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := hex.DecodeString(file.Crypto.Nonce)
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid key file nonce")
+	}
+
+	ciphertext, err := hex.DecodeString(file.Crypto.Ciphertext)
+	if err != nil {
+		return nil, errors.New("invalid key file ciphertext")
+	}
+
+	privateKey, err := aead.Open(nil, nonce, ciphertext, file.additionalData())
+	if err != nil {
+		return nil, errors.New("failed to decrypt key (wrong passphrase or tampered file)")
+	}
+
+	key := file.Key()
+	key.PrivateKey = string(privateKey)
+
+	return key, nil
+}
+
+func parseFile(data []byte) (*File, error) {
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse key file: %v", err)
+	}
+
+	if file.Version != Version {
+		return nil, fmt.Errorf("%w %d (expected %d)", ErrUnsupportedVersion, file.Version, Version)
+	}
+
+	return &file, nil
+}
+
+// Key returns the metadata of the file, without the private key.
+func (f *File) Key() *Key {
+	return &Key{
+		Name:      f.Name,
+		PublicKey: f.PublicKey,
+		KeyType:   f.KeyType,
+		HashType:  f.HashType,
+		Address:   f.Address,
+	}
+}
+
+// WriteFile encrypts key and writes it to path, readable by the owner only.
+func WriteFile(path string, key *Key, passphrase string) error {
+	data, err := Encrypt(key, passphrase)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// ReadFile reads and decrypts the key file at path.
+func ReadFile(path string, passphrase string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := Decrypt(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+func (f *File) additionalData() []byte {
+	return []byte(fmt.Sprintf("%d|%s|%s|%d|%d|%s|%s|%s", f.Version, f.Name, f.PublicKey, f.KeyType, f.HashType, f.Address, f.Crypto.KDF, f.Crypto.Cipher))
+}
+
+func (f *File) aead(passphrase string) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is required")
+	}
+
+	params := f.Crypto.KDFParams
+
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, errors.New("invalid key file salt")
+	}
+
+	var derived []byte
+	switch f.Crypto.KDF {
+	case KDFScrypt:
+		if params.N > 1<<20 || params.R > 32 || params.P > 16 {
+			return nil, errors.New("key file kdf parameters are too expensive")
+		}
+
+		derived, err = scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, derivedKeyLen)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %v", err)
+		}
+
+	case KDFArgon2id:
+		if params.Time == 0 || params.Threads == 0 || params.Time > 16 || params.Memory > 1<<20 {
+			return nil, errors.New("invalid or too expensive key file kdf parameters")
+		}
+
+		derived = argon2.IDKey([]byte(passphrase), salt, params.Time, params.Memory, params.Threads, derivedKeyLen)
+
+	default:
+		return nil, fmt.Errorf("unsupported kdf %s", f.Crypto.KDF)
+	}
+
+	switch f.Crypto.Cipher {
+	case CipherAESGCM:
+		block, err := aes.NewCipher(derived)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+
+	case CipherXChaCha20Poly1305:
+		return chacha20poly1305.NewX(derived)
+
+	default:
+		return nil, fmt.Errorf("unsupported cipher %s", f.Crypto.Cipher)
+	}
+}
+
+// LoadDir decrypts every *.json key file in dir with the same passphrase.
+func LoadDir(dir string, passphrase string) ([]*Key, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*Key
+	for _, path := range paths {
+		key, err := ReadFile(path, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,213 @@
+package keystore_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/keystore"
+)
+
+const (
+	TEST_PUBLIC  = "A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"
+	TEST_PRIVATE = "2ap5CkCekErkqJ4UuSGAW1BmRRRNr8hXaebudv1j8TY6mJMSsbnniakorFGmetE4aegsyQAD8WX1N8Q2Y45YEBDs"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	key := &keystore.Key{Name: "treasury", PublicKey: TEST_PUBLIC, PrivateKey: TEST_PRIVATE, KeyType: helper.ED25519}
+
+	data, err := keystore.Encrypt(key, "correct horse")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if strings.Contains(string(data), TEST_PRIVATE) {
+		t.Fatal("Expected the private key to be encrypted")
+	}
+
+	decrypted, err := keystore.Decrypt(data, "correct horse")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if decrypted.PublicKey != key.PublicKey || decrypted.PrivateKey != key.PrivateKey || decrypted.Name != key.Name {
+		t.Errorf("Expected %+v, got %+v", key, decrypted)
+	}
+
+	if decrypted.HashType != helper.BLAKE3 || decrypted.Address == "" {
+		t.Errorf("Expected the address metadata to be set, got %+v", decrypted)
+	}
+
+	if _, err := keystore.Decrypt(data, "wrong horse"); err == nil {
+		t.Error("Expected an error for wrong passphrase, got none")
+	}
+
+	// metadata is authenticated
+	tampered := strings.Replace(string(data), `"name": "treasury"`, `"name": "other"`, 1)
+	if _, err := keystore.Decrypt([]byte(tampered), "correct horse"); err == nil {
+		t.Error("Expected an error for tampered metadata, got none")
+	}
+
+	unsupported := strings.Replace(string(data), `"version": 2`, `"version": 3`, 1)
+	if _, err := keystore.Decrypt([]byte(unsupported), "correct horse"); !errors.Is(err, keystore.ErrUnsupportedVersion) {
+		t.Errorf("Expected an unsupported version error, got %v", err)
+	}
+}
+
+func TestDecrypt_Version1(t *testing.T) {
+	// version 1 layout: no address metadata, scrypt + AES-256-GCM
+	v1 := `{
+		"version": 1,
+		"name": "treasury",
+		"publicKey": "` + TEST_PUBLIC + `",
+		"keyType": 0,
+		"crypto": {
+			"kdf": "scrypt",
+			"kdfParams": {"n": 32768, "r": 8, "p": 1, "salt": "73616c7473616c7473616c7473616c74"},
+			"cipher": "aes-256-gcm",
+			"nonce": "6e6f6e63656e6f6e63656e6f",
+			"ciphertext": "63697068657274657874"
+		}
+	}`
+
+	_, err := keystore.Decrypt([]byte(v1), "correct horse")
+	if !errors.Is(err, keystore.ErrUnsupportedVersion) {
+		t.Fatalf("Expected an unsupported version error, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "version 1") {
+		t.Errorf("Expected the error to name version 1, got %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "treasury.json"), []byte(v1), 0600); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	keyring, err := keystore.OpenKeyring(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := keyring.List(); !errors.Is(err, keystore.ErrUnsupportedVersion) {
+		t.Errorf("Expected an unsupported version error, got %v", err)
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+
+	key := &keystore.Key{Name: "treasury", PublicKey: TEST_PUBLIC, PrivateKey: TEST_PRIVATE, KeyType: helper.ED25519}
+	if err := keystore.WriteFile(filepath.Join(dir, "treasury.json"), key, "correct horse"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	keys, err := keystore.LoadDir(dir, "correct horse")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(keys) != 1 || keys[0].Signer().PublicKey() != TEST_PUBLIC {
+		t.Fatalf("Expected the treasury key, got %+v", keys)
+	}
+}
+
+func TestEncryptOptions(t *testing.T) {
+	key := &keystore.Key{Name: "treasury", PublicKey: TEST_PUBLIC, PrivateKey: TEST_PRIVATE}
+
+	data, err := keystore.EncryptWithOptions(key, "correct horse", keystore.Options{KDF: keystore.KDFArgon2id, Cipher: keystore.CipherXChaCha20Poly1305})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	decrypted, err := keystore.Decrypt(data, "correct horse")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if decrypted.PrivateKey != TEST_PRIVATE || decrypted.KeyType != helper.ED25519 {
+		t.Errorf("Expected the treasury key, got %+v", decrypted)
+	}
+
+	if _, err := keystore.Decrypt(data, "wrong horse"); err == nil {
+		t.Error("Expected an error for wrong passphrase, got none")
+	}
+
+	// the private key must belong to the public key
+	other := &keystore.Key{Name: "other", PublicKey: "A_c_8tpnyGqNbTsDv3z8TLSmAtrtRrTvm1Sp9r1yCYb5o3yC", PrivateKey: TEST_PRIVATE}
+	if _, err := keystore.Encrypt(other, "correct horse"); err == nil {
+		t.Error("Expected an error for a mismatched key pair, got none")
+	}
+}
+
+func TestKeyring(t *testing.T) {
+	keyring, err := keystore.OpenKeyring(filepath.Join(t.TempDir(), "keys"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	key := &keystore.Key{Name: "treasury", PublicKey: TEST_PUBLIC, PrivateKey: TEST_PRIVATE}
+	if err := keyring.Import(key, "correct horse", keystore.Options{KDF: keystore.KDFArgon2id}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := keyring.Import(key, "correct horse", keystore.Options{}); err == nil {
+		t.Error("Expected an error for an existing key, got none")
+	}
+
+	if err := keyring.Import(&keystore.Key{Name: "../treasury", PublicKey: TEST_PUBLIC, PrivateKey: TEST_PRIVATE}, "correct horse", keystore.Options{}); err == nil {
+		t.Error("Expected an error for an invalid name, got none")
+	}
+
+	keys, err := keyring.List()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(keys) != 1 || keys[0].Name != "treasury" || keys[0].PrivateKey != "" || keys[0].Address == "" {
+		t.Fatalf("Expected the treasury metadata, got %+v", keys)
+	}
+
+	if _, err := keyring.Signer("treasury"); err == nil {
+		t.Error("Expected an error for a locked key, got none")
+	}
+
+	signer, err := keyring.Unlock("treasury", "correct horse")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	signature, err := signer.Sign([]byte("payload"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if ok, err := helper.Verify(TEST_PUBLIC, []byte("payload"), signature); !ok {
+		t.Fatalf("Expected a valid signature, got %v", err)
+	}
+
+	keyring.Lock("treasury")
+	if _, err := keyring.Signer("treasury"); err == nil {
+		t.Error("Expected an error for a locked key, got none")
+	}
+
+	exported, err := keyring.Export("treasury", "correct horse")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if exported.PrivateKey != TEST_PRIVATE {
+		t.Errorf("Expected the private key to be exported, got %+v", exported)
+	}
+
+	if err := keyring.Delete("treasury"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if keys, _ := keyring.List(); len(keys) != 0 {
+		t.Errorf("Expected no keys, got %+v", keys)
+	}
+}
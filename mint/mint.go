@@ -3,16 +3,16 @@ package mint
 import (
 	"context"
 	"fmt"
-	"strings"
+	"math/big"
 	"time"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/convert"
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
+	"github.com/ZeraVision/zera-go-sdk/parts"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
-	"github.com/ZeraVision/zera-go-sdk/wallet"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -23,7 +23,7 @@ import (
 // Parameters:
 // - useIndexer: true for data grab from indexer, false for validator
 // - symbol: contract symbol to mint (example: $ZRA+0000)
-// - amount: amount to mint, in full coins (as a string, not parts)
+// - amount: amount to mint in parts, not full coins (see CreateMintTxnWithAmount)
 // - recipient: Base58-encoded address to receive the minted tokens
 // - publicKeyBase58: Base58-encoded public key of the minting authority
 // - privateKeyBase58: Base58-encoded private key corresponding to the above public key
@@ -34,6 +34,40 @@ import (
 // - *pb.MintTXN: the constructed and signed MintTXN
 // - error: if any step in construction or signing fails
 func CreateMintTxn(nonceInfo nonce.NonceInfo, symbol string, amount string, recipient string, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.MintTXN, error) {
+	return CreateMintTxnWithContext(context.Background(), nonceInfo, symbol, amount, recipient, publicKeyBase58, privateKeyBase58, feeID, feeAmountParts)
+}
+
+// CreateMintTxnWithContext is CreateMintTxn with a context that is passed through to the nonce lookup.
+func CreateMintTxnWithContext(ctx context.Context, nonceInfo nonce.NonceInfo, symbol string, amount string, recipient string, publicKeyBase58 string, privateKeyBase58 string, feeID string, feeAmountParts string) (*pb.MintTXN, error) {
+	return CreateMintTxnWithSigner(ctx, nonceInfo, symbol, amount, recipient, helper.NewPrivateKeySigner(publicKeyBase58, privateKeyBase58), feeID, feeAmountParts)
+}
+
+// CreateMintTxnWithAmount is CreateMintTxnWithSigner with the amount in full coins of the contract partsInfo looks up,
+// ie convert.ParseAmount("25", partsPerCoin). An amount at another denomination (ie convert.ParseDecimal("25")) is an
+// error rather than minted as its parts.
+func CreateMintTxnWithAmount(ctx context.Context, nonceInfo nonce.NonceInfo, partsInfo parts.PartsInfo, amount convert.Amount, recipient string, signer helper.Signer, feeID string, feeAmountParts string) (*pb.MintTXN, error) {
+	partsPerCoin, err := parts.GetPartsWithContext(ctx, partsInfo)
+	if err != nil {
+		return nil, fmt.Errorf("could not get parts: %v", err)
+	}
+
+	amountParts, err := amount.PartsIn(partsPerCoin)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount for %s: %v", partsInfo.Symbol, err)
+	}
+
+	return CreateMintTxnWithSigner(ctx, nonceInfo, partsInfo.Symbol, amountParts.String(), recipient, signer, feeID, feeAmountParts)
+}
+
+// CreateMintTxnWithSigner is CreateMintTxn with the transaction signed by signer instead of a base58 private key.
+func CreateMintTxnWithSigner(ctx context.Context, nonceInfo nonce.NonceInfo, symbol string, amount string, recipient string, signer helper.Signer, feeID string, feeAmountParts string) (*pb.MintTXN, error) {
+	publicKeyBase58 := signer.PublicKey()
+
+	// amounts are parts, a decimal amount of coins is an error rather than truncated
+	if parts, ok := new(big.Int).SetString(amount, 10); !ok || parts.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount %q, expected a whole number of parts", amount)
+	}
+
 	// Step 1: Decode recipient address
 	recipientBytes, err := transcode.Base58Decode(recipient)
 	if err != nil {
@@ -41,12 +75,12 @@ func CreateMintTxn(nonceInfo nonce.NonceInfo, symbol string, amount string, reci
 	}
 
 	// Step 2: Decode public key
-	_, _, pubKeyBytes, err := transcode.Base58DecodePublicKey(publicKeyBase58)
+	txnPublicKey, err := helper.TxnPublicKey(signer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode public key: %v", err)
 	}
 
-	nonce, err := nonce.GetNonce(nonceInfo, 5)
+	nonce, err := nonce.GetNonceWithContext(ctx, nonceInfo, nonce.DefaultConcurrency)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nonce: %v", err)
@@ -58,21 +92,13 @@ func CreateMintTxn(nonceInfo nonce.NonceInfo, symbol string, amount string, reci
 
 	// Step 3: Create BaseTXN
 	base := &pb.BaseTXN{
-		PublicKey: &pb.PublicKey{},
+		PublicKey: txnPublicKey,
 		FeeId:     feeID,
 		FeeAmount: feeAmountParts,
 		Timestamp: timestamppb.New(time.Now().UTC()),
 		Nonce:     nonce[0],
 	}
 
-	if strings.HasPrefix(publicKeyBase58, "gov_") {
-		base.PublicKey.GovernanceAuth = pubKeyBytes
-	} else if strings.HasPrefix(publicKeyBase58, "sc_") {
-		base.PublicKey.SmartContractAuth = pubKeyBytes
-	} else {
-		base.PublicKey.Single = pubKeyBytes
-	}
-
 	// Step 4: Construct MintTXN
 	mintTxn := &pb.MintTXN{
 		Base:             base,
@@ -89,17 +115,11 @@ func CreateMintTxn(nonceInfo nonce.NonceInfo, symbol string, amount string, reci
 
 	// Step 6: Verify and determine key
 	// Check to ensure its a restricted key
-	if !strings.HasPrefix(publicKeyBase58, "r_") && !strings.HasPrefix(publicKeyBase58, "gov_") && !strings.HasPrefix(publicKeyBase58, "sc_") {
+	if !helper.IsRestricted(txnPublicKey) && txnPublicKey.GovernanceAuth == nil && txnPublicKey.SmartContractAuth == nil {
 		return nil, fmt.Errorf("not possible to do restricted logic (requires r_, gov_, or sc_ key): %s", publicKeyBase58)
 	}
 
-	// Ed25519
-	keyType, err := wallet.DetermineKeyType(publicKeyBase58)
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine key type: %v", err)
-	}
-
-	signature, err := helper.Sign(privateKeyBase58, byteDataNoSig, keyType)
+	signature, err := helper.SignWith(signer, byteDataNoSig)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign mint transaction: %v", err)
@@ -123,20 +143,26 @@ func CreateMintTxn(nonceInfo nonce.NonceInfo, symbol string, amount string, reci
 
 // SendMintTXN submits a MintTXN to the network via gRPC
 func SendMintTXN(grpcAddr string, txn *pb.MintTXN) (*emptypb.Empty, error) {
-	if !strings.Contains(grpcAddr, ":") {
-		grpcAddr += ":50052"
-	}
+	return SendMintTXNWithContext(context.Background(), grpcAddr, txn)
+}
+
+// SendMintTXNWithContext is SendMintTXN with a context that is passed through to the gRPC call.
+func SendMintTXNWithContext(ctx context.Context, grpcAddr string, txn *pb.MintTXN) (*emptypb.Empty, error) {
+	return SendMintTXNWithSecurity(ctx, grpcAddr, txn, nil)
+}
 
-	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// SendMintTXNWithSecurity is SendMintTXNWithContext with the validator dialed using security (plaintext if nil).
+func SendMintTXNWithSecurity(ctx context.Context, grpcAddr string, txn *pb.MintTXN, security *helper.TransportSecurity) (*emptypb.Empty, error) {
+	conn, err := helper.NewConnection(grpcAddr, "50052", security)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 
 	client := pb.NewTXNServiceClient(conn)
-	response, err := client.Mint(context.Background(), txn)
+	response, err := client.Mint(ctx, txn)
 	if err != nil {
-		return nil, fmt.Errorf("mint transaction failed: %v", err)
+		return nil, fmt.Errorf("mint transaction failed: %w", zeraerr.FromGRPC(err))
 	}
 
 	return response, nil
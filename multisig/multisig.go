@@ -0,0 +1,242 @@
+// Package multisig lets a multi-key (pb.MultiKey) account authorize transactions.
+//
+// Build the transaction with an Account as the signer (CreateXxxWithSigner or transfer.Inputs.Signer), wrap it with
+// offline.Prepare and hand the envelope to the key holders. Each holder signs their copy with offline.Sign, the copies
+// are combined with Envelope.Merge and offline.Assemble checks the signatures against the MultiPatterns before
+// producing the submittable transaction.
+package multisig
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/ZeraVision/zera-go-sdk/wallet"
+	"google.golang.org/protobuf/proto"
+)
+
+// Member is a key of a multi-key account
+type Member struct {
+	Class     uint32
+	PublicKey string // base58 public key, ie A_... (multi-key members carry no hash prefix)
+}
+
+// Account is a multi-key account usable as the signer of any builder. It does not sign itself,
+// its members sign the prepared envelope (see package documentation).
+type Account struct {
+	key *pb.PublicKey
+	id  string
+}
+
+var _ helper.TxnKeySigner = (*Account)(nil)
+
+// NewAccount validates multi and builds the transaction key for it. Member public keys may be given with or
+// without their class prefix (ie "1_A_..." or "A_...").
+func NewAccount(multi helper.MultiKeyHelper) (*Account, error) {
+	normalized := multi
+	normalized.MultiKey = make([]helper.MultiKey, len(multi.MultiKey))
+
+	for i, key := range multi.MultiKey {
+		publicKey := strings.TrimPrefix(key.PublicKey, strconv.FormatUint(uint64(key.Class), 10)+"_")
+
+		parsed, err := helper.ParsePublicKey(publicKey)
+		if err != nil || parsed.Restricted || parsed.Special() {
+			return nil, fmt.Errorf("invalid multi-key member %s", key.PublicKey)
+		}
+
+		// members carry no hash tokens, those are set once for the whole key
+		parsed.HashTokens = nil
+
+		normalized.MultiKey[i] = helper.MultiKey{
+			Class:     key.Class,
+			PublicKey: strconv.FormatUint(uint64(key.Class), 10) + "_" + parsed.String(),
+		}
+	}
+
+	key, err := helper.GeneratePublicKey(helper.PublicKey{Multi: &normalized})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Validate(key.Multi); err != nil {
+		return nil, err
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// no public key exists for the account, the id can not be mistaken for one (":" is not base58)
+	id := "multisig:" + transcode.Base58Encode(transcode.SHA3256(data))
+
+	return &Account{key: key, id: id}, nil
+}
+
+// ID identifies the account in logs and errors (multisig:<hash of the multi-key>). It is not a public key.
+func (a *Account) ID() string { return a.id }
+
+// PublicKey returns ID, builders put TxnPublicKey in the transaction.
+func (a *Account) PublicKey() string { return a.id }
+
+func (a *Account) KeyType() helper.KeyType { return helper.SPECIAL }
+
+// Sign returns no signature, the members sign the prepared envelope
+func (a *Account) Sign(payload []byte) ([]byte, error) { return nil, nil }
+
+func (a *Account) TxnPublicKey() (*pb.PublicKey, error) {
+	return proto.Clone(a.key).(*pb.PublicKey), nil
+}
+
+// Address returns the wallet address of the account. It is wallet.ErrMultiKeyAddress until deriving multi-key
+// addresses is implemented (see wallet.GetAddressFromKey).
+func (a *Account) Address() (string, error) {
+	_, address, err := wallet.GetAddressFromKey(a.key)
+	if err != nil {
+		return "", err
+	}
+	return address, nil
+}
+
+// Members returns the members of the account in key order.
+func (a *Account) Members() []Member {
+	members, _ := Members(a.key.Multi)
+	return members
+}
+
+// Members decodes the members of a multi-key (class_keytype_decodedpublickey) in key order.
+func Members(key *pb.MultiKey) ([]Member, error) {
+	var members []Member
+	for _, publicKey := range key.GetPublicKeys() {
+		member, err := parseMember(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func parseMember(publicKey []byte) (Member, error) {
+	parts := strings.SplitN(string(publicKey), "_", 2)
+	if len(parts) != 2 {
+		return Member{}, errors.New("multi-key member is not in class_keytype_key format")
+	}
+
+	class, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return Member{}, fmt.Errorf("multi-key member has an invalid class %q", parts[0])
+	}
+
+	parsed, err := helper.ParsePublicKeyBytes(publicKey[len(parts[0])+1:])
+	if err != nil || parsed.Restricted || parsed.Special() || len(parsed.HashTokens) > 0 {
+		return Member{}, fmt.Errorf("multi-key member is not in class_keytype_key format")
+	}
+
+	return Member{Class: uint32(class), PublicKey: parsed.String()}, nil
+}
+
+// Validate checks that the patterns of a multi-key can be satisfied by its members: every pattern lists each class
+// once with a required count of at least 1, and there are enough members of the class to meet it.
+func Validate(key *pb.MultiKey) error {
+	if key == nil {
+		return errors.New("multi-key is required")
+	}
+
+	members, err := Members(key)
+	if err != nil {
+		return err
+	}
+
+	if len(members) == 0 {
+		return errors.New("multi-key has no members")
+	}
+
+	seen := map[string]bool{}
+	classCount := map[uint32]uint32{}
+	for _, member := range members {
+		if seen[member.PublicKey] {
+			return fmt.Errorf("multi-key member %s is listed twice", member.PublicKey)
+		}
+		seen[member.PublicKey] = true
+		classCount[member.Class]++
+	}
+
+	if len(key.GetMultiPatterns()) == 0 {
+		return errors.New("multi-key has no patterns")
+	}
+
+	if len(key.GetHashTokens()) == 0 {
+		return errors.New("multi-key has no hash tokens")
+	}
+
+	for i, pattern := range key.GetMultiPatterns() {
+		if len(pattern.Class) == 0 || len(pattern.Class) != len(pattern.Required) {
+			return fmt.Errorf("pattern %d: class and required must be non empty and of equal length", i)
+		}
+
+		classes := map[uint32]bool{}
+		for j, class := range pattern.Class {
+			if classes[class] {
+				return fmt.Errorf("pattern %d: class %d is listed twice", i, class)
+			}
+			classes[class] = true
+
+			if pattern.Required[j] == 0 {
+				return fmt.Errorf("pattern %d: class %d requires no signatures", i, class)
+			}
+
+			if classCount[class] < pattern.Required[j] {
+				return fmt.Errorf("pattern %d: class %d requires %d signatures but has %d members", i, class, pattern.Required[j], classCount[class])
+			}
+		}
+	}
+
+	return nil
+}
+
+// Satisfied reports whether the members that signed (signed[i] for member i) meet at least one pattern.
+func Satisfied(key *pb.MultiKey, signed []bool) error {
+	members, err := Members(key)
+	if err != nil {
+		return err
+	}
+
+	if len(signed) != len(members) {
+		return fmt.Errorf("expected %d signature flags, got %d", len(members), len(signed))
+	}
+
+	signedCount := map[uint32]uint32{}
+	for i, member := range members {
+		if signed[i] {
+			signedCount[member.Class]++
+		}
+	}
+
+	var unmet []string
+	for i, pattern := range key.GetMultiPatterns() {
+		ok := true
+		for j, class := range pattern.Class {
+			if j >= len(pattern.Required) {
+				ok = false
+				break
+			}
+
+			if signedCount[class] < pattern.Required[j] {
+				ok = false
+				unmet = append(unmet, fmt.Sprintf("pattern %d needs %d of class %d (has %d)", i, pattern.Required[j], class, signedCount[class]))
+				break
+			}
+		}
+
+		if ok {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no signature pattern is satisfied: %s", strings.Join(unmet, "; "))
+}
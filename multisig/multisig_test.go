@@ -0,0 +1,230 @@
+package multisig_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/governance"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/mint"
+	"github.com/ZeraVision/zera-go-sdk/multisig"
+	"github.com/ZeraVision/zera-go-sdk/nonce"
+	"github.com/ZeraVision/zera-go-sdk/offline"
+	"github.com/ZeraVision/zera-go-sdk/parts"
+	"github.com/ZeraVision/zera-go-sdk/transfer"
+	"github.com/ZeraVision/zera-go-sdk/wallet"
+)
+
+const TEST_ADDRESS = "8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR"
+
+type testKey struct {
+	public  string
+	private string
+}
+
+func generateKeys(t *testing.T) []testKey {
+	t.Helper()
+
+	var keys []testKey
+	for _, mnemonic := range []string{"multisig one", "multisig two", "multisig three"} {
+		private, public, _, err := wallet.GenerateEd25519(mnemonic, helper.BLAKE3, helper.ED25519)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		keys = append(keys, testKey{public, private})
+	}
+
+	private, public, _, err := wallet.GenerateEd448("multisig four", helper.BLAKE3, helper.ED448)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	return append(keys, testKey{public, private})
+}
+
+// 2 of the 3 class 1 keys, or 1 class 1 key together with the class 2 key
+func testAccount(t *testing.T, keys []testKey) *multisig.Account {
+	t.Helper()
+
+	account, err := multisig.NewAccount(helper.MultiKeyHelper{
+		MultiKey: []helper.MultiKey{
+			{Class: 1, PublicKey: keys[0].public},
+			{Class: 1, PublicKey: keys[1].public},
+			{Class: 1, PublicKey: keys[2].public},
+			{Class: 2, PublicKey: keys[3].public},
+		},
+		Pattern: [][]helper.MultiPatterns{
+			{{Class: 1, Required: 2}},
+			{{Class: 1, Required: 1}, {Class: 2, Required: 1}},
+		},
+		HashTokens: []helper.HashType{helper.BLAKE3},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	return account
+}
+
+func TestNewAccount(t *testing.T) {
+	keys := generateKeys(t)
+	account := testAccount(t, keys)
+
+	members := account.Members()
+	if len(members) != 4 || members[0].Class != 1 || members[3].Class != 2 {
+		t.Fatalf("Expected 4 members in classes 1 and 2, got %+v", members)
+	}
+
+	if members[0].PublicKey != "A_"+keys[0].public[len("A_c_"):] {
+		t.Errorf("Expected member key without hash prefix, got %s", members[0].PublicKey)
+	}
+
+	if address, err := account.Address(); !errors.Is(err, wallet.ErrMultiKeyAddress) {
+		t.Errorf("Expected the account address to be unimplemented, got %s (%v)", address, err)
+	}
+
+	if _, err := helper.ParsePublicKey(account.ID()); err == nil || account.PublicKey() != account.ID() {
+		t.Errorf("Expected an id that is not a public key, got %s", account.ID())
+	}
+
+	// restriction is decided by the multi-key hash tokens, the account is not restricted
+	if _, err := mint.CreateMintTxnWithSigner(context.Background(), nonce.NonceInfo{Override: []uint64{1}}, "$ZRA+0000", "1", TEST_ADDRESS, account, "$ZRA+0000", "1000000"); err == nil {
+		t.Error("Expected an error minting with an unrestricted account, got none")
+	}
+
+	tests := []struct {
+		name    string
+		pattern [][]helper.MultiPatterns
+	}{
+		{"no patterns", nil},
+		{"too many required", [][]helper.MultiPatterns{{{Class: 2, Required: 2}}}},
+		{"unknown class", [][]helper.MultiPatterns{{{Class: 3, Required: 1}}}},
+		{"zero required", [][]helper.MultiPatterns{{{Class: 1, Required: 0}}}},
+		{"class twice", [][]helper.MultiPatterns{{{Class: 1, Required: 1}, {Class: 1, Required: 1}}}},
+	}
+
+	for _, tt := range tests {
+		_, err := multisig.NewAccount(helper.MultiKeyHelper{
+			MultiKey: []helper.MultiKey{
+				{Class: 1, PublicKey: keys[0].public},
+				{Class: 2, PublicKey: keys[3].public},
+			},
+			Pattern:    tt.pattern,
+			HashTokens: []helper.HashType{helper.BLAKE3},
+		})
+		if err == nil {
+			t.Errorf("%s: expected an error, got none", tt.name)
+		}
+	}
+}
+
+func TestMultiSigVote(t *testing.T) {
+	keys := generateKeys(t)
+	account := testAccount(t, keys)
+
+	support := true
+	txn, err := governance.CreateVoteTxnWithSigner(context.Background(), nonce.NonceInfo{Override: []uint64{1}}, "$ZRA+0000",
+		"aa", account, "$ZRA+0000", "1000000000", &support, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if txn.Base.PublicKey.Multi == nil {
+		t.Fatal("Expected a multi-key transaction")
+	}
+
+	env, err := offline.Prepare(txn)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(env.Signers) != 4 {
+		t.Fatalf("Expected 4 signer slots, got %d", len(env.Signers))
+	}
+
+	// each holder signs their own copy
+	first, second := *env, *env
+	first.Signers = append([]offline.Slot(nil), env.Signers...)
+	second.Signers = append([]offline.Slot(nil), env.Signers...)
+
+	if err := offline.Sign(&first, helper.NewPrivateKeySigner(keys[0].public, keys[0].private)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := offline.Assemble(&first); err == nil {
+		t.Fatal("Expected an error for an unsatisfied pattern, got none")
+	}
+
+	if missing, err := first.Missing(); err != nil || len(missing) != 3 {
+		t.Errorf("Expected 3 keys that can still sign, got %v", missing)
+	}
+
+	if err := offline.Sign(&second, helper.NewPrivateKeySigner(keys[3].public, keys[3].private)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := first.Merge(&second); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	message, err := offline.Assemble(&first)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	vote := message.(*pb.GovernanceVote)
+	signatures := vote.Base.PublicKey.Multi.Signatures
+	if len(signatures) != 4 || len(signatures[0]) == 0 || len(signatures[1]) != 0 || len(signatures[3]) == 0 {
+		t.Fatalf("Expected signatures from members 0 and 3, got %d", len(signatures))
+	}
+
+	if ok, err := helper.Verify(keys[3].public, first.Payload, signatures[3]); !ok {
+		t.Errorf("Expected a valid signature, got %v", err)
+	}
+
+	if len(vote.Base.Hash) != 32 {
+		t.Errorf("Expected a SHA3-256 hash, got %d bytes", len(vote.Base.Hash))
+	}
+}
+
+func TestMultiSigCoinTxn(t *testing.T) {
+	keys := generateKeys(t)
+	account := testAccount(t, keys)
+
+	txn, err := transfer.CreateCoinTxn(
+		nonce.NonceInfo{Override: []uint64{4}},
+		parts.PartsInfo{Symbol: "$ZRA+0000", Override: big.NewInt(1000000000)},
+		[]transfer.Inputs{{B58Address: TEST_ADDRESS, Signer: account, Amount: "2", FeePercent: 100}},
+		map[string]string{TEST_ADDRESS: "2"},
+		"$ZRA+0000", "1000000000", nil, nil, 5,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	env, err := offline.Prepare(txn)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err = offline.Sign(env,
+		helper.NewPrivateKeySigner(keys[1].public, keys[1].private),
+		helper.NewPrivateKeySigner(keys[2].public, keys[2].private),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	message, err := offline.Assemble(env)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	coin := message.(*pb.CoinTXN)
+	if len(coin.Auth.Signature) != 1 || len(coin.Auth.PublicKey[0].Multi.Signatures) != 4 {
+		t.Fatalf("Expected the multi-key signatures on the input key, got %+v", coin.Auth)
+	}
+}
@@ -4,15 +4,13 @@ import (
 	"context"
 	"fmt"
 	"math/big"
-	"strings"
 	"time"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -48,6 +46,39 @@ func CreateNftTransfer(
 	feeAmountParts string,
 	contractFeeID *string,
 	contractFeeAmountParts *big.Int,
+) (*pb.NFTTXN, error) {
+	return CreateNftTransferWithContext(context.Background(), nonceInfo, symbol, itemID, recipientBase58, publicKeyBase58, privateKeyBase58, feeID, feeAmountParts, contractFeeID, contractFeeAmountParts)
+}
+
+// CreateNftTransferWithContext is CreateNftTransfer with a context that is passed through to the nonce lookup.
+func CreateNftTransferWithContext(
+	ctx context.Context,
+	nonceInfo nonce.NonceInfo,
+	symbol string,
+	itemID *big.Int,
+	recipientBase58 string,
+	publicKeyBase58 string,
+	privateKeyBase58 string,
+	feeID string,
+	feeAmountParts string,
+	contractFeeID *string,
+	contractFeeAmountParts *big.Int,
+) (*pb.NFTTXN, error) {
+	return CreateNftTransferWithSigner(ctx, nonceInfo, symbol, itemID, recipientBase58, helper.NewPrivateKeySigner(publicKeyBase58, privateKeyBase58), feeID, feeAmountParts, contractFeeID, contractFeeAmountParts)
+}
+
+// CreateNftTransferWithSigner is CreateNftTransfer with the transaction signed by signer instead of a base58 private key.
+func CreateNftTransferWithSigner(
+	ctx context.Context,
+	nonceInfo nonce.NonceInfo,
+	symbol string,
+	itemID *big.Int,
+	recipientBase58 string,
+	signer helper.Signer,
+	feeID string,
+	feeAmountParts string,
+	contractFeeID *string,
+	contractFeeAmountParts *big.Int,
 ) (*pb.NFTTXN, error) {
 	// Step 1: Decode recipient address
 	recipientBytes, err := transcode.Base58Decode(recipientBase58)
@@ -56,12 +87,12 @@ func CreateNftTransfer(
 	}
 
 	// Step 2: Decode public key
-	_, _, pubKeyBytes, err := transcode.Base58DecodePublicKey(publicKeyBase58)
+	txnPublicKey, err := helper.TxnPublicKey(signer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode public key: %v", err)
 	}
 
-	nonce, err := nonce.GetNonce(nonceInfo, 5)
+	nonce, err := nonce.GetNonceWithContext(ctx, nonceInfo, nonce.DefaultConcurrency)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nonce: %v", err)
 	}
@@ -71,9 +102,7 @@ func CreateNftTransfer(
 
 	// Step 3: Create BaseTXN
 	base := &pb.BaseTXN{
-		PublicKey: &pb.PublicKey{
-			Single: pubKeyBytes,
-		},
+		PublicKey: txnPublicKey,
 		FeeId:     feeID,
 		FeeAmount: feeAmountParts,
 		Timestamp: timestamppb.New(time.Now().UTC()),
@@ -102,13 +131,8 @@ func CreateNftTransfer(
 		return nil, fmt.Errorf("failed to serialize NFT transfer transaction: %v", err)
 	}
 
-	keyType, err := helper.DetermineKeyType(publicKeyBase58)
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine key type: %v", err)
-	}
-
 	// Step 6: Sign the transaction
-	signature, err := helper.Sign(privateKeyBase58, byteDataNoSig, keyType)
+	signature, err := helper.SignWith(signer, byteDataNoSig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign NFT transfer transaction: %v", err)
 	}
@@ -131,20 +155,26 @@ func CreateNftTransfer(
 
 // SendNftTransferTxn submits an NFT transfer to the network via gRPC
 func SendNftTransferTxn(grpcAddr string, txn *pb.NFTTXN) (*emptypb.Empty, error) {
-	if !strings.Contains(grpcAddr, ":") {
-		grpcAddr += ":50052"
-	}
+	return SendNftTransferTxnWithContext(context.Background(), grpcAddr, txn)
+}
+
+// SendNftTransferTxnWithContext is SendNftTransferTxn with a context that is passed through to the gRPC call.
+func SendNftTransferTxnWithContext(ctx context.Context, grpcAddr string, txn *pb.NFTTXN) (*emptypb.Empty, error) {
+	return SendNftTransferTxnWithSecurity(ctx, grpcAddr, txn, nil)
+}
 
-	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// SendNftTransferTxnWithSecurity is SendNftTransferTxnWithContext with the validator dialed using security (plaintext if nil).
+func SendNftTransferTxnWithSecurity(ctx context.Context, grpcAddr string, txn *pb.NFTTXN, security *helper.TransportSecurity) (*emptypb.Empty, error) {
+	conn, err := helper.NewConnection(grpcAddr, "50052", security)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 
 	client := pb.NewTXNServiceClient(conn)
-	response, err := client.NFT(context.Background(), txn)
+	response, err := client.NFT(ctx, txn)
 	if err != nil {
-		return nil, fmt.Errorf("NFT transfer transaction failed: %v", err)
+		return nil, fmt.Errorf("NFT transfer transaction failed: %w", zeraerr.FromGRPC(err))
 	}
 
 	return response, nil
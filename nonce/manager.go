@@ -0,0 +1,239 @@
+package nonce
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+)
+
+// NonceManager caches the next nonce of each wallet so transactions can be built back to back
+// (or from many goroutines) without querying the indexer / validator for every transaction.
+//
+// Reserve hands out increasing nonces per address. A reservation must be either committed once the
+// transaction has been accepted, or released if it was never submitted / was rejected, so the nonce
+// can be handed out again. Resync reloads the nonce from the network, ie after a bad nonce rejection
+// or when the wallet is also used elsewhere.
+//
+// The returned nonce is used by passing it as NonceInfo.Override (see Reservation.Override).
+type NonceManager struct {
+	info        NonceInfo // source of nonces, Addresses / NonceReqs / Override are ignored
+	concurrency int       // lookups in flight at once, see GetNonce
+
+	mu       sync.Mutex
+	accounts map[string]*account
+}
+
+type account struct {
+	mu       sync.Mutex
+	loaded   bool
+	next     uint64          // next never reserved nonce
+	synced   uint64          // network nonce of the last sync, released nonces below it may already be used
+	released []uint64        // released nonces below next, handed out again before next (sorted)
+	pending  map[uint64]bool // reserved, not yet committed or released
+}
+
+// Reservation is a nonce reserved for a single transaction.
+type Reservation struct {
+	Address string
+	Nonce   uint64
+
+	manager *NonceManager
+	once    sync.Once
+}
+
+// NewNonceManager creates a manager that loads nonces from the indexer or validator described by info.
+// Only the source fields of info are used (UseIndexer, IndexerURL, Authorization, ValidatorAddr, Security, ApiClient,
+// RateLimits), concurrency bounds the lookups in flight at once (see GetNonce), the request rate is set by info.RateLimits.
+func NewNonceManager(info NonceInfo, concurrency int) *NonceManager {
+	info.Addresses = nil
+	info.NonceReqs = nil
+	info.Override = nil
+
+	return &NonceManager{
+		info:        info,
+		concurrency: concurrency,
+		accounts:    make(map[string]*account),
+	}
+}
+
+func (m *NonceManager) account(address string) *account {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc, ok := m.accounts[address]
+	if !ok {
+		acc = &account{pending: make(map[uint64]bool)}
+		m.accounts[address] = acc
+	}
+
+	return acc
+}
+
+// fetch loads the next nonce of address from the network
+func (m *NonceManager) fetch(ctx context.Context, address string) (uint64, error) {
+	info := m.info
+
+	if info.UseIndexer {
+		info.Addresses = []string{address}
+	} else {
+		req, err := MakeNonceRequest(address)
+		if err != nil {
+			return 0, err
+		}
+		info.NonceReqs = []*pb.NonceRequest{req}
+	}
+
+	nonces, err := GetNonceWithContext(ctx, info, m.concurrency)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load nonce for %s: %w", address, err)
+	}
+
+	return nonces[0], nil
+}
+
+// Reserve returns the next unused nonce of address, loading it from the network on first use.
+// Released nonces are handed out again (lowest first) so no gap is left behind.
+func (m *NonceManager) Reserve(ctx context.Context, address string) (*Reservation, error) {
+	// Governance wallets are not nonce tracked
+	if strings.HasPrefix(address, "gov_") {
+		return &Reservation{Address: address, Nonce: 0}, nil
+	}
+
+	acc := m.account(address)
+
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.loaded {
+		next, err := m.fetch(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+
+		acc.next = next
+		acc.synced = next
+		acc.loaded = true
+	}
+
+	var nonce uint64
+	if len(acc.released) > 0 {
+		nonce = acc.released[0]
+		acc.released = acc.released[1:]
+	} else {
+		for acc.pending[acc.next] { // still in flight from before a resync
+			acc.next++
+		}
+		nonce = acc.next
+		acc.next++
+	}
+
+	acc.pending[nonce] = true
+
+	return &Reservation{Address: address, Nonce: nonce, manager: m}, nil
+}
+
+// Resync reloads the nonce of address from the network, discarding any released nonces.
+// Nonces of reservations still pending are not handed out again, if the network has moved past
+// the cached nonce (ie the wallet was used elsewhere) the cache skips ahead.
+func (m *NonceManager) Resync(ctx context.Context, address string) error {
+	if strings.HasPrefix(address, "gov_") {
+		return nil
+	}
+
+	acc := m.account(address)
+
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	next, err := m.fetch(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	acc.synced = next
+
+	// Skip over anything still in flight
+	for acc.pending[next] {
+		next++
+	}
+
+	acc.next = next
+	acc.released = nil
+	acc.loaded = true
+
+	return nil
+}
+
+// Forget drops the cached nonce of address, it is loaded from the network again on the next Reserve.
+func (m *NonceManager) Forget(address string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.accounts, address)
+}
+
+// Override returns the reserved nonce in the form expected by NonceInfo.Override.
+func (r *Reservation) Override() []uint64 {
+	return []uint64{r.Nonce}
+}
+
+// Commit marks the nonce as used, call once the transaction has been accepted.
+func (r *Reservation) Commit() {
+	r.once.Do(func() {
+		if r.manager == nil {
+			return
+		}
+
+		acc := r.manager.account(r.Address)
+
+		acc.mu.Lock()
+		defer acc.mu.Unlock()
+
+		delete(acc.pending, r.Nonce)
+	})
+}
+
+// Release returns the nonce to the manager, call if the transaction was never submitted or was rejected.
+// If it was the latest reservation the nonce is rolled back, otherwise it is handed out by the next Reserve.
+// Nonces below the network nonce of the last Resync are dropped, the network may already have used them.
+func (r *Reservation) Release() {
+	r.once.Do(func() {
+		if r.manager == nil {
+			return
+		}
+
+		acc := r.manager.account(r.Address)
+
+		acc.mu.Lock()
+		defer acc.mu.Unlock()
+
+		if !acc.pending[r.Nonce] {
+			return // discarded by Resync / Forget
+		}
+		delete(acc.pending, r.Nonce)
+
+		if r.Nonce >= acc.next || r.Nonce < acc.synced {
+			return
+		}
+
+		if r.Nonce == acc.next-1 {
+			acc.next--
+
+			// Roll back over any released nonces now at the top
+			for len(acc.released) > 0 && acc.released[len(acc.released)-1] == acc.next-1 {
+				acc.released = acc.released[:len(acc.released)-1]
+				acc.next--
+			}
+			return
+		}
+
+		i := sort.Search(len(acc.released), func(i int) bool { return acc.released[i] >= r.Nonce })
+		acc.released = append(acc.released, 0)
+		copy(acc.released[i+1:], acc.released[i:])
+		acc.released[i] = r.Nonce
+	})
+}
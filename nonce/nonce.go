@@ -2,18 +2,18 @@ package nonce
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
 	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/ratelimit"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 )
 
 type NonceInfo struct {
@@ -24,37 +24,46 @@ type NonceInfo struct {
 	IndexerURL    string             // required when useIndexer true
 	Authorization string             // required when useIndexer true, Api-Key or Bearer
 	Override      []uint64           // optional, if set, this nonce will be used instead of the one from the indexer or validator
+
+	Security  *helper.TransportSecurity // optional, TLS / credentials for the validator and indexer requests
+	ApiClient pb.APIServiceClient       // optional, an existing validator api client to use instead of dialing ValidatorAddr
+
+	RateLimits *ratelimit.Registry // optional, per endpoint rate limits shared with parts / submission (ratelimit.Default if nil)
 }
 
+// DefaultConcurrency is the number of nonce lookups kept in flight when resolving several addresses
+const DefaultConcurrency = 5
+
 // If using for allowance CoinTXN, format should be [0] your own info, and [0+n] for any other involved addrs
-func GetNonce(info NonceInfo, maxRps int) ([]uint64, error) {
+// Requests are throttled by the shared per endpoint limiter in info.RateLimits, concurrency only bounds how many
+// lookups are in flight at once (DefaultConcurrency if < 1), it is not a rate.
+func GetNonce(info NonceInfo, concurrency int) ([]uint64, error) {
+	return GetNonceWithContext(context.Background(), info, concurrency)
+}
+
+// GetNonceWithContext is GetNonce with a context that bounds the indexer and validator requests.
+// Cancelling ctx stops any lookups that have not started yet and aborts those in flight.
+func GetNonceWithContext(ctx context.Context, info NonceInfo, concurrency int) ([]uint64, error) {
 	if len(info.Override) > 0 {
 		return info.Override, nil
 	}
 
-	// Ensure maxRps is valid
-	if maxRps <= 1 {
-		return []uint64{}, fmt.Errorf("maxRps must be greater than 1")
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
 	}
 
-	// Calculate delay between requests
-	delay := time.Second / time.Duration(maxRps-1)
-
 	var (
 		nonceRet = make([]uint64, len(info.Addresses)+len(info.NonceReqs))   // Pre-allocate slice for deterministic order
 		errChan  = make(chan error, len(info.Addresses)+len(info.NonceReqs)) // Channel to capture errors
 	)
 
 	// Create a worker pool channel to limit concurrency
-	workerPool := make(chan struct{}, maxRps)
+	workerPool := make(chan struct{}, concurrency)
 
 	// Function to process a single address or request
 	processNonce := func(index int, addr string, req *pb.NonceRequest, useIndexer bool) {
 		defer func() { <-workerPool }() // Release the worker slot when done
 
-		// Wait for rate limiter
-		time.Sleep(delay)
-
 		if useIndexer {
 			// Indexer mode
 			if strings.HasPrefix(addr, "gov_") {
@@ -62,9 +71,14 @@ func GetNonce(info NonceInfo, maxRps int) ([]uint64, error) {
 				return
 			}
 
+			if _, err := info.RateLimits.Wait(ctx, info.IndexerURL); err != nil {
+				errChan <- err
+				return
+			}
+
 			url := fmt.Sprintf("%s/store?requestType=getNextNonce&address=%s", info.IndexerURL, addr)
 
-			req, err := http.NewRequest("POST", url, nil)
+			req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
 			if err != nil {
 				errChan <- fmt.Errorf("failed to create request: %w", err)
 				return
@@ -79,7 +93,7 @@ func GetNonce(info NonceInfo, maxRps int) ([]uint64, error) {
 				req.Header.Add("Authorization", "Api-Key "+info.Authorization)
 			}
 
-			client := &http.Client{}
+			client := info.Security.HTTPClient()
 			resp, err := client.Do(req)
 			if err != nil {
 				errChan <- fmt.Errorf("failed to perform request: %w", err)
@@ -93,6 +107,11 @@ func GetNonce(info NonceInfo, maxRps int) ([]uint64, error) {
 				return
 			}
 
+			if resp.StatusCode >= 300 {
+				errChan <- fmt.Errorf("nonce request failed: %w", zeraerr.FromIndexer(resp.StatusCode, body, zeraerr.ErrUnknownWallet))
+				return
+			}
+
 			nonce, err := strconv.ParseUint(strings.TrimSpace(string(body)), 10, 64)
 			if err != nil {
 				errChan <- fmt.Errorf("failed to parse nonce: %w", err)
@@ -107,28 +126,35 @@ func GetNonce(info NonceInfo, maxRps int) ([]uint64, error) {
 				return
 			}
 
-			if info.ValidatorAddr == "" {
-				errChan <- fmt.Errorf("validatorAddr is required when useIndexer is false")
+			if _, err := info.RateLimits.Wait(ctx, validatorEndpoint(info.ValidatorAddr)); err != nil {
+				errChan <- err
 				return
 			}
 
-			if !strings.Contains(info.ValidatorAddr, ":") {
-				info.ValidatorAddr += ":50053"
-			}
+			client := info.ApiClient
 
-			conn, err := grpc.Dial(info.ValidatorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-			if err != nil {
-				errChan <- fmt.Errorf("failed to connect to validator: %w", err)
-				return
-			}
-			defer conn.Close()
+			if client == nil {
+				if info.ValidatorAddr == "" {
+					errChan <- fmt.Errorf("validatorAddr is required when useIndexer is false")
+					return
+				}
+
+				conn, err := helper.NewConnection(info.ValidatorAddr, "50053", info.Security)
+				if err != nil {
+					errChan <- fmt.Errorf("failed to connect to validator: %w", err)
+					return
+				}
+				defer conn.Close()
 
-			client := helper.NewValidatorNetworkApiClient(conn)
+				client = helper.NewValidatorNetworkApiClient(conn)
+			}
 
-			response, err := client.Nonce(context.Background(), req)
+			response, err := client.Nonce(ctx, req)
 			if err != nil {
-				// If first time
-				if strings.Contains(err.Error(), "does not exist") {
+				err = zeraerr.FromGRPC(err)
+
+				// If first time, only a wallet without a nonce is new (anything else not found is an error)
+				if errors.Is(err, zeraerr.ErrUnknownWallet) {
 					response = &pb.NonceResponse{Nonce: 0}
 				} else {
 					errChan <- fmt.Errorf("nonce request failed: %w", err)
@@ -174,6 +200,17 @@ func GetNonce(info NonceInfo, maxRps int) ([]uint64, error) {
 	return nonceRet, nil
 }
 
+// validatorEndpoint is the rate limit key of the validator APIService (shared with an ApiClient without ValidatorAddr)
+func validatorEndpoint(addr string) string {
+	if addr == "" {
+		return "validator"
+	}
+	if !strings.Contains(addr, ":") {
+		addr += ":50053"
+	}
+	return addr
+}
+
 func MakeNonceRequest(address string) (*pb.NonceRequest, error) {
 
 	if strings.HasPrefix(address, "gov_") {
@@ -1,13 +1,25 @@
 package nonce_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
 	"github.com/ZeraVision/zera-go-sdk/testvars"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 	"github.com/joho/godotenv"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const NONCE_TEST_ADDR = "48aPY5LHV6rHXAS5ciZNYPTGYV1fm1k4BQ8Wakh2B1xP"
@@ -64,3 +76,229 @@ func TestGetNonce_ValidatorMode(t *testing.T) {
 
 	t.Logf("Retrieved nonce from Validator: %d", nonceValue)
 }
+
+func TestGetNonceWithContext_Cancelled(t *testing.T) {
+	// Indexer that never answers in time
+	indexer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer indexer.Close()
+
+	nonceInfo := nonce.NonceInfo{
+		UseIndexer:    true,
+		Addresses:     []string{NONCE_TEST_ADDR},
+		IndexerURL:    indexer.URL,
+		Authorization: "test-key",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := nonce.GetNonceWithContext(ctx, nonceInfo, 5)
+	if err == nil {
+		t.Fatal("Expected an error for cancelled context, got none")
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("Expected lookup to stop at the deadline, took %s", time.Since(start))
+	}
+}
+
+// errorClient rejects every nonce request with err
+type errorClient struct {
+	pb.APIServiceClient
+	err error
+}
+
+func (c *errorClient) Nonce(ctx context.Context, in *pb.NonceRequest, opts ...grpc.CallOption) (*pb.NonceResponse, error) {
+	return nil, c.err
+}
+
+func TestGetNonce_NotFound(t *testing.T) {
+	nonceReq, err := nonce.MakeNonceRequest(NONCE_TEST_ADDR)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A wallet without a nonce yet starts at 1
+	nonceInfo := nonce.NonceInfo{
+		NonceReqs: []*pb.NonceRequest{nonceReq},
+		ApiClient: &errorClient{err: status.Error(codes.NotFound, "nonce for wallet "+NONCE_TEST_ADDR+" does not exist")},
+	}
+
+	nonceValue, err := nonce.GetNonce(nonceInfo, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if nonceValue[0] != 1 {
+		t.Fatalf("Expected nonce 1 for a new wallet, got %d", nonceValue[0])
+	}
+
+	// Anything else that is not found is an error
+	nonceInfo.ApiClient = &errorClient{err: status.Error(codes.NotFound, "contract $ABC+0000 does not exist")}
+
+	if _, err := nonce.GetNonce(nonceInfo, 1); !errors.Is(err, zeraerr.ErrUnknownContract) {
+		t.Fatalf("Expected unknown contract, got %v", err)
+	}
+
+	for _, message := range []string{"not found", "method not found", "state does not exist"} {
+		nonceInfo.ApiClient = &errorClient{err: status.Error(codes.NotFound, message)}
+
+		nonceValue, err := nonce.GetNonce(nonceInfo, 1)
+		if !errors.Is(err, zeraerr.ErrNotFound) || errors.Is(err, zeraerr.ErrUnknownWallet) {
+			t.Fatalf("Expected a not found error for %q, got %v (nonce %v)", message, err, nonceValue)
+		}
+	}
+}
+
+func TestNonceManager(t *testing.T) {
+	var (
+		networkNonce atomic.Uint64
+		lookups      atomic.Int64
+	)
+	networkNonce.Store(7)
+
+	indexer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups.Add(1)
+		fmt.Fprint(w, networkNonce.Load())
+	}))
+	defer indexer.Close()
+
+	manager := nonce.NewNonceManager(nonce.NonceInfo{
+		UseIndexer:    true,
+		IndexerURL:    indexer.URL,
+		Authorization: "test-key",
+	}, 100)
+
+	ctx := context.Background()
+
+	// Concurrent reservations are unique and contiguous
+	var (
+		mu   sync.Mutex
+		seen = map[uint64]bool{}
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reservation, err := manager.Reserve(ctx, NONCE_TEST_ADDR)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+				return
+			}
+			reservation.Commit()
+
+			mu.Lock()
+			defer mu.Unlock()
+			seen[reservation.Nonce] = true
+		}()
+	}
+	wg.Wait()
+
+	for n := uint64(7); n < 27; n++ {
+		if !seen[n] {
+			t.Fatalf("Expected nonce %d to be reserved, got %v", n, seen)
+		}
+	}
+
+	if lookups.Load() != 1 {
+		t.Errorf("Expected a single nonce lookup, got %d", lookups.Load())
+	}
+
+	// Released nonces are handed out again
+	first, _ := manager.Reserve(ctx, NONCE_TEST_ADDR)
+	second, _ := manager.Reserve(ctx, NONCE_TEST_ADDR)
+	first.Release()
+
+	again, _ := manager.Reserve(ctx, NONCE_TEST_ADDR)
+	if again.Nonce != first.Nonce {
+		t.Errorf("Expected released nonce %d to be reused, got %d", first.Nonce, again.Nonce)
+	}
+
+	// Releasing the latest reservation rolls back
+	second.Release()
+	third, _ := manager.Reserve(ctx, NONCE_TEST_ADDR)
+	if third.Nonce != second.Nonce {
+		t.Errorf("Expected nonce %d after rollback, got %d", second.Nonce, third.Nonce)
+	}
+	again.Commit()
+	third.Commit()
+
+	// Resync jumps to the network nonce (ie the wallet was used elsewhere)
+	networkNonce.Store(100)
+	if err := manager.Resync(ctx, NONCE_TEST_ADDR); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	reservation, err := manager.Reserve(ctx, NONCE_TEST_ADDR)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if reservation.Nonce != 100 {
+		t.Errorf("Expected nonce 100 after resync, got %d", reservation.Nonce)
+	}
+}
+
+func TestNonceManagerReleaseAfterResync(t *testing.T) {
+	var networkNonce atomic.Uint64
+	networkNonce.Store(100)
+
+	indexer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, networkNonce.Load())
+	}))
+	defer indexer.Close()
+
+	manager := nonce.NewNonceManager(nonce.NonceInfo{UseIndexer: true, IndexerURL: indexer.URL, Authorization: "test-key"}, 100)
+	ctx := context.Background()
+
+	var reservations []*nonce.Reservation
+	for i := 0; i < 3; i++ {
+		reservation, err := manager.Reserve(ctx, NONCE_TEST_ADDR)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	// 100 and 101 were used on chain (ie submitted before a timeout), 102 is still pending
+	networkNonce.Store(102)
+	if err := manager.Resync(ctx, NONCE_TEST_ADDR); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	reservations[0].Release()
+	reservations[1].Release()
+
+	reservation, err := manager.Reserve(ctx, NONCE_TEST_ADDR)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if reservation.Nonce != 103 {
+		t.Fatalf("Expected nonce 103, nonces below the network nonce must not be reused, got %d", reservation.Nonce)
+	}
+
+	// the pending nonce at the network nonce is still reusable
+	reservation.Release()
+	reservations[2].Release()
+
+	reservation, err = manager.Reserve(ctx, NONCE_TEST_ADDR)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if reservation.Nonce != 102 {
+		t.Errorf("Expected nonce 102 after releasing it, got %d", reservation.Nonce)
+	}
+}
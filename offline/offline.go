@@ -0,0 +1,450 @@
+// Package offline splits transaction creation into three phases so keys can stay on an air gapped machine:
+//
+//  1. online: build the transaction with an offline.PublicKeySigner (nonce and fee are resolved as usual)
+//     and Prepare an unsigned Envelope, written to a portable file with WriteFile.
+//  2. offline: ReadFile the envelope, review it and Sign it with the private keys.
+//  3. online: Assemble the signed envelope into the final transaction (signatures attached, SHA3-256 hash set)
+//     and submit it.
+//
+// VerifyTxn is the inverse for any signed transaction, ie to detect tampering before it is submitted.
+package offline
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/multisig"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const EnvelopeVersion = 1
+
+// Envelope is an unsigned transaction with its nonce and fee already resolved.
+type Envelope struct {
+	Version   int      `json:"version"`
+	Type      string   `json:"type"`      // full protobuf message name of the transaction, ie the CoinTXN message
+	Payload   []byte   `json:"payload"`   // serialized transaction without signatures / hash, exactly what is signed (base64 in JSON)
+	Nonce     []uint64 `json:"nonce"`     // informational, resolved when the envelope was prepared
+	FeeID     string   `json:"feeId"`     // informational
+	FeeAmount string   `json:"feeAmount"` // informational, in parts
+	Signers   []Slot   `json:"signers"`   // keys that can sign, in transaction order
+}
+
+type Slot struct {
+	Input     int     `json:"input"`               // index of the transaction key (CoinTXN inputs, always 0 otherwise)
+	PublicKey string  `json:"publicKey"`           // base58 public key, ie A_c_... (A_... for multi-key members)
+	Class     *uint32 `json:"class,omitempty"`     // multi-key member class, not set for single keys
+	Signature []byte  `json:"signature,omitempty"` // set by Sign (base64 in JSON)
+}
+
+// txn is implemented by every transaction type (all of them carry a BaseTXN)
+type txn interface {
+	proto.Message
+	GetBase() *pb.BaseTXN
+}
+
+// PublicKeySigner stands in for a key that is held offline. Passing it to a builder (CreateXxxWithSigner or
+// transfer.Inputs.Signer) produces a transaction without signatures that can be handed to Prepare.
+type PublicKeySigner struct {
+	publicKey string
+}
+
+var _ helper.Signer = (*PublicKeySigner)(nil)
+
+func NewPublicKeySigner(publicKeyBase58 string) *PublicKeySigner {
+	return &PublicKeySigner{publicKey: publicKeyBase58}
+}
+
+func (s *PublicKeySigner) PublicKey() string { return s.publicKey }
+
+func (s *PublicKeySigner) KeyType() helper.KeyType {
+	return helper.NewPrivateKeySigner(s.publicKey, "").KeyType()
+}
+
+// Sign returns no signature, it is added to the envelope later by the offline signer
+func (s *PublicKeySigner) Sign(payload []byte) ([]byte, error) { return nil, nil }
+
+// Prepare strips any signatures and hash from transaction and wraps the result in an Envelope.
+func Prepare(transaction proto.Message) (*Envelope, error) {
+	t, ok := transaction.(txn)
+	if !ok || t.GetBase() == nil {
+		return nil, fmt.Errorf("unsupported transaction type %T", transaction)
+	}
+
+	unsigned := proto.Clone(t).(txn)
+	strip(unsigned)
+
+	payload, err := proto.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize transaction: %v", err)
+	}
+
+	env := &Envelope{
+		Version:   EnvelopeVersion,
+		Type:      string(proto.MessageName(unsigned)),
+		Payload:   payload,
+		FeeID:     unsigned.GetBase().GetFeeId(),
+		FeeAmount: unsigned.GetBase().GetFeeAmount(),
+	}
+
+	if coin, ok := unsigned.(*pb.CoinTXN); ok {
+		env.Nonce = coin.GetAuth().GetNonce()
+	} else {
+		env.Nonce = []uint64{unsigned.GetBase().GetNonce()}
+	}
+
+	env.Signers, err = signingSlots(unsigned)
+	if err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// Transaction decodes the unsigned transaction carried by the envelope, ie to review it before signing.
+func (e *Envelope) Transaction() (proto.Message, error) {
+	if e.Version != EnvelopeVersion {
+		return nil, fmt.Errorf("unsupported envelope version %d", e.Version)
+	}
+
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(e.Type))
+	if err != nil {
+		return nil, fmt.Errorf("unknown transaction type %s: %v", e.Type, err)
+	}
+
+	message := messageType.New().Interface()
+	if err := proto.Unmarshal(e.Payload, message); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %v", err)
+	}
+
+	t, ok := message.(txn)
+	if !ok || t.GetBase() == nil {
+		return nil, fmt.Errorf("unsupported transaction type %s", e.Type)
+	}
+
+	// the signer list is only a convenience, it must match the keys in the payload
+	slots, err := signingSlots(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(slots) != len(e.Signers) {
+		return nil, fmt.Errorf("envelope lists %d signers, transaction requires %d", len(e.Signers), len(slots))
+	}
+
+	for i, slot := range slots {
+		if !sameSlot(e.Signers[i], slot) {
+			return nil, fmt.Errorf("envelope signer %d is %s, transaction requires %s", i, e.Signers[i].PublicKey, slot.PublicKey)
+		}
+	}
+
+	return message, nil
+}
+
+// Sign adds a signature from each signer to its slot. Signers that do not belong to the transaction are an error,
+// slots without a matching signer are left for another party. The envelope is only changed if every signer signs.
+func Sign(env *Envelope, signers ...helper.Signer) error {
+	if _, err := env.Transaction(); err != nil {
+		return err
+	}
+
+	// Step 1: Find the slots of every signer
+	slots := make([][]int, len(signers))
+	for s, signer := range signers {
+		key, err := rawKey(signer.PublicKey())
+		if err != nil {
+			return err
+		}
+
+		for i := range env.Signers {
+			if slotKey, _ := rawKey(env.Signers[i].PublicKey); bytes.Equal(slotKey, key) {
+				slots[s] = append(slots[s], i)
+			}
+		}
+
+		if len(slots[s]) == 0 {
+			return fmt.Errorf("%s is not a signer of this transaction", signer.PublicKey())
+		}
+	}
+
+	// Step 2: Sign
+	signatures := make([][]byte, len(signers))
+	for s, signer := range signers {
+		signature, err := helper.SignWith(signer, env.Payload)
+		if err != nil {
+			return fmt.Errorf("failed to sign with %s: %v", signer.PublicKey(), err)
+		}
+		signatures[s] = signature
+	}
+
+	// Step 3: Add the signatures to their slots
+	for s := range signers {
+		for _, i := range slots[s] {
+			env.Signers[i].Signature = signatures[s]
+		}
+	}
+
+	return nil
+}
+
+// Merge copies the signatures of other, a copy of the same envelope signed independently, into e.
+func (e *Envelope) Merge(other *Envelope) error {
+	if e.Type != other.Type || !bytes.Equal(e.Payload, other.Payload) || len(e.Signers) != len(other.Signers) {
+		return errors.New("envelopes are not for the same transaction")
+	}
+
+	for i, slot := range other.Signers {
+		if !sameSlot(e.Signers[i], slot) {
+			return errors.New("envelopes are not for the same transaction")
+		}
+
+		if len(slot.Signature) == 0 {
+			continue
+		}
+
+		if len(e.Signers[i].Signature) > 0 && !bytes.Equal(e.Signers[i].Signature, slot.Signature) {
+			return fmt.Errorf("conflicting signatures from %s", slot.PublicKey)
+		}
+
+		e.Signers[i].Signature = slot.Signature
+	}
+
+	return nil
+}
+
+// Missing returns the public keys that can still sign for transaction keys that are not yet authorized
+// (a single key that has not signed, or a multi-key whose patterns are not met).
+func (e *Envelope) Missing() ([]string, error) {
+	message, err := e.Transaction()
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for input, publicKey := range txnKeys(message.(txn)) {
+		if authorize(e.Signers, input, publicKey) == nil {
+			continue
+		}
+
+		for _, slot := range e.Signers {
+			if slot.Input == input && len(slot.Signature) == 0 {
+				missing = append(missing, slot.PublicKey)
+			}
+		}
+	}
+	return missing, nil
+}
+
+// Assemble verifies every signature, attaches them and sets the SHA3-256 hash. Multi-keys must meet one of their
+// MultiPatterns. The result can be submitted like any builder output (ie zera.Client.Submit, or cast to the
+// concrete type for the SendXxx functions).
+func Assemble(env *Envelope) (proto.Message, error) {
+	message, err := env.Transaction()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, slot := range env.Signers {
+		if len(slot.Signature) == 0 {
+			continue
+		}
+
+		if ok, err := helper.Verify(slot.PublicKey, env.Payload, slot.Signature); !ok {
+			return nil, fmt.Errorf("invalid signature from %s: %v", slot.PublicKey, err)
+		}
+	}
+
+	t := message.(txn)
+	keys := txnKeys(t)
+
+	// Step 1: Check every key is authorized
+	for input, publicKey := range keys {
+		if err := authorize(env.Signers, input, publicKey); err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 2: Attach signatures
+	attach(t, env.Signers)
+
+	// Step 3: Serialize with signatures
+	byteDataWithSig, err := proto.Marshal(t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize signed transaction: %v", err)
+	}
+
+	// Step 4: Hash the signed transaction
+	t.GetBase().Hash = transcode.SHA3256(byteDataWithSig)
+
+	return t, nil
+}
+
+// WriteFile writes the envelope as JSON.
+func WriteFile(path string, env *Envelope) error {
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// ReadFile reads an envelope written by WriteFile.
+func ReadFile(path string) (*Envelope, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("failed to parse envelope: %v", err)
+	}
+
+	if env.Version != EnvelopeVersion {
+		return nil, fmt.Errorf("unsupported envelope version %d", env.Version)
+	}
+
+	return &env, nil
+}
+
+func strip(t txn) {
+	t.GetBase().Signature = nil
+	t.GetBase().Hash = nil
+
+	if coin, ok := t.(*pb.CoinTXN); ok && coin.Auth != nil {
+		coin.Auth.Signature = nil
+	}
+
+	for _, publicKey := range txnKeys(t) {
+		if multi := publicKey.GetMulti(); multi != nil {
+			multi.Signatures = nil
+		}
+	}
+}
+
+// attach sets the signatures of the slots on the unsigned transaction, the inverse of strip
+func attach(t txn, slots []Slot) {
+	for input, publicKey := range txnKeys(t) {
+		var signature []byte
+
+		if multi := publicKey.GetMulti(); multi != nil {
+			multi.Signatures = make([][]byte, len(multi.PublicKeys))
+			member := 0
+			for _, slot := range slots {
+				if slot.Input == input {
+					multi.Signatures[member] = slot.Signature
+					member++
+				}
+			}
+		} else {
+			for _, slot := range slots {
+				if slot.Input == input {
+					signature = slot.Signature
+				}
+			}
+		}
+
+		if coin, ok := t.(*pb.CoinTXN); ok {
+			coin.Auth.Signature = append(coin.Auth.Signature, signature)
+		} else {
+			t.GetBase().Signature = signature
+		}
+	}
+}
+
+// txnKeys returns the keys that authorize the transaction, the CoinTXN inputs or the base key
+func txnKeys(t txn) []*pb.PublicKey {
+	if coin, ok := t.(*pb.CoinTXN); ok {
+		return coin.GetAuth().GetPublicKey()
+	}
+	return []*pb.PublicKey{t.GetBase().GetPublicKey()}
+}
+
+// signingSlots returns a slot for every key that can sign the transaction (special keys do not sign)
+func signingSlots(t txn) ([]Slot, error) {
+	var slots []Slot
+	for input, publicKey := range txnKeys(t) {
+		if multi := publicKey.GetMulti(); multi != nil {
+			if err := multisig.Validate(multi); err != nil {
+				return nil, fmt.Errorf("key %d: %v", input, err)
+			}
+
+			members, err := multisig.Members(multi)
+			if err != nil {
+				return nil, fmt.Errorf("key %d: %v", input, err)
+			}
+
+			for _, member := range members {
+				class := member.Class
+				slots = append(slots, Slot{Input: input, PublicKey: member.PublicKey, Class: &class})
+			}
+			continue
+		}
+
+		if key, ok := encodeSingle(publicKey.GetSingle()); ok {
+			slots = append(slots, Slot{Input: input, PublicKey: key})
+		}
+	}
+	return slots, nil
+}
+
+// authorize checks the slots of one transaction key carry enough signatures
+func authorize(slots []Slot, input int, publicKey *pb.PublicKey) error {
+	var signed []bool
+	var keys []string
+	for _, slot := range slots {
+		if slot.Input == input {
+			signed = append(signed, len(slot.Signature) > 0)
+			keys = append(keys, slot.PublicKey)
+		}
+	}
+
+	if multi := publicKey.GetMulti(); multi != nil {
+		if err := multisig.Satisfied(multi, signed); err != nil {
+			return fmt.Errorf("key %d: %v", input, err)
+		}
+		return nil
+	}
+
+	for i := range signed {
+		if !signed[i] {
+			return fmt.Errorf("missing signature from %s", keys[i])
+		}
+	}
+	return nil
+}
+
+func sameSlot(a, b Slot) bool {
+	if a.Input != b.Input || a.PublicKey != b.PublicKey || (a.Class == nil) != (b.Class == nil) {
+		return false
+	}
+	return a.Class == nil || *a.Class == *b.Class
+}
+
+// encodeSingle turns the prefixed key bytes used in transactions (ie "A_c_" + 32 bytes) back into base58
+func encodeSingle(key []byte) (string, bool) {
+	parsed, err := helper.ParsePublicKeyBytes(key)
+	if err != nil || parsed.Special() {
+		return "", false // gov_, sc_, inheritence...
+	}
+
+	return parsed.String(), true
+}
+
+// rawKey returns the key material of a base58 public key, without type / hash prefixes
+func rawKey(publicKeyBase58 string) ([]byte, error) {
+	parsed, err := helper.ParsePublicKey(publicKeyBase58)
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Key, nil
+}
@@ -0,0 +1,169 @@
+package offline_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/governance"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/nonce"
+	"github.com/ZeraVision/zera-go-sdk/offline"
+	"github.com/ZeraVision/zera-go-sdk/parts"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/ZeraVision/zera-go-sdk/transfer"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	TEST_ADDRESS = "8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR"
+	TEST_PUBLIC  = "A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"
+	TEST_PRIVATE = "2ap5CkCekErkqJ4UuSGAW1BmRRRNr8hXaebudv1j8TY6mJMSsbnniakorFGmetE4aegsyQAD8WX1N8Q2Y45YEBDs"
+)
+
+func TestOfflineCoinTxn(t *testing.T) {
+	// Phase 1 (online): build with the public key only
+	txn, err := transfer.CreateCoinTxn(
+		nonce.NonceInfo{Override: []uint64{7}},
+		parts.PartsInfo{Symbol: "$ZRA+0000", Override: big.NewInt(1000000000)},
+		[]transfer.Inputs{{B58Address: TEST_ADDRESS, Signer: offline.NewPublicKeySigner(TEST_PUBLIC), Amount: "1.5", FeePercent: 100}},
+		map[string]string{TEST_ADDRESS: "1.5"},
+		"$ZRA+0000", "1000000000", nil, nil, 5,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	env, err := offline.Prepare(txn)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if env.Type != string(proto.MessageName(&pb.CoinTXN{})) || len(env.Nonce) != 1 || env.Nonce[0] != 7 {
+		t.Fatalf("Expected a CoinTXN with nonce 7, got %s %v", env.Type, env.Nonce)
+	}
+
+	if len(env.Signers) != 1 || env.Signers[0].PublicKey != TEST_PUBLIC {
+		t.Fatalf("Expected %s to be the only signer, got %+v", TEST_PUBLIC, env.Signers)
+	}
+
+	path := filepath.Join(t.TempDir(), "txn.json")
+	if err := offline.WriteFile(path, env); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := offline.Assemble(env); err == nil {
+		t.Fatal("Expected an error for missing signatures, got none")
+	}
+
+	// Phase 2 (offline): sign
+	env, err = offline.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := offline.Sign(env, helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := offline.WriteFile(path, env); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Phase 3 (online): assemble
+	env, err = offline.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	message, err := offline.Assemble(env)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	signed := message.(*pb.CoinTXN)
+	if len(signed.Auth.Signature) != 1 {
+		t.Fatalf("Expected 1 signature, got %d", len(signed.Auth.Signature))
+	}
+
+	checkHash(t, signed, signed.Base)
+
+	// a signature over a different payload is refused
+	env.Signers[0].Signature[0] ^= 0xff
+	if _, err := offline.Assemble(env); err == nil {
+		t.Error("Expected an error for an invalid signature, got none")
+	}
+}
+
+func TestOfflineVote(t *testing.T) {
+	support := true
+
+	txn, err := governance.CreateVoteTxnWithSigner(context.Background(), nonce.NonceInfo{Override: []uint64{3}}, "$ZRA+0000",
+		"aa", offline.NewPublicKeySigner(TEST_PUBLIC), "$ZRA+0000", "1000000000", &support, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	env, err := offline.Prepare(txn)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// a signer that does not belong leaves the envelope untouched
+	if err := offline.Sign(env, helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE), helper.NewPrivateKeySigner("A_c_5S2ZGbjukeWE3e7uqUpS4T4VCvJnMgWhzkeV4dn4G7yW", TEST_PRIVATE)); err == nil {
+		t.Error("Expected an error for a key that is not a signer, got none")
+	}
+
+	missing, err := env.Missing()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(missing) != 1 || missing[0] != TEST_PUBLIC || len(env.Signers[0].Signature) != 0 {
+		t.Fatalf("Expected %s to still be missing, got %v", TEST_PUBLIC, missing)
+	}
+
+	if err := offline.Sign(env, helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if missing, err := env.Missing(); err != nil || len(missing) != 0 {
+		t.Fatalf("Expected no missing signers, got %v (%v)", missing, err)
+	}
+
+	message, err := offline.Assemble(env)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	vote := message.(*pb.GovernanceVote)
+	if ok, err := helper.Verify(TEST_PUBLIC, env.Payload, vote.Base.Signature); !ok {
+		t.Fatalf("Expected a valid signature, got %v", err)
+	}
+
+	checkHash(t, vote, vote.Base)
+
+	env.Version = 0
+	if _, err := env.Missing(); err == nil {
+		t.Error("Expected an error for an unsupported envelope, got none")
+	}
+}
+
+func checkHash(t *testing.T, txn proto.Message, base *pb.BaseTXN) {
+	t.Helper()
+
+	hash := base.Hash
+	base.Hash = nil
+	defer func() { base.Hash = hash }()
+
+	data, err := proto.Marshal(txn)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if transcode.HexEncode(transcode.SHA3256(data)) != transcode.HexEncode(hash) {
+		t.Errorf("Expected the hash to be the SHA3-256 of the signed transaction")
+	}
+}
@@ -0,0 +1,186 @@
+package offline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"google.golang.org/protobuf/proto"
+)
+
+// Verification is the result of checking a signed transaction, see Check.
+type Verification struct {
+	Hash          []byte           // SHA3-256 of the signed transaction, what Base.Hash must be
+	HashValid     bool             // Base.Hash matches Hash
+	Signatures    []SignatureCheck // one per signing key, in transaction order (gov_ / sc_ keys do not sign)
+	Authorization error            // why the keys are not authorized, nil if every key is
+}
+
+// SignatureCheck is the check of the signature carried for one signing key.
+type SignatureCheck struct {
+	Slot       // the signing key with the signature found in the transaction (empty if it did not sign)
+	Err  error // why the signature is not valid, nil if it is
+}
+
+// Signed reports whether every signature present is valid and every key is authorized. Multi-key members
+// that did not sign are fine as long as one of the MultiPatterns is met.
+func (v *Verification) Signed() bool {
+	for _, check := range v.Signatures {
+		if check.Err != nil && len(check.Signature) > 0 {
+			return false
+		}
+	}
+	return v.Authorization == nil
+}
+
+// Err returns the first problem found, nil if the transaction is signed and hashed correctly.
+func (v *Verification) Err() error {
+	for _, check := range v.Signatures {
+		if check.Err != nil && len(check.Signature) > 0 {
+			return check.Err
+		}
+	}
+
+	if v.Authorization != nil {
+		return v.Authorization
+	}
+
+	if !v.HashValid {
+		return fmt.Errorf("hash does not match the transaction, expected %x", v.Hash)
+	}
+
+	return nil
+}
+
+// VerifyTxn checks a signed transaction of any type the way a validator would: every signature against the
+// transaction without signatures and hash (CoinTXN inputs and multi-key members included), every key is authorized,
+// and Base.Hash is the SHA3-256 of the signed transaction.
+func VerifyTxn(transaction proto.Message) error {
+	v, err := Check(transaction)
+	if err != nil {
+		return err
+	}
+	return v.Err()
+}
+
+// Check is VerifyTxn with the result of every check. The error is only set if the transaction can not be checked
+// at all (unsupported type or malformed keys).
+func Check(transaction proto.Message) (*Verification, error) {
+	t, ok := transaction.(txn)
+	if !ok || t.GetBase() == nil {
+		return nil, fmt.Errorf("unsupported transaction type %T", transaction)
+	}
+
+	v := &Verification{}
+
+	// Step 1: Hash the signed transaction (without its hash)
+	unhashed := proto.Clone(t).(txn)
+	unhashed.GetBase().Hash = nil
+
+	byteDataWithSig, err := proto.Marshal(unhashed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize transaction: %v", err)
+	}
+
+	v.Hash = transcode.SHA3256(byteDataWithSig)
+	v.HashValid = bytes.Equal(v.Hash, t.GetBase().GetHash())
+
+	// Step 2: Strip signatures and hash, the payload every key signed
+	env, err := Prepare(t)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: Check the signature carried for every signing key
+	signed := signaturesOf(t, env.Signers)
+
+	authorized := make([]Slot, len(signed))
+	for i, slot := range signed {
+		check := SignatureCheck{Slot: slot}
+		authorized[i] = Slot{Input: slot.Input, PublicKey: slot.PublicKey, Class: slot.Class}
+
+		if len(slot.Signature) == 0 {
+			check.Err = fmt.Errorf("missing signature from %s", slot.PublicKey)
+		} else if ok, err := helper.Verify(slot.PublicKey, env.Payload, slot.Signature); !ok {
+			check.Err = fmt.Errorf("invalid signature from %s: %v", slot.PublicKey, err)
+		} else {
+			authorized[i].Signature = slot.Signature
+		}
+
+		v.Signatures = append(v.Signatures, check)
+	}
+
+	// Step 4: Check every key is authorized by its valid signatures
+	for input, publicKey := range txnKeys(t) {
+		if err := authorize(authorized, input, publicKey); err != nil {
+			v.Authorization = err
+			break
+		}
+	}
+
+	// Step 5: Nothing else is signed, the signatures put back where the builders put them give the transaction
+	if v.Authorization == nil {
+		rebuilt, err := env.Transaction()
+		if err != nil {
+			return nil, err
+		}
+
+		attach(rebuilt.(txn), signed)
+		rebuilt.(txn).GetBase().Hash = t.GetBase().GetHash()
+
+		carried := proto.Clone(t).(txn)
+		padSignatures(carried)
+
+		if !proto.Equal(rebuilt, carried) {
+			v.Authorization = errors.New("transaction carries signatures that do not belong to its keys")
+		}
+	}
+
+	return v, nil
+}
+
+// padSignatures gives every multi-key of t a signature per member, empty for the members that did not sign, as
+// attach does. Multi-keys may leave the unsigned members after the last signature off.
+func padSignatures(t txn) {
+	for _, publicKey := range txnKeys(t) {
+		if multi := publicKey.GetMulti(); multi != nil && len(multi.Signatures) < len(multi.PublicKeys) {
+			multi.Signatures = append(multi.Signatures, make([][]byte, len(multi.PublicKeys)-len(multi.Signatures))...)
+		}
+	}
+}
+
+// signaturesOf returns the slots with the signatures t carries for them, the inverse of attach
+func signaturesOf(t txn, slots []Slot) []Slot {
+	keys := txnKeys(t)
+	coin, isCoin := t.(*pb.CoinTXN)
+
+	signed := make([]Slot, len(slots))
+	members := make(map[int]int)
+
+	for i, slot := range slots {
+		signed[i] = slot
+
+		if multi := keys[slot.Input].GetMulti(); multi != nil {
+			member := members[slot.Input]
+			members[slot.Input]++
+
+			if member < len(multi.Signatures) {
+				signed[i].Signature = multi.Signatures[member]
+			}
+			continue
+		}
+
+		if isCoin {
+			if signatures := coin.GetAuth().GetSignature(); slot.Input < len(signatures) {
+				signed[i].Signature = signatures[slot.Input]
+			}
+		} else {
+			signed[i].Signature = t.GetBase().GetSignature()
+		}
+	}
+
+	return signed
+}
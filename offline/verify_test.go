@@ -0,0 +1,234 @@
+package offline_test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/governance"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/multisig"
+	"github.com/ZeraVision/zera-go-sdk/nonce"
+	"github.com/ZeraVision/zera-go-sdk/offline"
+	"github.com/ZeraVision/zera-go-sdk/parts"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/ZeraVision/zera-go-sdk/transfer"
+	"github.com/ZeraVision/zera-go-sdk/wallet"
+	"google.golang.org/protobuf/proto"
+)
+
+// the test key with the sha3-256 hash token, a second wallet with its own signature
+const (
+	SECOND_ADDRESS = "QK2KwEe1qKng1mzfiyDaQMKqYzFvman5CPdEVyRy1PV"
+	SECOND_PUBLIC  = "A_a_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"
+)
+
+func twoInputCoinTxn(t *testing.T) *pb.CoinTXN {
+	txn, err := transfer.CreateCoinTxnWithContext(context.Background(),
+		nonce.NonceInfo{Override: []uint64{7, 2}},
+		parts.PartsInfo{Symbol: "$ZRA+0000", Override: big.NewInt(1000000000)},
+		[]transfer.Inputs{
+			{B58Address: TEST_ADDRESS, Signer: helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE), Amount: "1", FeePercent: 50},
+			{B58Address: SECOND_ADDRESS, Signer: helper.NewPrivateKeySigner(SECOND_PUBLIC, TEST_PRIVATE), Amount: "0.5", FeePercent: 50},
+		},
+		map[string]string{TEST_ADDRESS: "1.5"},
+		"$ZRA+0000", "1000000000", nil, nil, 1,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return txn
+}
+
+func TestVerifyTxn(t *testing.T) {
+	txn := twoInputCoinTxn(t)
+
+	if err := offline.VerifyTxn(txn); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	v, err := offline.Check(txn)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !v.HashValid || !v.Signed() || len(v.Signatures) != 2 {
+		t.Fatalf("Expected a valid hash and two valid signatures, got %+v", v)
+	}
+
+	for i, publicKey := range []string{TEST_PUBLIC, SECOND_PUBLIC} {
+		if v.Signatures[i].Input != i || v.Signatures[i].PublicKey != publicKey || v.Signatures[i].Err != nil {
+			t.Errorf("Expected a valid signature from %s for input %d, got %+v", publicKey, i, v.Signatures[i])
+		}
+	}
+
+	vote, err := governance.CreateVoteTxnWithSigner(context.Background(), nonce.NonceInfo{Override: []uint64{3}}, "$ZRA+0000",
+		"aa", helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE), "$ZRA+0000", "1000000000", nil, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := offline.VerifyTxn(vote); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := offline.VerifyTxn(&pb.BaseTXN{}); err == nil {
+		t.Error("Expected an error for a message that is not a transaction, got none")
+	}
+}
+
+func TestVerifyTxnTampered(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(txn *pb.CoinTXN)
+		invalid []int // inputs whose signature is not valid
+		err     string
+	}{
+		{
+			name:    "amount",
+			tamper:  func(txn *pb.CoinTXN) { txn.OutputTransfers[0].Amount = "2500000000" },
+			invalid: []int{0, 1},
+			err:     "invalid signature",
+		},
+		{
+			name:    "signature",
+			tamper:  func(txn *pb.CoinTXN) { txn.Auth.Signature[1] = make([]byte, len(txn.Auth.Signature[1])) },
+			invalid: []int{1},
+			err:     "invalid signature from " + SECOND_PUBLIC,
+		},
+		{
+			name:    "missing signature",
+			tamper:  func(txn *pb.CoinTXN) { txn.Auth.Signature = txn.Auth.Signature[:1] },
+			invalid: []int{1},
+			err:     "missing signature from " + SECOND_PUBLIC,
+		},
+		{
+			name:   "extra signature",
+			tamper: func(txn *pb.CoinTXN) { txn.Auth.Signature = append(txn.Auth.Signature, txn.Auth.Signature[0]) },
+			err:    "do not belong",
+		},
+		{
+			name:   "hash",
+			tamper: func(txn *pb.CoinTXN) { txn.Base.Hash = transcode.SHA3256([]byte("other")) },
+			err:    "hash does not match",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			txn := twoInputCoinTxn(t)
+			test.tamper(txn)
+
+			// the hash is recomputed after tampering, to check the signatures alone
+			if test.name != "hash" {
+				txn.Base.Hash = nil
+				data, err := proto.Marshal(txn)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				txn.Base.Hash = transcode.SHA3256(data)
+			}
+
+			err := offline.VerifyTxn(txn)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("Expected an error containing %q, got %v", test.err, err)
+			}
+
+			v, err := offline.Check(txn)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if v.HashValid != (test.name != "hash") {
+				t.Errorf("Expected hash valid %v, got %v", test.name != "hash", v.HashValid)
+			}
+
+			for _, input := range test.invalid {
+				if v.Signatures[input].Err == nil {
+					t.Errorf("Expected the signature of input %d to be invalid", input)
+				}
+			}
+
+			if v.Signed() == (test.name != "hash") {
+				t.Errorf("Expected signed %v, got %v", test.name == "hash", v.Signed())
+			}
+		})
+	}
+}
+
+func TestVerifyTxnPartialMultiKey(t *testing.T) {
+	var keys []helper.MultiKey
+	var signers []helper.Signer
+	for _, mnemonic := range []string{"verify one", "verify two", "verify three"} {
+		private, public, _, err := wallet.GenerateEd25519(mnemonic, helper.BLAKE3, helper.ED25519)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		keys = append(keys, helper.MultiKey{Class: 1, PublicKey: public})
+		signers = append(signers, helper.NewPrivateKeySigner(public, private))
+	}
+
+	// 2 of 3, the last member does not sign
+	account, err := multisig.NewAccount(helper.MultiKeyHelper{
+		MultiKey:   keys,
+		Pattern:    [][]helper.MultiPatterns{{{Class: 1, Required: 2}}},
+		HashTokens: []helper.HashType{helper.BLAKE3},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	vote, err := governance.CreateVoteTxnWithSigner(context.Background(), nonce.NonceInfo{Override: []uint64{1}}, "$ZRA+0000",
+		"aa", account, "$ZRA+0000", "1000000000", nil, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	env, err := offline.Prepare(vote)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, signer := range signers[:2] {
+		if err := offline.Sign(env, signer); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	message, err := offline.Assemble(env)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := offline.VerifyTxn(message); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// the empty slot of the member that did not sign left off, and the hash taken again
+	trimmed := proto.Clone(message).(*pb.GovernanceVote)
+	trimmed.Base.PublicKey.Multi.Signatures = trimmed.Base.PublicKey.Multi.Signatures[:2]
+	rehash := func(txn *pb.GovernanceVote) {
+		txn.Base.Hash = nil
+		data, err := proto.Marshal(txn)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		txn.Base.Hash = transcode.SHA3256(data)
+	}
+	rehash(trimmed)
+
+	if err := offline.VerifyTxn(trimmed); err != nil {
+		t.Fatalf("Expected a partially signed multi-key to verify, got %v", err)
+	}
+
+	// a signature slot beyond the members still does not belong
+	extra := proto.Clone(message).(*pb.GovernanceVote)
+	extra.Base.PublicKey.Multi.Signatures = append(extra.Base.PublicKey.Multi.Signatures, extra.Base.PublicKey.Multi.Signatures[0])
+	rehash(extra)
+
+	if err := offline.VerifyTxn(extra); err == nil || !strings.Contains(err.Error(), "do not belong") {
+		t.Fatalf("Expected an error for an extra signature, got %v", err)
+	}
+}
@@ -2,12 +2,18 @@ package parts
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
 	"net/http"
 	"strings"
+
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/ratelimit"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 )
 
 type Response struct {
@@ -27,9 +33,17 @@ type PartsInfo struct {
 	ValidatorAddr string   // required when UseIndexer false
 	Authorization string   // required when useIndexer true, Api-Key or Bearer
 	Override      *big.Int // to just specify it
+
+	Security   *helper.TransportSecurity // optional, TLS / http transport for the indexer request
+	RateLimits *ratelimit.Registry       // optional, per endpoint rate limits shared with nonce / submission (ratelimit.Default if nil)
 }
 
 func GetParts(partsInfo PartsInfo) (*big.Int, error) {
+	return GetPartsWithContext(context.Background(), partsInfo)
+}
+
+// GetPartsWithContext is GetParts with a context that bounds the indexer request.
+func GetPartsWithContext(ctx context.Context, partsInfo PartsInfo) (*big.Int, error) {
 
 	if partsInfo.Override != nil {
 		return partsInfo.Override, nil
@@ -45,8 +59,12 @@ func GetParts(partsInfo PartsInfo) (*big.Int, error) {
 			return nil, fmt.Errorf("authorization (api key or bearer token) is required when useIndexer is true")
 		}
 
+		if _, err := partsInfo.RateLimits.Wait(ctx, partsInfo.IndexerUrl); err != nil {
+			return nil, err
+		}
+
 		// Create the request
-		req, err := http.NewRequest("POST", fmt.Sprintf("%s/store?requestType=getContractGlance&symbol=%s", partsInfo.IndexerUrl, partsInfo.Symbol), bytes.NewBuffer([]byte{}))
+		req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/store?requestType=getContractGlance&symbol=%s", partsInfo.IndexerUrl, partsInfo.Symbol), bytes.NewBuffer([]byte{}))
 		if err != nil {
 			return nil, fmt.Errorf("failed to create request: %v", err)
 		}
@@ -60,7 +78,7 @@ func GetParts(partsInfo PartsInfo) (*big.Int, error) {
 		}
 
 		// Send request
-		client := &http.Client{}
+		client := partsInfo.Security.HTTPClient()
 		resp, err := client.Do(req)
 		if err != nil {
 			return nil, fmt.Errorf("API request failed: %v", err)
@@ -73,12 +91,16 @@ func GetParts(partsInfo PartsInfo) (*big.Int, error) {
 			return nil, fmt.Errorf("failed to read response: %v", err)
 		}
 
+		if resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("contract lookup for %s failed: %w", partsInfo.Symbol, zeraerr.FromIndexer(resp.StatusCode, body, zeraerr.ErrUnknownContract))
+		}
+
 		// Parse JSON
 		var result Response
 		err = json.Unmarshal(body, &result)
 		if err != nil {
-			if strings.Contains(string(body), "does not exist") {
-				return nil, fmt.Errorf("contract with symbol %s does not exist", partsInfo.Symbol)
+			if indexerErr := zeraerr.FromIndexer(resp.StatusCode, body, zeraerr.ErrUnknownContract); errors.Is(indexerErr, zeraerr.ErrUnknownContract) {
+				return nil, fmt.Errorf("contract with symbol %s does not exist: %w", partsInfo.Symbol, indexerErr)
 			}
 			return nil, fmt.Errorf("failed to parse JSON response: %v", err)
 		}
@@ -0,0 +1,180 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRate  = 5 // requests per second per endpoint
+	DefaultBurst = 5 // requests allowed back to back before throttling kicks in
+)
+
+type Config struct {
+	Rate   float64                                     // optional, requests per second per endpoint (default 5, -1 to disable limiting)
+	Burst  int                                         // optional, requests allowed at once before throttling (default 5)
+	OnWait func(endpoint string, waited time.Duration) // optional, called whenever a request had to wait for the limiter
+}
+
+// Default is the process wide registry, used by nonce and parts when no registry is configured (a zera.Client is
+// only limited when its Config.RateLimit is set).
+var Default = NewRegistry(Config{})
+
+// Registry holds one token bucket per endpoint (indexer url, validator address...), so every
+// request to the same endpoint shares a single limit no matter which part of the SDK sends it.
+type Registry struct {
+	mu       sync.Mutex
+	config   Config
+	limiters map[string]*Limiter
+}
+
+// NewRegistry creates a registry applying config to every endpoint.
+func NewRegistry(config Config) *Registry {
+	return &Registry{
+		config:   withDefaults(config),
+		limiters: make(map[string]*Limiter),
+	}
+}
+
+func withDefaults(config Config) Config {
+	if config.Rate == 0 {
+		config.Rate = DefaultRate
+	}
+
+	if config.Burst <= 0 {
+		config.Burst = DefaultBurst
+	}
+
+	return config
+}
+
+// Configure replaces the limits of every endpoint, ie to set ratelimit.Default once at startup.
+func (r *Registry) Configure(config Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.config = withDefaults(config)
+	r.limiters = make(map[string]*Limiter)
+}
+
+// For returns the limiter of an endpoint, a nil registry uses Default.
+func (r *Registry) For(endpoint string) *Limiter {
+	if r == nil {
+		r = Default
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	limiter, ok := r.limiters[endpoint]
+	if !ok {
+		limiter = newLimiter(endpoint, r.config)
+		r.limiters[endpoint] = limiter
+	}
+
+	return limiter
+}
+
+// Wait blocks until a request to endpoint is allowed, returning how long it waited.
+func (r *Registry) Wait(ctx context.Context, endpoint string) (time.Duration, error) {
+	return r.For(endpoint).Wait(ctx)
+}
+
+// Limiter is a token bucket refilled at Rate tokens per second, holding up to Burst tokens.
+type Limiter struct {
+	endpoint string
+	rate     float64
+	burst    float64
+	onWait   func(endpoint string, waited time.Duration)
+
+	mu       sync.Mutex
+	tokens   float64
+	last     time.Time
+	requests int64
+	waited   time.Duration
+}
+
+// NewLimiter creates a standalone limiter, rate <= 0 disables limiting.
+func NewLimiter(rate float64, burst int) *Limiter {
+	if rate <= 0 {
+		rate = -1
+	}
+
+	return newLimiter("", withDefaults(Config{Rate: rate, Burst: burst}))
+}
+
+func newLimiter(endpoint string, config Config) *Limiter {
+	return &Limiter{
+		endpoint: endpoint,
+		rate:     config.Rate,
+		burst:    float64(config.Burst),
+		onWait:   config.OnWait,
+		tokens:   float64(config.Burst),
+		last:     time.Now(),
+	}
+}
+
+// Wait takes a token, blocking until one is available or ctx is done. It returns the time spent waiting.
+func (l *Limiter) Wait(ctx context.Context) (time.Duration, error) {
+	if l == nil || l.rate < 0 {
+		return 0, ctx.Err()
+	}
+
+	l.mu.Lock()
+
+	// Step 1: refill
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	// Step 2: take a token, going into debt reserves a slot in the queue of waiting requests
+	l.tokens--
+	l.requests++
+
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+
+	l.mu.Unlock()
+
+	if wait == 0 {
+		return 0, ctx.Err()
+	}
+
+	// Step 3: wait for the reserved slot
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		// hand the slot back to the requests queued behind this one
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return 0, ctx.Err()
+	}
+
+	l.mu.Lock()
+	l.waited += wait
+	l.mu.Unlock()
+
+	if l.onWait != nil {
+		l.onWait(l.endpoint, wait)
+	}
+
+	return wait, nil
+}
+
+// Stats returns the number of requests that passed through the limiter and the total time they spent waiting.
+func (l *Limiter) Stats() (requests int64, waited time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.requests, l.waited
+}
@@ -0,0 +1,95 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ZeraVision/zera-go-sdk/ratelimit"
+)
+
+func TestLimiterBurstThenRate(t *testing.T) {
+	limiter := ratelimit.NewLimiter(20, 2)
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if _, err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	// 2 immediately, 4 more at 20/s
+	if elapsed < 150*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Expected roughly 200ms for 6 requests, took %s", elapsed)
+	}
+
+	requests, waited := limiter.Stats()
+	if requests != 6 {
+		t.Errorf("Expected 6 requests, got %d", requests)
+	}
+	if waited <= 0 {
+		t.Errorf("Expected wait time to be reported, got %s", waited)
+	}
+}
+
+func TestLimiterCancelled(t *testing.T) {
+	limiter := ratelimit.NewLimiter(1, 1)
+	limiter.Wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRegistrySharedPerEndpoint(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		waits  = map[string]int{}
+		config = ratelimit.Config{
+			Rate:  50,
+			Burst: 1,
+			OnWait: func(endpoint string, waited time.Duration) {
+				mu.Lock()
+				defer mu.Unlock()
+				waits[endpoint]++
+			},
+		}
+	)
+
+	registry := ratelimit.NewRegistry(config)
+
+	if registry.For("indexer") != registry.For("indexer") {
+		t.Fatal("Expected the same limiter for the same endpoint")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			registry.Wait(context.Background(), "indexer")
+		}()
+		go func() {
+			defer wg.Done()
+			registry.Wait(context.Background(), "validator")
+		}()
+	}
+	wg.Wait()
+
+	if waits["indexer"] != 3 || waits["validator"] != 3 {
+		t.Errorf("Expected 3 throttled requests per endpoint, got %v", waits)
+	}
+
+	unlimited := ratelimit.NewRegistry(ratelimit.Config{Rate: -1})
+	for i := 0; i < 100; i++ {
+		if waited, _ := unlimited.Wait(context.Background(), "validator"); waited != 0 {
+			t.Fatalf("Expected no wait with limiting disabled, got %s", waited)
+		}
+	}
+}
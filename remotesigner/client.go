@@ -0,0 +1,151 @@
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ZeraVision/zera-go-sdk/helper"
+)
+
+const DefaultTimeout = 10 * time.Second
+
+type ClientConfig struct {
+	URL      string                    // daemon url, ie https://signer.internal:7400
+	Token    string                    // optional, bearer token
+	Security *helper.TransportSecurity // optional, TLS / mTLS for the daemon connection
+	Timeout  time.Duration             // optional, per signature (default 10s)
+}
+
+// Signer is a helper.Signer backed by a key held by a remote signing daemon.
+type Signer struct {
+	config ClientConfig
+	client *http.Client
+	key    KeyInfo
+}
+
+var _ helper.Signer = (*Signer)(nil)
+
+// NewSigner connects to the daemon and resolves key (name or public key), so PublicKey and KeyType
+// can be answered without a round trip.
+func NewSigner(ctx context.Context, config ClientConfig, key string) (*Signer, error) {
+	if config.URL == "" {
+		return nil, errors.New("remote signer url is required")
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+
+	config.URL = strings.TrimSuffix(config.URL, "/")
+
+	s := &Signer{config: config, client: config.Security.HTTPClient()}
+
+	keys, err := s.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, info := range keys {
+		if info.Name == key || info.PublicKey == key {
+			s.key = info
+			return s, nil
+		}
+	}
+
+	return nil, fmt.Errorf("remote signer has no key %s", key)
+}
+
+// Keys lists the keys held by the daemon.
+func (s *Signer) Keys(ctx context.Context) ([]KeyInfo, error) {
+	var response KeysResponse
+	if err := s.do(ctx, http.MethodGet, KeysPath, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Keys, nil
+}
+
+func (s *Signer) PublicKey() string { return s.key.PublicKey }
+
+func (s *Signer) KeyType() helper.KeyType { return s.key.KeyType }
+
+// Sign sends the payload to the daemon, bounded by the configured timeout.
+func (s *Signer) Sign(payload []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+
+	return s.SignWithContext(ctx, payload)
+}
+
+// SignWithContext is Sign with a context bounding the request to the daemon.
+func (s *Signer) SignWithContext(ctx context.Context, payload []byte) ([]byte, error) {
+	var response SignResponse
+	if err := s.do(ctx, http.MethodPost, SignPath, SignRequest{Key: s.key.Name, Payload: payload}, &response); err != nil {
+		return nil, err
+	}
+
+	if response.PublicKey != s.key.PublicKey {
+		return nil, fmt.Errorf("remote signer signed with %s, expected %s", response.PublicKey, s.key.PublicKey)
+	}
+
+	// never pass on a signature that does not verify (ie a compromised or misconfigured daemon)
+	if s.key.KeyType != helper.SPECIAL {
+		if ok, err := helper.Verify(s.key.PublicKey, payload, response.Signature); !ok {
+			return nil, fmt.Errorf("remote signer returned an invalid signature: %v", err)
+		}
+	}
+
+	return response.Signature, nil
+}
+
+func (s *Signer) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.config.URL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if s.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.config.Token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("remote signer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		var errResponse ErrorResponse
+		if json.Unmarshal(data, &errResponse) == nil && errResponse.Error != "" {
+			return fmt.Errorf("remote signer: %s (%d)", errResponse.Error, resp.StatusCode)
+		}
+		return fmt.Errorf("remote signer: %s", resp.Status)
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
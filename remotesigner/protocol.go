@@ -0,0 +1,41 @@
+package remotesigner
+
+import "github.com/ZeraVision/zera-go-sdk/helper"
+
+// The remote signer protocol is JSON over HTTP(S):
+//
+//	GET  /v1/keys -> KeysResponse
+//	POST /v1/sign  SignRequest -> SignResponse
+//
+// Requests carry "Authorization: Bearer <token>" when the daemon is configured with a token.
+// Failures use a non 2xx status with an ErrorResponse body.
+const (
+	KeysPath = "/v1/keys"
+	SignPath = "/v1/sign"
+
+	MaxPayloadSize = 1 << 20 // largest payload the daemon will sign
+)
+
+type KeyInfo struct {
+	Name      string         `json:"name"`
+	PublicKey string         `json:"publicKey"` // base58 public key as used by the builders (ie r_A_c_...)
+	KeyType   helper.KeyType `json:"keyType"`
+}
+
+type KeysResponse struct {
+	Keys []KeyInfo `json:"keys"`
+}
+
+type SignRequest struct {
+	Key     string `json:"key"`     // key name or public key
+	Payload []byte `json:"payload"` // serialized transaction without signature / hash (base64 in JSON)
+}
+
+type SignResponse struct {
+	PublicKey string `json:"publicKey"`
+	Signature []byte `json:"signature"` // base64 in JSON
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,96 @@
+package remotesigner_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/nonce"
+	"github.com/ZeraVision/zera-go-sdk/parts"
+	"github.com/ZeraVision/zera-go-sdk/remotesigner"
+	"github.com/ZeraVision/zera-go-sdk/remotesigner/remotesignertest"
+	"github.com/ZeraVision/zera-go-sdk/transfer"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	TEST_ADDRESS = "8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR"
+	TEST_PUBLIC  = "A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"
+	TEST_PRIVATE = "2ap5CkCekErkqJ4UuSGAW1BmRRRNr8hXaebudv1j8TY6mJMSsbnniakorFGmetE4aegsyQAD8WX1N8Q2Y45YEBDs"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	daemon := remotesignertest.StartLocal(remotesigner.ServerConfig{Token: "secret"})
+	defer daemon.Close()
+
+	daemon.Add("treasury", helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE))
+
+	signer, err := daemon.Signer(context.Background(), "treasury")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if signer.PublicKey() != TEST_PUBLIC || signer.KeyType() != helper.ED25519 {
+		t.Fatalf("Expected the treasury key, got %s (%d)", signer.PublicKey(), signer.KeyType())
+	}
+
+	// Build a transaction with the remote key
+	txn, err := transfer.CreateCoinTxn(
+		nonce.NonceInfo{Override: []uint64{5}},
+		parts.PartsInfo{Symbol: "$ZRA+0000", Override: big.NewInt(1000000000)},
+		[]transfer.Inputs{{B58Address: TEST_ADDRESS, Signer: signer, Amount: "1", FeePercent: 100}},
+		map[string]string{TEST_ADDRESS: "1"},
+		"$ZRA+0000", "1000000000", nil, nil, 5,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	signature := txn.Auth.Signature[0]
+	txn.Auth.Signature = nil
+	txn.Base.Hash = nil
+
+	txnBytes, err := proto.Marshal(txn)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if ok, err := helper.Verify(TEST_PUBLIC, txnBytes, signature); !ok {
+		t.Fatalf("Expected a valid signature, got %v", err)
+	}
+
+	// Keys can also be resolved by public key
+	if _, err := daemon.Signer(context.Background(), TEST_PUBLIC); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if _, err := daemon.Signer(context.Background(), "unknown"); err == nil {
+		t.Error("Expected an error for unknown key, got none")
+	}
+
+	if _, err := remotesigner.NewSigner(context.Background(), remotesigner.ClientConfig{URL: daemon.URL, Token: "wrong"}, "treasury"); err == nil {
+		t.Error("Expected an error for wrong token, got none")
+	}
+}
+
+func TestRemoteSignerPolicy(t *testing.T) {
+	daemon := remotesignertest.StartLocal(remotesigner.ServerConfig{
+		Policy: func(key remotesigner.KeyInfo, payload []byte) error {
+			return errors.New("signing disabled")
+		},
+	})
+	defer daemon.Close()
+
+	daemon.Add("treasury", helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE))
+
+	signer, err := daemon.Signer(context.Background(), "treasury")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := signer.Sign([]byte("payload")); err == nil {
+		t.Fatal("Expected the policy to refuse, got a signature")
+	}
+}
@@ -0,0 +1,46 @@
+// Package remotesignertest runs a remote signing daemon in process, so code built on remotesigner.Signer can be
+// tested (or developed locally) without running zera-signer:
+//
+//	daemon := remotesignertest.StartLocal(remotesigner.ServerConfig{Token: "secret"})
+//	defer daemon.Close()
+//
+//	daemon.Add("treasury", helper.NewPrivateKeySigner(publicKey, privateKey))
+//	signer, _ := daemon.Signer(ctx, "treasury")
+package remotesignertest
+
+import (
+	"context"
+	"net/http/httptest"
+
+	"github.com/ZeraVision/zera-go-sdk/remotesigner"
+)
+
+// LocalDaemon runs a remotesigner.Server on a loopback port.
+type LocalDaemon struct {
+	*remotesigner.Server
+	URL   string
+	token string
+	http  *httptest.Server
+}
+
+// StartLocal starts a daemon on 127.0.0.1, add keys with Add and stop it with Close.
+func StartLocal(config remotesigner.ServerConfig) *LocalDaemon {
+	server := remotesigner.NewServer(config)
+	httpServer := httptest.NewServer(server)
+
+	return &LocalDaemon{
+		Server: server,
+		URL:    httpServer.URL,
+		token:  config.Token,
+		http:   httpServer,
+	}
+}
+
+// Signer connects a client Signer to the daemon for key (name or public key).
+func (d *LocalDaemon) Signer(ctx context.Context, key string) (*remotesigner.Signer, error) {
+	return remotesigner.NewSigner(ctx, remotesigner.ClientConfig{URL: d.URL, Token: d.token}, key)
+}
+
+func (d *LocalDaemon) Close() {
+	d.http.Close()
+}
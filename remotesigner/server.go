@@ -0,0 +1,154 @@
+package remotesigner
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/ZeraVision/zera-go-sdk/helper"
+)
+
+// Policy can veto a signature, ie to restrict a key to certain transaction types. Returning an error refuses to sign.
+type Policy func(key KeyInfo, payload []byte) error
+
+type ServerConfig struct {
+	Token  string      // optional, bearer token required on every request
+	Policy Policy      // optional, checked before every signature
+	Logger *log.Logger // optional, audit log of every signature (disabled if nil)
+}
+
+// Server serves the remote signer protocol for a set of signers (normally loaded from a keystore).
+type Server struct {
+	config ServerConfig
+
+	mu      sync.RWMutex
+	signers map[string]helper.Signer // by name
+	names   map[string]string        // public key -> name
+}
+
+func NewServer(config ServerConfig) *Server {
+	return &Server{
+		config:  config,
+		signers: make(map[string]helper.Signer),
+		names:   make(map[string]string),
+	}
+}
+
+// Add makes a signer available under name.
+func (s *Server) Add(name string, signer helper.Signer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.signers[name] = signer
+	s.names[signer.PublicKey()] = name
+}
+
+func (s *Server) lookup(key string) (string, helper.Signer, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	name := key
+	if byPublicKey, ok := s.names[key]; ok {
+		name = byPublicKey
+	}
+
+	signer, ok := s.signers[name]
+	return name, signer, ok
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "invalid or missing token")
+		return
+	}
+
+	switch {
+	case r.URL.Path == KeysPath && r.Method == http.MethodGet:
+		s.keys(w)
+	case r.URL.Path == SignPath && r.Method == http.MethodPost:
+		s.sign(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.config.Token == "" {
+		return true
+	}
+
+	expected := []byte("Bearer " + s.config.Token)
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) == 1
+}
+
+func (s *Server) keys(w http.ResponseWriter) {
+	s.mu.RLock()
+	response := KeysResponse{Keys: make([]KeyInfo, 0, len(s.signers))}
+	for name, signer := range s.signers {
+		response.Keys = append(response.Keys, KeyInfo{Name: name, PublicKey: signer.PublicKey(), KeyType: signer.KeyType()})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(response.Keys, func(i, j int) bool { return response.Keys[i].Name < response.Keys[j].Name })
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) sign(w http.ResponseWriter, r *http.Request) {
+	var req SignRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 2*MaxPayloadSize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+
+	if len(req.Payload) == 0 || len(req.Payload) > MaxPayloadSize {
+		writeError(w, http.StatusBadRequest, "payload must be between 1 byte and 1MB")
+		return
+	}
+
+	name, signer, ok := s.lookup(req.Key)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown key %s", req.Key))
+		return
+	}
+
+	info := KeyInfo{Name: name, PublicKey: signer.PublicKey(), KeyType: signer.KeyType()}
+
+	if s.config.Policy != nil {
+		if err := s.config.Policy(info, req.Payload); err != nil {
+			s.audit("refused %s: %v", name, err)
+			writeError(w, http.StatusForbidden, fmt.Sprintf("refused by policy: %v", err))
+			return
+		}
+	}
+
+	signature, err := helper.SignWith(signer, req.Payload)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to sign: %v", err))
+		return
+	}
+
+	s.audit("signed %d bytes with %s", len(req.Payload), name)
+
+	writeJSON(w, http.StatusOK, SignResponse{PublicKey: info.PublicKey, Signature: signature})
+}
+
+func (s *Server) audit(format string, args ...interface{}) {
+	if s.config.Logger != nil {
+		s.config.Logger.Printf(format, args...)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, ErrorResponse{Error: message})
+}
@@ -0,0 +1,338 @@
+package track
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/nonce"
+	"github.com/ZeraVision/zera-go-sdk/ratelimit"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
+)
+
+const DefaultPollInterval = 2 * time.Second
+
+type Status int
+
+const (
+	StatusPending   Status = iota // not yet seen in a block
+	StatusConfirmed               // included and executed
+	StatusTimeDelay               // included, execution deferred by the contract's time delay
+	StatusFailed                  // included but rejected (see Receipt.TxnStatus)
+	StatusIncluded                // nonce fallback only: the sender's nonce moved past the transaction's, see TrackInfo
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusPending:
+		return "pending"
+	case StatusConfirmed:
+		return "confirmed"
+	case StatusTimeDelay:
+		return "time delay"
+	case StatusFailed:
+		return "failed"
+	case StatusIncluded:
+		return "included (nonce only)"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// Terminal reports whether the status can no longer change (time delay is terminal for the original transaction,
+// the delayed execution is recorded under the hash with an "i" suffix, see transcode.HexEncodeHash)
+func (s Status) Terminal() bool {
+	return s != StatusPending
+}
+
+type Fee struct {
+	ContractId string   // ie $ZRA+0000
+	Amount     *big.Int // parts
+}
+
+// Receipt is the outcome of a transaction. Only the indexer lookup by hash fills in the network status, block and
+// fees, the nonce fallback reports StatusPending / StatusIncluded alone.
+type Receipt struct {
+	Hash        string        // hex hash the transaction was tracked by
+	Status      Status        // derived from TxnStatus
+	TxnStatus   pb.TXN_STATUS // network status, only set in indexer mode
+	StatusName  string        // network status as reported (ie OK, TIME_DELAY_INITIALIZED)
+	BlockHeight uint64        // only set in indexer mode
+	Timestamp   time.Time     // block time, only set in indexer mode
+	Fees        []Fee         // fees paid, only set in indexer mode
+}
+
+type TrackInfo struct {
+	UseIndexer    bool   // use the ZV indexer if true (full receipt), false use the validator gRPC api (inclusion only)
+	IndexerURL    string // required when useIndexer true
+	Authorization string // required when useIndexer true, Api-Key or Bearer
+
+	// Nonce fallback: validators do not serve transactions by hash as of network version v.1.1.0, so without the
+	// indexer a transaction is reported as StatusIncluded once the nonce of its sender has moved past the
+	// transaction's nonce. The hash is not checked (another transaction with the same nonce looks the same) and
+	// the outcome, block and fees are unknown.
+	Address       string              // required when useIndexer false, base58 address of the transaction sender
+	Nonce         uint64              // required when useIndexer false, nonce of the transaction (Base.Nonce)
+	ValidatorAddr string              // required when useIndexer false (unless ApiClient is set)
+	ApiClient     pb.APIServiceClient // optional, an existing validator api client to use instead of dialing ValidatorAddr
+
+	PollInterval time.Duration             // optional, time between lookups (default 2s)
+	Security     *helper.TransportSecurity // optional, TLS / credentials for the validator and indexer requests
+	RateLimits   *ratelimit.Registry       // optional, per endpoint rate limits (ratelimit.Default if nil)
+}
+
+// Update is sent by TrackTxn every time the status of the transaction changes.
+type Update struct {
+	Receipt *Receipt
+	Err     error // set on the final update if tracking stopped before a terminal status
+}
+
+// WaitForConfirmation polls until the transaction with the given hex hash (transcode.HexEncode(txn.Base.Hash))
+// reaches a terminal status or ctx is done. Use a context deadline to bound the wait.
+func WaitForConfirmation(ctx context.Context, info TrackInfo, hash string) (*Receipt, error) {
+	var last Update
+	for update := range TrackTxn(ctx, info, hash) {
+		last = update
+	}
+
+	if last.Err != nil {
+		return last.Receipt, last.Err
+	}
+
+	// the final update is not sent if ctx was done before it could be
+	if last.Receipt == nil || !last.Receipt.Status.Terminal() {
+		return last.Receipt, fmt.Errorf("transaction %s not confirmed: %w", hash, ctx.Err())
+	}
+
+	return last.Receipt, nil
+}
+
+// TrackTxn polls the transaction in the background, sending an Update on every status change.
+// The channel is closed after a terminal status, a non retryable error or once ctx is done, updates
+// nobody receives are dropped once ctx is done.
+func TrackTxn(ctx context.Context, info TrackInfo, hash string) <-chan Update {
+	updates := make(chan Update, 1)
+
+	go func() {
+		defer close(updates)
+
+		send := func(update Update) bool {
+			select {
+			case updates <- update:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if info.PollInterval <= 0 {
+			info.PollInterval = DefaultPollInterval
+		}
+
+		var previous *Receipt
+
+		for {
+			receipt, err := lookup(ctx, info, hash)
+
+			switch {
+			case err != nil && !zeraerr.IsRetryable(err) && !errors.Is(err, zeraerr.ErrNotFound):
+				send(Update{Receipt: previous, Err: err})
+				return
+			case err == nil && (previous == nil || receipt.Status != previous.Status):
+				previous = receipt
+				if !send(Update{Receipt: receipt}) || receipt.Status.Terminal() {
+					return
+				}
+			}
+
+			select {
+			case <-time.After(info.PollInterval):
+			case <-ctx.Done():
+				// only if there is room or the receiver is waiting
+				select {
+				case updates <- Update{Receipt: previous, Err: fmt.Errorf("transaction %s not confirmed: %w", hash, ctx.Err())}:
+				default:
+				}
+				return
+			}
+		}
+	}()
+
+	return updates
+}
+
+func lookup(ctx context.Context, info TrackInfo, hash string) (*Receipt, error) {
+	if info.UseIndexer {
+		if info.IndexerURL == "" || info.Authorization == "" {
+			return nil, fmt.Errorf("indexerURL and authorization are required when useIndexer is true")
+		}
+
+		return lookupIndexer(ctx, info, hash)
+	}
+
+	if info.Address == "" || info.Nonce == 0 {
+		return nil, fmt.Errorf("address and nonce are required when useIndexer is false")
+	}
+
+	return lookupValidator(ctx, info, hash)
+}
+
+// indexerReceipt is the getTransaction response of the indexer
+type indexerReceipt struct {
+	Hash        string          `json:"hash"`
+	Status      json.RawMessage `json:"status"` // TXN_STATUS name or number, empty / "pending" while not in a block
+	BlockHeight uint64          `json:"blockHeight"`
+	Timestamp   int64           `json:"timestamp"` // unix seconds
+	Fees        []struct {
+		ContractId string `json:"contractId"`
+		Amount     string `json:"amount"`
+	} `json:"fees"`
+}
+
+func lookupIndexer(ctx context.Context, info TrackInfo, hash string) (*Receipt, error) {
+	if _, err := info.RateLimits.Wait(ctx, info.IndexerURL); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/store?requestType=getTransaction&hash=%s", info.IndexerURL, hash)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Add("Target", "indexer")
+
+	// Bearer
+	if strings.Contains(info.Authorization, ".") {
+		req.Header.Add("Authorization", "Bearer "+info.Authorization)
+	} else { // Api Key
+		req.Header.Add("Authorization", "Api-Key "+info.Authorization)
+	}
+
+	resp, err := info.Security.HTTPClient().Do(req)
+	if err != nil {
+		return nil, &zeraerr.Error{Kind: zeraerr.ErrTransient, Message: err.Error(), Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		return nil, zeraerr.FromIndexer(resp.StatusCode, body, nil)
+	}
+
+	var result indexerReceipt
+	if err := json.Unmarshal(body, &result); err != nil {
+		if indexerErr := zeraerr.FromIndexer(resp.StatusCode, body, nil); errors.Is(indexerErr, zeraerr.ErrNotFound) {
+			return nil, indexerErr
+		}
+		return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	receipt := &Receipt{
+		Hash:        hash,
+		BlockHeight: result.BlockHeight,
+	}
+
+	if result.Timestamp > 0 {
+		receipt.Timestamp = time.Unix(result.Timestamp, 0)
+	}
+
+	if err := parseStatus(result.Status, receipt); err != nil {
+		return nil, err
+	}
+
+	for _, fee := range result.Fees {
+		amount, ok := new(big.Int).SetString(fee.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid fee amount %q", fee.Amount)
+		}
+		receipt.Fees = append(receipt.Fees, Fee{ContractId: fee.ContractId, Amount: amount})
+	}
+
+	return receipt, nil
+}
+
+func parseStatus(raw json.RawMessage, receipt *Receipt) error {
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		var number int32
+		if err := json.Unmarshal(raw, &number); err != nil {
+			if len(raw) == 0 || string(raw) == "null" {
+				receipt.Status = StatusPending
+				return nil
+			}
+			return fmt.Errorf("invalid transaction status %s", raw)
+		}
+		name = pb.TXN_STATUS(number).String()
+	}
+
+	name = strings.ToUpper(strings.TrimSpace(name))
+	receipt.StatusName = name
+
+	if name == "" || name == "PENDING" {
+		receipt.Status = StatusPending
+		return nil
+	}
+
+	if value, ok := pb.TXN_STATUS_value[name]; ok {
+		receipt.TxnStatus = pb.TXN_STATUS(value)
+	} else if number, err := strconv.Atoi(name); err == nil {
+		receipt.TxnStatus = pb.TXN_STATUS(number)
+		receipt.StatusName = receipt.TxnStatus.String()
+	} else {
+		return fmt.Errorf("unknown transaction status %s", name)
+	}
+
+	switch {
+	case receipt.StatusName == "OK":
+		receipt.Status = StatusConfirmed
+	case receipt.TxnStatus == pb.TXN_STATUS_TIME_DELAY_INITIALIZED:
+		receipt.Status = StatusTimeDelay
+	default:
+		receipt.Status = StatusFailed
+	}
+
+	return nil
+}
+
+// lookupValidator is the nonce fallback, see TrackInfo
+func lookupValidator(ctx context.Context, info TrackInfo, hash string) (*Receipt, error) {
+	req, err := nonce.MakeNonceRequest(info.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := nonce.GetNonceWithContext(ctx, nonce.NonceInfo{
+		NonceReqs:     []*pb.NonceRequest{req},
+		ValidatorAddr: info.ValidatorAddr,
+		ApiClient:     info.ApiClient,
+		Security:      info.Security,
+		RateLimits:    info.RateLimits,
+	}, 1) // a single lookup, the rate is set by RateLimits
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &Receipt{Hash: hash, Status: StatusPending}
+
+	// next is the nonce the wallet will use next, so a transaction with info.Nonce is in once it has moved past it
+	if next[0] > info.Nonce {
+		receipt.Status = StatusIncluded
+	}
+
+	return receipt, nil
+}
@@ -0,0 +1,268 @@
+package track_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/ratelimit"
+	"github.com/ZeraVision/zera-go-sdk/track"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	TRACK_TEST_HASH = "2b8c5b1f0a6e4d93c7e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7"
+	TRACK_TEST_ADDR = "8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR"
+)
+
+var unlimited = ratelimit.NewRegistry(ratelimit.Config{Rate: -1})
+
+// nonceClient reports a wallet nonce that advances on every request, unless stuck
+type nonceClient struct {
+	pb.APIServiceClient
+	nonce atomic.Uint64
+	stuck bool
+	err   error
+}
+
+func (c *nonceClient) Nonce(ctx context.Context, in *pb.NonceRequest, opts ...grpc.CallOption) (*pb.NonceResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.stuck {
+		return &pb.NonceResponse{Nonce: c.nonce.Load()}, nil
+	}
+	return &pb.NonceResponse{Nonce: c.nonce.Add(1)}, nil
+}
+
+func TestWaitForConfirmation_Indexer(t *testing.T) {
+	var lookups atomic.Int64
+
+	indexer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("hash") != TRACK_TEST_HASH {
+			http.Error(w, "transaction does not exist", http.StatusNotFound)
+			return
+		}
+
+		switch lookups.Add(1) {
+		case 1:
+			http.Error(w, "transaction does not exist", http.StatusNotFound)
+		case 2:
+			fmt.Fprintf(w, `{"hash": %q, "status": "pending"}`, TRACK_TEST_HASH)
+		default:
+			fmt.Fprintf(w, `{"hash": %q, "status": "OK", "blockHeight": 1234, "timestamp": 1700000000, "fees": [{"contractId": "$ZRA+0000", "amount": "1500000"}]}`, TRACK_TEST_HASH)
+		}
+	}))
+	defer indexer.Close()
+
+	info := track.TrackInfo{
+		UseIndexer:    true,
+		IndexerURL:    indexer.URL,
+		Authorization: "test-key",
+		PollInterval:  10 * time.Millisecond,
+		RateLimits:    unlimited,
+	}
+
+	var statuses []track.Status
+	for update := range track.TrackTxn(context.Background(), info, TRACK_TEST_HASH) {
+		if update.Err != nil {
+			t.Fatalf("Expected no error, got %v", update.Err)
+		}
+		statuses = append(statuses, update.Receipt.Status)
+	}
+
+	if len(statuses) != 2 || statuses[0] != track.StatusPending || statuses[1] != track.StatusConfirmed {
+		t.Fatalf("Expected pending then confirmed, got %v", statuses)
+	}
+
+	receipt, err := track.WaitForConfirmation(context.Background(), info, TRACK_TEST_HASH)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if receipt.Status != track.StatusConfirmed || receipt.BlockHeight != 1234 || receipt.Timestamp.Unix() != 1700000000 {
+		t.Errorf("Expected confirmed at height 1234, got %s at %d", receipt.Status, receipt.BlockHeight)
+	}
+
+	if len(receipt.Fees) != 1 || receipt.Fees[0].ContractId != "$ZRA+0000" || receipt.Fees[0].Amount.Int64() != 1500000 {
+		t.Errorf("Expected 1500000 parts of $ZRA+0000 in fees, got %+v", receipt.Fees)
+	}
+}
+
+func TestWaitForConfirmation_NetworkStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		want   track.Status
+		txn    pb.TXN_STATUS
+	}{
+		{fmt.Sprint(int32(pb.TXN_STATUS_TIME_DELAY_INITIALIZED)), track.StatusTimeDelay, pb.TXN_STATUS_TIME_DELAY_INITIALIZED},
+		{`"TIME_DELAY_INITIALIZED"`, track.StatusTimeDelay, pb.TXN_STATUS_TIME_DELAY_INITIALIZED},
+		{`"INSUFFICIENT_AMOUNT"`, track.StatusFailed, pb.TXN_STATUS_INSUFFICIENT_AMOUNT},
+		{fmt.Sprint(int32(pb.TXN_STATUS_FEE_ERROR)), track.StatusFailed, pb.TXN_STATUS_FEE_ERROR},
+	}
+
+	for _, test := range tests {
+		indexer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"status": %s, "blockHeight": 9}`, test.status)
+		}))
+
+		receipt, err := track.WaitForConfirmation(context.Background(), track.TrackInfo{
+			UseIndexer:    true,
+			IndexerURL:    indexer.URL,
+			Authorization: "test-key",
+			RateLimits:    unlimited,
+		}, TRACK_TEST_HASH)
+		indexer.Close()
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if receipt.Status != test.want || receipt.TxnStatus != test.txn || receipt.BlockHeight != 9 {
+			t.Errorf("Expected %s (%s) for status %s, got %s (%s)", test.want, test.txn, test.status, receipt.Status, receipt.StatusName)
+		}
+	}
+}
+
+func TestWaitForConfirmation_IndexerUnauthorized(t *testing.T) {
+	indexer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid api key", http.StatusUnauthorized)
+	}))
+	defer indexer.Close()
+
+	_, err := track.WaitForConfirmation(context.Background(), track.TrackInfo{
+		UseIndexer:    true,
+		IndexerURL:    indexer.URL,
+		Authorization: "test-key",
+		RateLimits:    unlimited,
+	}, TRACK_TEST_HASH)
+
+	if !errors.Is(err, zeraerr.ErrUnauthenticated) {
+		t.Fatalf("Expected unauthenticated, got %v", err)
+	}
+}
+
+func TestWaitForConfirmation_Validator(t *testing.T) {
+	client := &nonceClient{}
+	client.nonce.Store(2)
+
+	receipt, err := track.WaitForConfirmation(context.Background(), track.TrackInfo{
+		Address:      TRACK_TEST_ADDR,
+		Nonce:        6,
+		ApiClient:    client,
+		PollInterval: 10 * time.Millisecond,
+		RateLimits:   unlimited,
+	}, TRACK_TEST_HASH)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// the nonce fallback can not tell which transaction used the nonce, or how it went
+	if receipt.Status != track.StatusIncluded || receipt.BlockHeight != 0 {
+		t.Errorf("Expected included without a block, got %s at %d", receipt.Status, receipt.BlockHeight)
+	}
+
+	if client.nonce.Load() != 6 {
+		t.Errorf("Expected polling to stop once nonce 6 was used, last nonce %d", client.nonce.Load())
+	}
+}
+
+func TestTrackTxn(t *testing.T) {
+	client := &nonceClient{}
+	client.nonce.Store(3)
+
+	info := track.TrackInfo{
+		Address:      TRACK_TEST_ADDR,
+		Nonce:        5,
+		ApiClient:    client,
+		PollInterval: 10 * time.Millisecond,
+		RateLimits:   unlimited,
+	}
+
+	var statuses []track.Status
+	for update := range track.TrackTxn(context.Background(), info, TRACK_TEST_HASH) {
+		if update.Err != nil {
+			t.Fatalf("Expected no error, got %v", update.Err)
+		}
+		statuses = append(statuses, update.Receipt.Status)
+	}
+
+	if len(statuses) != 2 || statuses[0] != track.StatusPending || statuses[1] != track.StatusIncluded {
+		t.Fatalf("Expected pending then included, got %v", statuses)
+	}
+}
+
+func TestWaitForConfirmation_Timeout(t *testing.T) {
+	client := &nonceClient{stuck: true}
+	client.nonce.Store(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	receipt, err := track.WaitForConfirmation(ctx, track.TrackInfo{
+		Address:      TRACK_TEST_ADDR,
+		Nonce:        5,
+		ApiClient:    client,
+		PollInterval: 10 * time.Millisecond,
+		RateLimits:   unlimited,
+	}, TRACK_TEST_HASH)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+
+	if receipt == nil || receipt.Status != track.StatusPending {
+		t.Errorf("Expected the last pending receipt, got %+v", receipt)
+	}
+}
+
+func TestWaitForConfirmation_Unauthorized(t *testing.T) {
+	_, err := track.WaitForConfirmation(context.Background(), track.TrackInfo{
+		Address:    TRACK_TEST_ADDR,
+		Nonce:      5,
+		ApiClient:  &nonceClient{err: status.Error(codes.Unauthenticated, "invalid token")},
+		RateLimits: unlimited,
+	}, TRACK_TEST_HASH)
+
+	if !errors.Is(err, zeraerr.ErrUnauthenticated) {
+		t.Fatalf("Expected unauthenticated, got %v", err)
+	}
+}
+
+func TestTrackTxn_NotReceived(t *testing.T) {
+	client := &nonceClient{stuck: true}
+	client.nonce.Store(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	updates := track.TrackTxn(ctx, track.TrackInfo{
+		Address:      TRACK_TEST_ADDR,
+		Nonce:        5,
+		ApiClient:    client,
+		PollInterval: 10 * time.Millisecond,
+		RateLimits:   unlimited,
+	}, TRACK_TEST_HASH)
+
+	// nobody receives the pending update, tracking still stops once ctx is done
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+
+	count := 0
+	for range updates {
+		count++
+	}
+
+	if count != 1 {
+		t.Errorf("Expected only the pending update, got %d updates", count)
+	}
+}
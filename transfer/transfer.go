@@ -4,15 +4,14 @@ import (
 	"context"
 	"fmt"
 	"math/big"
-	"strings"
 
 	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/convert"
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/nonce"
 	"github.com/ZeraVision/zera-go-sdk/parts"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -22,29 +21,96 @@ type Inputs struct {
 	AllowanceAddr      *string // specify non-empty address of allower if allowance transaction, otherwise leave empty
 	B58Address         string
 	KeyType            helper.KeyType
-	PublicKey          string   // Base 58 encoded
-	PrivateKey         string   // Base 58 encoded
-	Amount             string   // full coins (not parts)
-	FeePercent         float32  // 0-100 max 6 digits of precision
-	ContractFeePercent *float32 // 0-100 max 6 digits of precision
+	PublicKey          string        // Base 58 encoded
+	PrivateKey         string        // Base 58 encoded
+	Signer             helper.Signer // optional, signs this input instead of KeyType / PrivateKey (PublicKey is taken from the signer)
+	Amount             string        // full coins (not parts), ie "1.23" or convert.Amount.String()
+	FeePercent         float32       // 0-100 max 6 digits of precision
+	ContractFeePercent *float32      // 0-100 max 6 digits of precision
 }
 
-func CreateCoinTxn(nonceInfo nonce.NonceInfo, partsInfo parts.PartsInfo, inputs []Inputs, outputs map[string]string, baseFeeID, baseFeeAmountParts string, contractFeeID, contractFeeAmountParts *string, maxRps int) (*pb.CoinTXN, error) {
+// concurrency bounds the number of nonce lookups in flight at once (see nonce.GetNonce), the request rate is set by nonceInfo.RateLimits
+func CreateCoinTxn(nonceInfo nonce.NonceInfo, partsInfo parts.PartsInfo, inputs []Inputs, outputs map[string]string, baseFeeID, baseFeeAmountParts string, contractFeeID, contractFeeAmountParts *string, concurrency int) (*pb.CoinTXN, error) {
+	return CreateCoinTxnWithContext(context.Background(), nonceInfo, partsInfo, inputs, outputs, baseFeeID, baseFeeAmountParts, contractFeeID, contractFeeAmountParts, concurrency)
+}
+
+// CreateCoinTxnWithContext is CreateCoinTxn with a context that is passed through to the nonce and parts lookups.
+func CreateCoinTxnWithContext(ctx context.Context, nonceInfo nonce.NonceInfo, partsInfo parts.PartsInfo, inputs []Inputs, outputs map[string]string, baseFeeID, baseFeeAmountParts string, contractFeeID, contractFeeAmountParts *string, concurrency int) (*pb.CoinTXN, error) {
 
-	parts, err := parts.GetParts(partsInfo)
+	parts, err := parts.GetPartsWithContext(ctx, partsInfo)
 
 	if err != nil {
 		return nil, fmt.Errorf("could not get parts: %v", err)
 	}
 
+	// Parse amount strings (e.g., "1.23"), the first input of an allowance transaction has none
+	inputParts := make([]*big.Int, len(inputs))
+	for i, input := range inputs {
+		if input.Amount == "" {
+			continue
+		}
+
+		if inputParts[i], err = parseAmountToParts(input.Amount, parts); err != nil {
+			return nil, fmt.Errorf("could not parse amount %q: %v", input.Amount, err)
+		}
+	}
+
+	outputParts := make(map[string]*big.Int, len(outputs))
+	for address, amount := range outputs {
+		if outputParts[address], err = parseAmountToParts(amount, parts); err != nil {
+			return nil, fmt.Errorf("could not parse amount %q for address %q: %v", amount, address, err)
+		}
+	}
+
+	return createCoinTxn(ctx, nonceInfo, partsInfo.Symbol, inputs, inputParts, outputParts, baseFeeID, baseFeeAmountParts, contractFeeID, contractFeeAmountParts, concurrency)
+}
+
+// CreateCoinTxnWithAmounts is CreateCoinTxnWithContext with the amounts in full coins of the contract partsInfo looks
+// up, ie convert.ParseAmount("1.23", partsPerCoin). inputAmounts[i] is the amount of inputs[i] (its Amount is not
+// used, the zero value leaves it empty for an allowance transaction). An amount at another denomination (ie
+// convert.ParseDecimal("1.23")) is an error.
+func CreateCoinTxnWithAmounts(ctx context.Context, nonceInfo nonce.NonceInfo, partsInfo parts.PartsInfo, inputs []Inputs, inputAmounts []convert.Amount, outputs map[string]convert.Amount, baseFeeID, baseFeeAmountParts string, contractFeeID, contractFeeAmountParts *string, concurrency int) (*pb.CoinTXN, error) {
+	if len(inputAmounts) != len(inputs) {
+		return nil, fmt.Errorf("expected %d input amounts, got %d", len(inputs), len(inputAmounts))
+	}
+
+	parts, err := parts.GetPartsWithContext(ctx, partsInfo)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not get parts: %v", err)
+	}
+
+	inputParts := make([]*big.Int, len(inputs))
+	for i, amount := range inputAmounts {
+		if amount.PartsPerCoin() == nil {
+			continue
+		}
+
+		if inputParts[i], err = amount.PartsIn(parts); err != nil {
+			return nil, fmt.Errorf("invalid amount for input %d: %v", i, err)
+		}
+	}
+
+	outputParts := make(map[string]*big.Int, len(outputs))
+	for address, amount := range outputs {
+		if outputParts[address], err = amount.PartsIn(parts); err != nil {
+			return nil, fmt.Errorf("invalid amount for address %q: %v", address, err)
+		}
+	}
+
+	return createCoinTxn(ctx, nonceInfo, partsInfo.Symbol, inputs, inputParts, outputParts, baseFeeID, baseFeeAmountParts, contractFeeID, contractFeeAmountParts, concurrency)
+}
+
+// createCoinTxn builds and signs a CoinTXN of symbol with the input and output amounts in parts
+func createCoinTxn(ctx context.Context, nonceInfo nonce.NonceInfo, symbol string, inputs []Inputs, inputParts []*big.Int, outputParts map[string]*big.Int, baseFeeID, baseFeeAmountParts string, contractFeeID, contractFeeAmountParts *string, concurrency int) (*pb.CoinTXN, error) {
 	// Step 1: Process Inputs
-	inputTransfers, auth, keys, totalInput, err := processInputs(nonceInfo, inputs, parts, maxRps)
+	inputTransfers, auth, keys, totalInput, err := processInputs(ctx, nonceInfo, inputs, inputParts, concurrency)
 	if err != nil {
 		return nil, err
 	}
 
 	// Step 2: Process Outputs
-	outputTransfers, totalOutput, err := processOutputs(outputs, parts)
+	outputTransfers, totalOutput, err := processOutputs(outputParts)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +130,7 @@ func CreateCoinTxn(nonceInfo nonce.NonceInfo, partsInfo parts.PartsInfo, inputs
 	txn := &pb.CoinTXN{
 		Auth:              transferAuth,
 		Base:              txnBase,
-		ContractId:        partsInfo.Symbol,
+		ContractId:        symbol,
 		InputTransfers:    inputTransfers,
 		OutputTransfers:   outputTransfers,
 		ContractFeeId:     contractFeeID,
@@ -93,13 +159,11 @@ func CreateCoinTxn(nonceInfo nonce.NonceInfo, partsInfo parts.PartsInfo, inputs
 }
 
 type keyTracking struct {
-	Allowance  bool
-	KeyType    helper.KeyType
-	PrivateKey string
+	Signer helper.Signer
 }
 
 type authTracking struct {
-	PublicKeyBytes   []byte
+	PublicKey        *pb.PublicKey
 	Signature        []byte
 	Nonce            uint64
 	AllowanceAddress []byte
@@ -107,7 +171,7 @@ type authTracking struct {
 }
 
 // For allowance transaction -- first one is your own wallet info index [0], [0+n] is those you are calling, ie first allowance called is at [1].
-func processInputs(nonceInfo nonce.NonceInfo, inputs []Inputs, parts *big.Int, maxRps int) ([]*pb.InputTransfers, []authTracking, map[string]keyTracking, *big.Int, error) {
+func processInputs(ctx context.Context, nonceInfo nonce.NonceInfo, inputs []Inputs, amounts []*big.Int, concurrency int) ([]*pb.InputTransfers, []authTracking, map[string]keyTracking, *big.Int, error) {
 	var (
 		inputTransfers []*pb.InputTransfers
 		auth           []authTracking
@@ -116,7 +180,7 @@ func processInputs(nonceInfo nonce.NonceInfo, inputs []Inputs, parts *big.Int, m
 	)
 
 	// Get nonce
-	nonce, err := nonce.GetNonce(nonceInfo, maxRps)
+	nonce, err := nonce.GetNonceWithContext(ctx, nonceInfo, concurrency)
 	if err != nil {
 		return nil, nil, nil, nil, fmt.Errorf("could not get nonce: %v", err)
 	}
@@ -125,12 +189,19 @@ func processInputs(nonceInfo nonce.NonceInfo, inputs []Inputs, parts *big.Int, m
 
 	for i, input := range inputs {
 		var err error
-		var pubKeyByte []byte
 
-		// Decode public key
-		_, _, pubKeyByte, err = transcode.Base58DecodePublicKey(input.PublicKey)
-		if err != nil {
-			return nil, nil, nil, nil, fmt.Errorf("could not decode public key: %v", err)
+		signer := input.Signer
+		if signer == nil {
+			signer = helper.NewPrivateKeySignerWithKeyType(input.PublicKey, input.PrivateKey, input.KeyType)
+		}
+
+		// Decode public key (allowance inputs have none, the spender signs them)
+		var txnPublicKey *pb.PublicKey
+		if input.AllowanceAddr == nil {
+			txnPublicKey, err = helper.TxnPublicKey(signer)
+			if err != nil {
+				return nil, nil, nil, nil, fmt.Errorf("could not decode public key: %v", err)
+			}
 		}
 
 		// Allowance
@@ -160,25 +231,23 @@ func processInputs(nonceInfo nonce.NonceInfo, inputs []Inputs, parts *big.Int, m
 
 		} else { // regular
 			auth = append(auth, authTracking{
-				PublicKeyBytes: pubKeyByte,
-				Signature:      nil,
-				Nonce:          nonce[i],
+				PublicKey: txnPublicKey,
+				Signature: nil,
+				Nonce:     nonce[i],
 			})
 		}
 
-		// Parse amount string (e.g., "1.23")
-		var amountParts *big.Int
-		if len(input.Amount) < 1 && inputs[i+1].AllowanceAddr != nil { // first allowance index
-			amountParts, err = parseAmountToParts(inputs[i+1].Amount, parts)
+		// Amount in parts
+		amountParts := amounts[i]
+		if amountParts == nil && i+1 < len(inputs) && inputs[i+1].AllowanceAddr != nil { // first allowance index
+			amountParts = amounts[i+1]
 			isAllowance = true
 		} else if isAllowance && i+1 == len(inputs) {
 			break
-		} else {
-			amountParts, err = parseAmountToParts(input.Amount, parts)
 		}
 
-		if err != nil {
-			return nil, nil, nil, nil, fmt.Errorf("could not parse amount %q: %v", input.Amount, err)
+		if amountParts == nil {
+			return nil, nil, nil, nil, fmt.Errorf("missing amount for input %d", i)
 		}
 
 		// Append to inputTransfers
@@ -189,10 +258,10 @@ func processInputs(nonceInfo nonce.NonceInfo, inputs []Inputs, parts *big.Int, m
 		})
 
 		// Add to keys map
-		keys[transcode.Base58Encode(pubKeyByte)] = keyTracking{
-			Allowance:  input.AllowanceAddr != nil,
-			KeyType:    input.KeyType,
-			PrivateKey: input.PrivateKey,
+		if txnPublicKey != nil {
+			keys[keyID(txnPublicKey)] = keyTracking{
+				Signer: signer,
+			}
 		}
 
 		// Update totalInput
@@ -202,24 +271,18 @@ func processInputs(nonceInfo nonce.NonceInfo, inputs []Inputs, parts *big.Int, m
 	return inputTransfers, auth, keys, totalInput, nil
 }
 
-// processOutputs processes output amounts and converts them to parts.
-func processOutputs(outputs map[string]string, parts *big.Int) ([]*pb.OutputTransfers, *big.Int, error) {
+// processOutputs processes output amounts in parts.
+func processOutputs(outputs map[string]*big.Int) ([]*pb.OutputTransfers, *big.Int, error) {
 	var outputsTransfers []*pb.OutputTransfers
 	totalOutput := big.NewInt(0)
 
-	for address, amount := range outputs {
+	for address, amountParts := range outputs {
 		// Decode address
 		decodedAddr, err := transcode.Base58Decode(address)
 		if err != nil {
 			return nil, nil, fmt.Errorf("could not decode address: %v", err)
 		}
 
-		// Parse amount string (e.g., "1.23")
-		amountParts, err := parseAmountToParts(amount, parts)
-		if err != nil {
-			return nil, nil, fmt.Errorf("could not parse amount %q for address %q: %v", amount, address, err)
-		}
-
 		// Append to outputsTransfers
 		outputsTransfers = append(outputsTransfers, &pb.OutputTransfers{
 			WalletAddress: decodedAddr,
@@ -234,55 +297,14 @@ func processOutputs(outputs map[string]string, parts *big.Int) ([]*pb.OutputTran
 }
 
 // parseAmountToParts converts a decimal string (e.g., "1.23") to parts (e.g., 1230 for 1000 parts per coin).
+// Amounts that are not a whole number of parts are an error rather than truncated.
 func parseAmountToParts(amountStr string, partsPerCoin *big.Int) (*big.Int, error) {
-	// Validate input
-	if amountStr == "" || strings.Count(amountStr, ".") > 1 {
-		return nil, fmt.Errorf("invalid amount format")
-	}
-
-	// Split into whole and fractional parts
-	parts := strings.Split(amountStr, ".")
-	wholeStr := parts[0]
-	var fractionalStr string
-	if len(parts) > 1 {
-		fractionalStr = parts[1]
-	} else {
-		fractionalStr = "0"
-	}
-
-	// Convert whole part to big.Int
-	whole, ok := new(big.Int).SetString(wholeStr, 10)
-	if !ok {
-		return nil, fmt.Errorf("invalid whole number %q", wholeStr)
-	}
-
-	// Convert fractional part to big.Int, aligning with partsPerCoin
-	partsPerCoinStr := partsPerCoin.String()
-	precision := len(partsPerCoinStr) - 1 // e.g., 1e9 -> 9 digits of precision
-
-	// Truncate or pad fractional part to match partsPerCoin precision
-	if len(fractionalStr) > precision {
-		fractionalStr = fractionalStr[:precision] // Truncate to precision
-	} else {
-		// Pad with zeros instead of spaces
-		fractionalStr = fractionalStr + strings.Repeat("0", precision-len(fractionalStr))
-	}
-
-	// Convert fractional part to big.Int
-	fractional, ok := new(big.Int).SetString(fractionalStr, 10)
-	if !ok {
-		return nil, fmt.Errorf("invalid fractional number %q", fractionalStr)
-	}
-
-	// Calculate total parts: (whole * partsPerCoin) + fractional
-	result := new(big.Int).Mul(whole, partsPerCoin)
-	result.Add(result, fractional)
-
-	if result.Sign() < 0 {
-		return nil, fmt.Errorf("negative amount not allowed")
+	amount, err := convert.ParseAmount(amountStr, partsPerCoin)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	return amount.Parts(), nil
 }
 
 // Helper Function: Build Transfer Authentication
@@ -290,8 +312,8 @@ func buildTransferAuthentication(auth []authTracking) *pb.TransferAuthentication
 	transferAuth := &pb.TransferAuthentication{}
 	for _, a := range auth {
 
-		if a.PublicKeyBytes != nil {
-			transferAuth.PublicKey = append(transferAuth.PublicKey, &pb.PublicKey{Single: a.PublicKeyBytes})
+		if a.PublicKey != nil {
+			transferAuth.PublicKey = append(transferAuth.PublicKey, a.PublicKey)
 		}
 
 		if a.Nonce != 0 {
@@ -323,31 +345,42 @@ func signTransaction(txn *pb.CoinTXN, keys map[string]keyTracking) (*pb.CoinTXN,
 	}
 
 	for _, auth := range txn.Auth.PublicKey {
-		if key, ok := keys[transcode.Base58Encode(auth.Single)]; ok {
-
-			if key.Allowance {
-				continue
-			}
-
-			signature, err := helper.Sign(key.PrivateKey, txnBytes, key.KeyType)
+		if key, ok := keys[keyID(auth)]; ok {
+			signature, err := helper.SignWith(key.Signer, txnBytes)
 			if err != nil {
 				return nil, fmt.Errorf("could not sign transaction: %v", err)
 			}
 			txn.Auth.Signature = append(txn.Auth.Signature, signature)
 		} else {
-			return nil, fmt.Errorf("could not find private key for public key: %s", transcode.Base58Encode(auth.Single))
+			return nil, fmt.Errorf("could not find private key for public key: %s", keyID(auth))
 		}
 	}
 	return txn, nil
 }
 
-func SendCoinTXN(grpcAddr string, txn *pb.CoinTXN) (*emptypb.Empty, error) {
-	if !strings.Contains(grpcAddr, ":") {
-		grpcAddr += ":50052"
+// keyID identifies an input key, single keys by their base58 encoding, others (ie multi-key) by their serialized form
+func keyID(publicKey *pb.PublicKey) string {
+	if publicKey.GetSingle() != nil {
+		return transcode.Base58Encode(publicKey.GetSingle())
 	}
 
+	data, _ := proto.MarshalOptions{Deterministic: true}.Marshal(publicKey)
+	return transcode.Base58Encode(data)
+}
+
+func SendCoinTXN(grpcAddr string, txn *pb.CoinTXN) (*emptypb.Empty, error) {
+	return SendCoinTXNWithContext(context.Background(), grpcAddr, txn)
+}
+
+// SendCoinTXNWithContext is SendCoinTXN with a context that is passed through to the gRPC call.
+func SendCoinTXNWithContext(ctx context.Context, grpcAddr string, txn *pb.CoinTXN) (*emptypb.Empty, error) {
+	return SendCoinTXNWithSecurity(ctx, grpcAddr, txn, nil)
+}
+
+// SendCoinTXNWithSecurity is SendCoinTXNWithContext with the validator dialed using security (plaintext if nil).
+func SendCoinTXNWithSecurity(ctx context.Context, grpcAddr string, txn *pb.CoinTXN, security *helper.TransportSecurity) (*emptypb.Empty, error) {
 	// Create a gRPC connection to the server
-	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := helper.NewConnection(grpcAddr, "50052", security)
 	if err != nil {
 		return nil, err
 	}
@@ -356,10 +389,10 @@ func SendCoinTXN(grpcAddr string, txn *pb.CoinTXN) (*emptypb.Empty, error) {
 	// Create a new instance of ValidatorNetworkClient
 	client := helper.NewNetworkClient(conn)
 
-	response, err := client.Coin(context.Background(), txn)
+	response, err := client.Coin(ctx, txn)
 
 	if err != nil {
-		return nil, err
+		return nil, zeraerr.FromGRPC(err)
 	}
 
 	return response, nil
@@ -0,0 +1,596 @@
+// Package txnjson is a canonical JSON encoding of transactions for audit logs and APIs. Unlike protojson, which
+// shows every bytes field in base64, fields are written the way ZERA tools show them:
+//
+//	public keys        base58 ZERA notation, ie "A_c_FPXd...", "r_A_c_...", "gov_$ZRA+0000"
+//	multi-key members  class and key, ie "1_A_FPXd..."
+//	addresses          base58, gov_ / sc_ / $ addresses as is
+//	hashes             hex, with the "i" suffix of time delayed executions kept (see transcode.HexEncodeHash)
+//	other bytes        hex (signatures, proposal ids, serialized transactions)
+//	amounts            decimal strings of parts, as in the transaction, 64 bit integers (ie nonces) as decimal strings
+//	timestamps         RFC 3339
+//	enums              names
+//
+// Fields use their JSON names in declaration order, so the encoding of a transaction is always the same, and
+// Unmarshal restores it exactly: proto.Marshal of the result gives the bytes that were signed and hashed.
+//
+//	data, err := txnjson.Marshal(coinTxn)
+//	var decoded pb.CoinTXN
+//	err = txnjson.Unmarshal(data, &decoded)
+package txnjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// hashSize is the length of the SHA3-256 transaction hashes a suffix can follow
+const hashSize = 32
+
+var (
+	publicKeyName = (&pb.PublicKey{}).ProtoReflect().Descriptor().FullName()
+	multiKeyName  = (&pb.MultiKey{}).ProtoReflect().Descriptor().FullName()
+	timestampName = (&timestamppb.Timestamp{}).ProtoReflect().Descriptor().FullName()
+
+	// hashSuffix marks a time delayed execution ("i", see transcode.HexEncodeHash) or a sub transaction ("s1")
+	hashSuffix = regexp.MustCompile(`^(?:i|s\d+)$`)
+)
+
+// Marshal encodes a transaction (or any message nested in one) as canonical JSON.
+func Marshal(txn proto.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeMessage(&buf, txn.ProtoReflect()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalIndent is Marshal with indentation, ie for logs read by people.
+func MarshalIndent(txn proto.Message, prefix, indent string) ([]byte, error) {
+	data, err := Marshal(txn)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, prefix, indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes JSON written by Marshal into txn, which is reset first. Fields are accepted by their JSON or
+// protobuf names, 64 bit integers as strings or numbers and enums by name or number.
+func Unmarshal(data []byte, txn proto.Message) error {
+	proto.Reset(txn)
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("failed to parse json: %v", err)
+	}
+
+	if decoder.More() {
+		return fmt.Errorf("failed to parse json: unexpected data after the transaction")
+	}
+
+	return decodeMessage(txn.ProtoReflect(), value, "")
+}
+
+func encodeMessage(buf *bytes.Buffer, m protoreflect.Message) error {
+	if len(m.GetUnknown()) > 0 {
+		return fmt.Errorf("%s has unknown fields that can not be encoded", m.Descriptor().FullName())
+	}
+
+	switch m.Descriptor().FullName() {
+	case publicKeyName:
+		if key, ok := publicKeyString(m.Interface().(*pb.PublicKey)); ok {
+			return encodeString(buf, key)
+		}
+
+	case timestampName:
+		ts := m.Interface().(*timestamppb.Timestamp)
+		if ts.IsValid() {
+			return encodeString(buf, ts.AsTime().Format(time.RFC3339Nano))
+		}
+	}
+
+	buf.WriteByte('{')
+
+	fields := m.Descriptor().Fields()
+	first := true
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !m.Has(fd) {
+			continue
+		}
+
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		if err := encodeString(buf, fd.JSONName()); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+
+		if err := encodeField(buf, m.Descriptor(), fd, m.Get(fd)); err != nil {
+			return fmt.Errorf("%s: %v", fd.Name(), err)
+		}
+	}
+
+	buf.WriteByte('}')
+	return nil
+}
+
+func encodeField(buf *bytes.Buffer, parent protoreflect.MessageDescriptor, fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		buf.WriteByte('[')
+		for i := 0; i < list.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeValue(buf, parent, fd, list.Get(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+
+	case fd.IsMap():
+		// keys are sorted, map order is random
+		var keys []protoreflect.MapKey
+		v.Map().Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+			keys = append(keys, key)
+			return true
+		})
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeString(buf, key.String()); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := encodeValue(buf, parent, fd.MapValue(), v.Map().Get(key)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	}
+
+	return encodeValue(buf, parent, fd, v)
+}
+
+func encodeValue(buf *bytes.Buffer, parent protoreflect.MessageDescriptor, fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		buf.WriteString(strconv.FormatBool(v.Bool()))
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		buf.WriteString(strconv.FormatInt(v.Int(), 10))
+
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return encodeString(buf, strconv.FormatInt(v.Int(), 10))
+
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return encodeString(buf, strconv.FormatUint(v.Uint(), 10))
+
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		data, err := json.Marshal(v.Float())
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+
+	case protoreflect.StringKind:
+		return encodeString(buf, v.String())
+
+	case protoreflect.BytesKind:
+		return encodeString(buf, encodeBytes(parent, fd, v.Bytes()))
+
+	case protoreflect.EnumKind:
+		if value := fd.Enum().Values().ByNumber(v.Enum()); value != nil {
+			return encodeString(buf, string(value.Name()))
+		}
+		buf.WriteString(strconv.FormatInt(int64(v.Enum()), 10))
+
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return encodeMessage(buf, v.Message())
+
+	default:
+		return fmt.Errorf("unsupported field kind %s", fd.Kind())
+	}
+
+	return nil
+}
+
+func encodeString(buf *bytes.Buffer, s string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// encodeBytes picks the notation of a bytes field from its name, decodeBytes is the inverse
+func encodeBytes(parent protoreflect.MessageDescriptor, fd protoreflect.FieldDescriptor, value []byte) string {
+	name := string(fd.Name())
+
+	switch {
+	case parent.FullName() == multiKeyName && name == "public_keys":
+		if member, ok := memberString(value); ok {
+			return member
+		}
+
+	case strings.Contains(name, "address"):
+		return addressString(value)
+
+	case strings.Contains(name, "hash") && len(value) > hashSize && hashSuffix.Match(value[hashSize:]):
+		return transcode.HexEncode(value[:hashSize]) + string(value[hashSize:])
+	}
+
+	return transcode.HexEncode(value)
+}
+
+func decodeBytes(parent protoreflect.MessageDescriptor, fd protoreflect.FieldDescriptor, value string) ([]byte, error) {
+	name := string(fd.Name())
+
+	switch {
+	case parent.FullName() == multiKeyName && name == "public_keys" && strings.Contains(value, "_"):
+		return parseMember(value)
+
+	case strings.Contains(name, "address"):
+		return parseAddress(value)
+
+	case strings.Contains(name, "hash"):
+		if len(value) > 2*hashSize && hashSuffix.MatchString(value[2*hashSize:]) {
+			hash, err := transcode.HexDecode(value[:2*hashSize])
+			if err != nil {
+				return nil, err
+			}
+			return append(hash, value[2*hashSize:]...), nil
+		}
+	}
+
+	return transcode.HexDecode(value)
+}
+
+// publicKeyString is the base58 notation of a single or gov_ / sc_ key, multi-keys are encoded as a message
+func publicKeyString(key *pb.PublicKey) (string, bool) {
+	if key.GetMulti() != nil {
+		return "", false
+	}
+
+	var encoded []byte
+	switch {
+	case key.GetGovernanceAuth() != nil:
+		encoded = key.GetGovernanceAuth()
+	case key.GetSmartContractAuth() != nil:
+		encoded = key.GetSmartContractAuth()
+	default:
+		encoded = key.GetSingle()
+	}
+
+	parsed, err := helper.ParsePublicKeyBytes(encoded)
+	if err != nil || !proto.Equal(parsed.Proto(), key) {
+		return "", false // not in a form the notation restores, ie a special key in single
+	}
+
+	return parsed.String(), true
+}
+
+// memberString is the class and base58 key of a multi-key member, ie 1_A_FPXd...
+func memberString(member []byte) (string, bool) {
+	class, _, ok := strings.Cut(string(member), "_")
+	if !ok {
+		return "", false
+	}
+
+	parsed, err := helper.ParsePublicKeyBytes(member[len(class)+1:])
+	if err != nil || parsed.Special() {
+		return "", false
+	}
+
+	encoded := class + "_" + parsed.String()
+	if restored, err := parseMember(encoded); err != nil || !bytes.Equal(restored, member) {
+		return "", false
+	}
+
+	return encoded, true
+}
+
+func parseMember(member string) ([]byte, error) {
+	class, key, ok := strings.Cut(member, "_")
+	if !ok {
+		return nil, fmt.Errorf("multi-key member %s is not in class_keytype_key format", member)
+	}
+
+	if _, err := strconv.ParseUint(class, 10, 32); err != nil {
+		return nil, fmt.Errorf("multi-key member %s has an invalid class", member)
+	}
+
+	parsed, err := helper.ParsePublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(class+"_"), parsed.Bytes()...), nil
+}
+
+// addressString is base58 for wallet addresses and the key itself for gov_ / sc_ / $ addresses (base58 has none
+// of these characters, so the two can not be confused)
+func addressString(address []byte) string {
+	if utf8.Valid(address) && isSpecialAddress(string(address)) {
+		return string(address)
+	}
+	return transcode.Base58Encode(address)
+}
+
+func parseAddress(address string) ([]byte, error) {
+	if isSpecialAddress(address) {
+		return []byte(address), nil
+	}
+	return transcode.Base58Decode(address)
+}
+
+func isSpecialAddress(address string) bool {
+	return strings.HasPrefix(address, "gov_") || strings.HasPrefix(address, "sc_") || strings.HasPrefix(address, "$")
+}
+
+func decodeMessage(m protoreflect.Message, value interface{}, path string) error {
+	switch m.Descriptor().FullName() {
+	case publicKeyName:
+		if s, ok := value.(string); ok {
+			parsed, err := helper.ParsePublicKey(s)
+			if err != nil {
+				return fmt.Errorf("%s: %v", pathName(path), err)
+			}
+			proto.Merge(m.Interface(), parsed.Proto())
+			return nil
+		}
+
+	case timestampName:
+		if s, ok := value.(string); ok {
+			parsed, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return fmt.Errorf("%s: invalid timestamp: %v", pathName(path), err)
+			}
+			proto.Merge(m.Interface(), timestamppb.New(parsed))
+			return nil
+		}
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: expected an object for %s", pathName(path), m.Descriptor().FullName())
+	}
+
+	fields := m.Descriptor().Fields()
+	for name, fieldValue := range object {
+		fd := fields.ByJSONName(name)
+		if fd == nil {
+			fd = fields.ByName(protoreflect.Name(name))
+		}
+		if fd == nil {
+			return fmt.Errorf("%s: unknown field %q of %s", pathName(path), name, m.Descriptor().FullName())
+		}
+
+		if err := decodeField(m, fd, fieldValue, join(path, string(fd.Name()))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodeField(m protoreflect.Message, fd protoreflect.FieldDescriptor, value interface{}, path string) error {
+	switch {
+	case fd.IsList():
+		elements, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an array", path)
+		}
+
+		list := m.Mutable(fd).List()
+		for i, element := range elements {
+			elementPath := fmt.Sprintf("%s[%d]", path, i)
+
+			if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+				message := list.NewElement()
+				if err := decodeMessage(message.Message(), element, elementPath); err != nil {
+					return err
+				}
+				list.Append(message)
+				continue
+			}
+
+			decoded, err := decodeValue(m.Descriptor(), fd, element, elementPath)
+			if err != nil {
+				return err
+			}
+			list.Append(decoded)
+		}
+		return nil
+
+	case fd.IsMap():
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object", path)
+		}
+
+		mapValue := m.Mutable(fd).Map()
+		for key, entry := range entries {
+			entryPath := fmt.Sprintf("%s[%s]", path, key)
+
+			mapKey, err := decodeValue(m.Descriptor(), fd.MapKey(), key, entryPath)
+			if err != nil {
+				return err
+			}
+
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				message := mapValue.NewValue()
+				if err := decodeMessage(message.Message(), entry, entryPath); err != nil {
+					return err
+				}
+				mapValue.Set(mapKey.MapKey(), message)
+				continue
+			}
+
+			decoded, err := decodeValue(m.Descriptor(), fd.MapValue(), entry, entryPath)
+			if err != nil {
+				return err
+			}
+			mapValue.Set(mapKey.MapKey(), decoded)
+		}
+		return nil
+
+	case fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind:
+		return decodeMessage(m.Mutable(fd).Message(), value, path)
+	}
+
+	decoded, err := decodeValue(m.Descriptor(), fd, value, path)
+	if err != nil {
+		return err
+	}
+	m.Set(fd, decoded)
+	return nil
+}
+
+func decodeValue(parent protoreflect.MessageDescriptor, fd protoreflect.FieldDescriptor, value interface{}, path string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		switch b := value.(type) {
+		case bool:
+			return protoreflect.ValueOfBool(b), nil
+		case string: // map keys
+			parsed, err := strconv.ParseBool(b)
+			if err == nil {
+				return protoreflect.ValueOfBool(parsed), nil
+			}
+		}
+		return protoreflect.Value{}, fmt.Errorf("%s: expected a boolean", path)
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(numberString(value), 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%s: expected a 32 bit integer", path)
+		}
+		return protoreflect.ValueOfInt32(int32(n)), nil
+
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(numberString(value), 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%s: expected an unsigned 32 bit integer", path)
+		}
+		return protoreflect.ValueOfUint32(uint32(n)), nil
+
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(numberString(value), 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%s: expected a 64 bit integer", path)
+		}
+		return protoreflect.ValueOfInt64(n), nil
+
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(numberString(value), 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%s: expected an unsigned 64 bit integer", path)
+		}
+		return protoreflect.ValueOfUint64(n), nil
+
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		n, err := strconv.ParseFloat(numberString(value), 64)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%s: expected a number", path)
+		}
+		if fd.Kind() == protoreflect.FloatKind {
+			return protoreflect.ValueOfFloat32(float32(n)), nil
+		}
+		return protoreflect.ValueOfFloat64(n), nil
+
+	case protoreflect.StringKind:
+		s, ok := value.(string)
+		if !ok {
+			return protoreflect.Value{}, fmt.Errorf("%s: expected a string", path)
+		}
+		return protoreflect.ValueOfString(s), nil
+
+	case protoreflect.BytesKind:
+		s, ok := value.(string)
+		if !ok {
+			return protoreflect.Value{}, fmt.Errorf("%s: expected a string", path)
+		}
+
+		decoded, err := decodeBytes(parent, fd, s)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%s: %v", path, err)
+		}
+		return protoreflect.ValueOfBytes(decoded), nil
+
+	case protoreflect.EnumKind:
+		if name, ok := value.(string); ok {
+			if enumValue := fd.Enum().Values().ByName(protoreflect.Name(name)); enumValue != nil {
+				return protoreflect.ValueOfEnum(enumValue.Number()), nil
+			}
+		}
+
+		n, err := strconv.ParseInt(numberString(value), 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%s: unknown %s value %v", path, fd.Enum().Name(), value)
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+	}
+
+	return protoreflect.Value{}, fmt.Errorf("%s: unsupported field kind %s", path, fd.Kind())
+}
+
+// numberString accepts numbers and decimal strings, as 64 bit integers are written
+func numberString(value interface{}) string {
+	switch n := value.(type) {
+	case json.Number:
+		return n.String()
+	case string:
+		return n
+	}
+	return ""
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func pathName(path string) string {
+	if path == "" {
+		return "transaction"
+	}
+	return path
+}
@@ -0,0 +1,251 @@
+package txnjson_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/compliance"
+	"github.com/ZeraVision/zera-go-sdk/governance"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/mint"
+	"github.com/ZeraVision/zera-go-sdk/multisig"
+	"github.com/ZeraVision/zera-go-sdk/nonce"
+	"github.com/ZeraVision/zera-go-sdk/offline"
+	"github.com/ZeraVision/zera-go-sdk/parts"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/ZeraVision/zera-go-sdk/transfer"
+	"github.com/ZeraVision/zera-go-sdk/txnjson"
+	"github.com/ZeraVision/zera-go-sdk/wallet"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	TEST_ADDRESS = "8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR"
+	TEST_PUBLIC  = "A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"
+	TEST_PRIVATE = "2ap5CkCekErkqJ4UuSGAW1BmRRRNr8hXaebudv1j8TY6mJMSsbnniakorFGmetE4aegsyQAD8WX1N8Q2Y45YEBDs"
+
+	RECIPIENT_ADDRESS = "Hv3KUwrmR8C8XVSxuJFJrQqeDixeDnakUTkUUMZkFCUS"
+)
+
+// roundTrip encodes txn, decodes it into into and checks the result serializes to the same bytes
+func roundTrip(t *testing.T, txn, into proto.Message) map[string]interface{} {
+	t.Helper()
+
+	data, err := txnjson.Marshal(txn)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := txnjson.Unmarshal(data, into); err != nil {
+		t.Fatalf("Expected no error, got %v\n%s", err, data)
+	}
+
+	original, err := proto.Marshal(txn)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	decoded, err := proto.Marshal(into)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !bytes.Equal(original, decoded) {
+		t.Fatalf("Expected identical protobuf bytes after a round trip of\n%s", data)
+	}
+
+	// the encoding is canonical
+	again, err := txnjson.Marshal(into)
+	if err != nil || !bytes.Equal(again, data) {
+		t.Fatalf("Expected the decoded transaction to encode the same, got\n%s\n%s", data, again)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Expected valid json, got %v", err)
+	}
+	return fields
+}
+
+func TestCoinTxn(t *testing.T) {
+	txn, err := transfer.CreateCoinTxnWithContext(context.Background(), nonce.NonceInfo{Override: []uint64{7}}, parts.PartsInfo{Symbol: "$ZRA+0000", Override: big.NewInt(1_000_000_000)},
+		[]transfer.Inputs{{B58Address: TEST_ADDRESS, Signer: helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE), Amount: "1.5", FeePercent: 100}},
+		map[string]string{RECIPIENT_ADDRESS: "1.5"},
+		"$ZRA+0000", "1000000", nil, nil, 1,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var decoded pb.CoinTXN
+	fields := roundTrip(t, txn, &decoded)
+
+	if err := offline.VerifyTxn(&decoded); err != nil {
+		t.Fatalf("Expected the decoded transaction to verify, got %v", err)
+	}
+
+	base := fields["base"].(map[string]interface{})
+	if base["hash"] != transcode.HexEncode(txn.Base.Hash) || base["feeAmount"] != "1000000" {
+		t.Errorf("Expected hex hash and decimal fee, got %v", base)
+	}
+
+	if _, err := time.Parse(time.RFC3339Nano, base["timestamp"].(string)); err != nil {
+		t.Errorf("Expected an RFC 3339 timestamp, got %v", base["timestamp"])
+	}
+
+	auth := fields["auth"].(map[string]interface{})
+	if auth["publicKey"].([]interface{})[0] != TEST_PUBLIC || auth["nonce"].([]interface{})[0] != "7" {
+		t.Errorf("Expected key %s with nonce \"7\", got %v", TEST_PUBLIC, auth)
+	}
+
+	input := fields["inputTransfers"].([]interface{})[0].(map[string]interface{})
+	output := fields["outputTransfers"].([]interface{})[0].(map[string]interface{})
+	if output["walletAddress"] != RECIPIENT_ADDRESS || output["amount"] != "1500000000" || input["amount"] != "1500000000" {
+		t.Errorf("Expected 1500000000 parts to %s, got %v", RECIPIENT_ADDRESS, output)
+	}
+}
+
+func TestSignedTxns(t *testing.T) {
+	signer := helper.NewPrivateKeySigner("r_"+TEST_PUBLIC, TEST_PRIVATE)
+
+	mintTxn, err := mint.CreateMintTxnWithSigner(context.Background(), nonce.NonceInfo{Override: []uint64{3}}, "$TEST+0000", "2500", RECIPIENT_ADDRESS, signer, "$ZRA+0000", "1000000")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	fields := roundTrip(t, mintTxn, &pb.MintTXN{})
+	if fields["base"].(map[string]interface{})["publicKey"] != "r_"+TEST_PUBLIC || fields["recipientAddress"] != RECIPIENT_ADDRESS {
+		t.Errorf("Expected a restricted key and base58 recipient, got %v", fields)
+	}
+
+	support := false
+	vote, err := governance.CreateVoteTxnWithSigner(context.Background(), nonce.NonceInfo{Override: []uint64{4}}, "$ZRA+0000",
+		"aa01", helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE), "$ZRA+0000", "1000000", &support, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// an optional field set to its zero value is kept
+	fields = roundTrip(t, vote, &pb.GovernanceVote{})
+	if fields["proposalId"] != "aa01" || fields["support"] != false {
+		t.Errorf("Expected hex proposal id and support false, got %v", fields)
+	}
+
+	expiry := time.Date(2027, 1, 2, 3, 4, 5, 600, time.UTC)
+	assign, err := compliance.CreateComplianceTxnWithSigner(context.Background(), nonce.NonceInfo{Override: []uint64{5}}, "$TEST+0000",
+		[]compliance.ComplianceDetails{{WalletAddr: RECIPIENT_ADDRESS, Level: 2, Assign: true, Expiry: timestamppb.New(expiry)}},
+		signer, "$ZRA+0000", "1000000")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	fields = roundTrip(t, assign, &pb.ComplianceTXN{})
+	complianceAssign := fields["compliance"].([]interface{})[0].(map[string]interface{})
+	if complianceAssign["expiry"] != "2027-01-02T03:04:05.0000006Z" {
+		t.Errorf("Expected the expiry in RFC 3339, got %v", complianceAssign["expiry"])
+	}
+}
+
+func TestSpecialKeys(t *testing.T) {
+	_, public, _, err := wallet.GenerateEd448("txnjson member", helper.BLAKE3, helper.ED448)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	account, err := multisig.NewAccount(helper.MultiKeyHelper{
+		MultiKey: []helper.MultiKey{
+			{Class: 1, PublicKey: TEST_PUBLIC},
+			{Class: 2, PublicKey: public},
+		},
+		Pattern:    [][]helper.MultiPatterns{{{Class: 1, Required: 1}}},
+		HashTokens: []helper.HashType{helper.RESTRICTED, helper.BLAKE3},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	unsigned, err := mint.CreateMintTxnWithSigner(context.Background(), nonce.NonceInfo{Override: []uint64{1}}, "$TEST+0000", "1", RECIPIENT_ADDRESS, account, "$ZRA+0000", "1000000")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// the treasury of a contract
+	unsigned.RecipientAddress = []byte("gov_$TEST+0000")
+
+	env, err := offline.Prepare(unsigned)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// only the class 1 member signs, the class 2 signature stays empty
+	if err := offline.Sign(env, helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	signed, err := offline.Assemble(env)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	fields := roundTrip(t, signed, &pb.MintTXN{})
+	multi := fields["base"].(map[string]interface{})["publicKey"].(map[string]interface{})["multi"].(map[string]interface{})
+	members := multi["publicKeys"].([]interface{})
+	if members[0] != "1_A_"+TEST_PUBLIC[len("A_c_"):] || !strings.HasPrefix(members[1].(string), "2_B_") || fields["recipientAddress"] != "gov_$TEST+0000" {
+		t.Errorf("Expected class prefixed members and a gov_ recipient, got %v", fields)
+	}
+
+	// a governance key and a time delayed execution hash
+	hash := append(transcode.SHA3256([]byte("delayed")), 'i')
+	base := &pb.BaseTXN{PublicKey: &pb.PublicKey{GovernanceAuth: []byte("gov_$ZRA+0000")}, Hash: hash, Nonce: 9}
+
+	fields = roundTrip(t, base, &pb.BaseTXN{})
+	if fields["publicKey"] != "gov_$ZRA+0000" || fields["hash"] != transcode.HexEncodeHash(hash[:32], pb.TXN_STATUS_TIME_DELAY_INITIALIZED) {
+		t.Errorf("Expected a gov_ key and a hash with the i suffix, got %v", fields)
+	}
+
+	// keys that are not in a form the notation restores are kept as fields
+	odd := &pb.BaseTXN{PublicKey: &pb.PublicKey{Single: []byte("gov_$ZRA+0000")}}
+	fields = roundTrip(t, odd, &pb.BaseTXN{})
+	if _, ok := fields["publicKey"].(map[string]interface{}); !ok {
+		t.Errorf("Expected a gov_ key in single to be encoded as a message, got %v", fields["publicKey"])
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"not json", `{"contractId":`},
+		{"trailing data", `{} {}`},
+		{"unknown field", `{"contractId":"$TEST+0000","unknown":1}`},
+		{"bad address", `{"recipientAddress":"0OIl"}`},
+		{"bad key", `{"base":{"publicKey":"C_c_abc"}}`},
+		{"bad nonce", `{"base":{"nonce":"-1"}}`},
+		{"bad timestamp", `{"base":{"timestamp":"yesterday"}}`},
+		{"wrong type", `{"amount":5}`},
+	}
+
+	for _, tt := range tests {
+		if err := txnjson.Unmarshal([]byte(tt.json), &pb.MintTXN{}); err == nil {
+			t.Errorf("%s: expected an error, got none", tt.name)
+		}
+	}
+
+	// protobuf names and numbers are accepted as well
+	var txn pb.MintTXN
+	if err := txnjson.Unmarshal([]byte(`{"contract_id":"$TEST+0000","base":{"nonce":12}}`), &txn); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if txn.ContractId != "$TEST+0000" || txn.Base.Nonce != 12 {
+		t.Errorf("Expected contract $TEST+0000 with nonce 12, got %v", &txn)
+	}
+}
@@ -1,8 +1,11 @@
 package wallet
 
 import (
+	"bytes"
 	"errors"
 
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+
 	"github.com/ZeraVision/zera-go-sdk/helper"
 	"github.com/ZeraVision/zera-go-sdk/transcode"
 	"github.com/zeebo/blake3"
@@ -44,3 +47,91 @@ func GetWalletAddress(publicKey []byte, hashAlg helper.HashType, keyType helper.
 
 	return processedPublicKey, transcode.Base58Encode(byteAddr), nil
 }
+
+// HashChain applies the hash tokens in order (ie c_a = SHA3-256(BLAKE3(data))). RESTRICTED (r) is a marker, not a hash, and is skipped.
+func HashChain(data []byte, hashTokens []helper.HashType) ([]byte, error) {
+	hashed := data
+	applied := 0
+
+	for _, token := range hashTokens {
+		switch token {
+		case helper.RESTRICTED:
+			continue
+		case helper.BLAKE3:
+			hashed = transcode.Blake3(hashed)
+		case helper.SHA3_256:
+			hashed = transcode.SHA3256(hashed)
+		case helper.SHA3_512:
+			hashed = transcode.SHA3512(hashed)
+		default:
+			return nil, errors.New("unsupported hash algorithm")
+		}
+		applied++
+	}
+
+	if applied == 0 {
+		return nil, errors.New("at least one hash token is required")
+	}
+
+	return hashed, nil
+}
+
+// ErrMultiKeyAddress is returned for multi-keys. Deriving their address is not implemented: the network's derivation
+// is not published and there are no network-confirmed addresses to test one against, so the SDK does not guess.
+var ErrMultiKeyAddress = errors.New("deriving multi-key addresses is not implemented")
+
+// GetAddress derives the wallet address of any public key variant. It returns the address bytes (as used in
+// transactions and nonce requests) and its display form:
+//   - Single: the key hashed with its hash tokens (as GetWalletAddress), base58
+//   - Governance, SmartContract and Inheritence: the key itself (ie gov_$ZRA+0000), which is not base58 encoded
+//   - Multi: ErrMultiKeyAddress
+func GetAddress(publicKey helper.PublicKey) ([]byte, string, error) {
+	key, err := helper.GeneratePublicKey(publicKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return GetAddressFromKey(key)
+}
+
+// GetAddressFromKey is GetAddress for a public key as it appears in a transaction.
+func GetAddressFromKey(key *pb.PublicKey) ([]byte, string, error) {
+	switch {
+	case key.GetMulti() != nil:
+		return nil, "", ErrMultiKeyAddress
+
+	case key.GetGovernanceAuth() != nil:
+		return key.GetGovernanceAuth(), string(key.GetGovernanceAuth()), nil
+
+	case key.GetSmartContractAuth() != nil:
+		return key.GetSmartContractAuth(), string(key.GetSmartContractAuth()), nil
+
+	case bytes.HasPrefix(key.GetSingle(), []byte("gov_")) || bytes.HasPrefix(key.GetSingle(), []byte("sc_")) || bytes.HasPrefix(key.GetSingle(), []byte("$")):
+		return key.GetSingle(), string(key.GetSingle()), nil // gov_ / sc_ given as a single key, inheritence
+
+	case key.GetSingle() != nil:
+		return singleAddress(key.GetSingle())
+
+	default:
+		return nil, "", errors.New("public key is empty")
+	}
+}
+
+// singleAddress hashes the key of keytype_hashtokens_key (optionally r_ prefixed) with its hash tokens
+func singleAddress(single []byte) ([]byte, string, error) {
+	parsed, err := helper.ParsePublicKeyBytes(single)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(parsed.HashTokens) == 0 {
+		return nil, "", errors.New("public key has no hash prefix")
+	}
+
+	address, err := HashChain(parsed.Key, parsed.HashTokens)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return address, transcode.Base58Encode(address), nil
+}
@@ -0,0 +1,83 @@
+package wallet_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/ZeraVision/zera-go-sdk/wallet"
+)
+
+func TestGetAddress(t *testing.T) {
+	single := func(key string) helper.PublicKey { return helper.PublicKey{Single: &key} }
+	symbol := "$ZRA+0000"
+
+	tests := []struct {
+		name      string
+		publicKey helper.PublicKey
+		expected  string
+	}{
+		{"single ED25519 BLAKE3", single("A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"), "8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR"},
+		{"single restricted", single("r_A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"), "8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR"},
+		{"single ED448 SHA3-256", single("B_a_8TZAaoUWbGvkxaWdWBXJ3mVHXVXLDJgtbeexkBzj5ySjpru7yZvfuKwGGHt2gtFpQfQCaRnBPU43bV"), "GmAMncQSf9xxCcyib1Xx7jVdXesD868s86XJiwTTspU1"},
+		{"single chained", single("A_c_a_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"), "42KU4CtKqBPu6JWpWkxQGiyZtStva9D75QxhEbbeBmTE"},
+		{"governance", helper.PublicKey{Governance: &symbol}, "gov_$ZRA+0000"},
+		{"smart contract", helper.PublicKey{SmartContract: &helper.SmartContractHelper{Name: "exchange", Instance: 1}}, "sc_exchange_1"},
+		{"inheritence", helper.PublicKey{Inheritence: &symbol}, "$ZRA+0000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addressBytes, address, err := wallet.GetAddress(tt.publicKey)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if address != tt.expected {
+				t.Errorf("Address mismatch. Expected: %s, Got: %s", tt.expected, address)
+			}
+
+			if len(addressBytes) == 0 {
+				t.Error("Expected address bytes")
+			}
+		})
+	}
+}
+
+func TestGetAddressMultiKey(t *testing.T) {
+	multi := helper.MultiKeyHelper{
+		MultiKey: []helper.MultiKey{
+			{Class: 1, PublicKey: "1_A_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"},
+			{Class: 2, PublicKey: "2_B_8TZAaoUWbGvkxaWdWBXJ3mVHXVXLDJgtbeexkBzj5ySjpru7yZvfuKwGGHt2gtFpQfQCaRnBPU43bV"},
+		},
+		Pattern: [][]helper.MultiPatterns{
+			{
+				{Class: 1, Required: 1},
+				{Class: 2, Required: 1},
+			},
+		},
+		HashTokens: []helper.HashType{helper.BLAKE3, helper.SHA3_256},
+	}
+
+	if _, _, err := wallet.GetAddress(helper.PublicKey{Multi: &multi}); !errors.Is(err, wallet.ErrMultiKeyAddress) {
+		t.Fatalf("Expected multi-key addresses to be unimplemented, got %v", err)
+	}
+}
+
+func TestHashChain(t *testing.T) {
+	data := []byte("zera")
+
+	chained, err := wallet.HashChain(data, []helper.HashType{helper.RESTRICTED, helper.BLAKE3, helper.SHA3_512})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if transcode.HexEncode(chained) != transcode.HexEncode(transcode.SHA3512(transcode.Blake3(data))) {
+		t.Error("Expected SHA3-512(BLAKE3(data))")
+	}
+
+	if _, err := wallet.HashChain(data, []helper.HashType{helper.RESTRICTED}); err == nil {
+		t.Error("Expected an error without a hash token, got none")
+	}
+}
@@ -1,27 +1,21 @@
 package wallet
 
 import (
-	"log"
 	"strings"
 
 	"github.com/ZeraVision/zera-go-sdk/helper"
 )
 
+// Deprecated: use helper.ParsePublicKey. gov_, sc_, inheritence ($ZRA+0000) and "special" keys resolve to helper.SPECIAL.
 func DetermineKeyType(publicKeyBase58 string) (helper.KeyType, error) {
-
-	if strings.HasPrefix(publicKeyBase58, "r_") {
-		publicKeyBase58 = publicKeyBase58[2:]
+	if strings.HasPrefix(publicKeyBase58, "$") || strings.HasPrefix(publicKeyBase58, "special") {
+		return helper.SPECIAL, nil
 	}
 
-	var keyType helper.KeyType
-	if strings.HasPrefix(publicKeyBase58, "A") {
-		keyType = helper.ED25519
-	} else if strings.HasPrefix(publicKeyBase58, "B") {
-		keyType = helper.ED448
-	} else if strings.HasPrefix(publicKeyBase58, "special") {
-		log.Printf("Special key type inferred: %s", publicKeyBase58)
-		keyType = helper.SPECIAL
+	parsed, err := helper.ParsePublicKey(publicKeyBase58)
+	if err != nil {
+		return helper.Unknown, err
 	}
 
-	return keyType, nil
+	return parsed.KeyType, nil
 }
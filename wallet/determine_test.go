@@ -0,0 +1,38 @@
+package wallet_test
+
+import (
+	"testing"
+
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/wallet"
+)
+
+func TestDetermineKeyType(t *testing.T) {
+	tests := []struct {
+		publicKey string
+		expected  helper.KeyType
+	}{
+		{"A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7", helper.ED25519},
+		{"r_B_a_8TZAaoUWbGvkxaWdWBXJ3mVHXVXLDJgtbeexkBzj5ySjpru7yZvfuKwGGHt2gtFpQfQCaRnBPU43bV", helper.ED448},
+		{"gov_$ZRA+0000", helper.SPECIAL},
+		{"sc_exchange_1", helper.SPECIAL},
+		{"$ZRA+0000", helper.SPECIAL},
+	}
+
+	for _, tt := range tests {
+		keyType, err := wallet.DetermineKeyType(tt.publicKey)
+		if err != nil {
+			t.Fatalf("Expected no error for %s, got %v", tt.publicKey, err)
+		}
+
+		if keyType != tt.expected {
+			t.Errorf("Expected key type %v for %s, got %v", tt.expected, tt.publicKey, keyType)
+		}
+	}
+
+	for _, publicKey := range []string{"", "FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7", "C_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7", "A_c_0OIl"} {
+		if _, err := wallet.DetermineKeyType(publicKey); err == nil {
+			t.Errorf("Expected an error for %q, got none", publicKey)
+		}
+	}
+}
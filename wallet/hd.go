@@ -0,0 +1,223 @@
+package wallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/sha3"
+)
+
+const HardenedOffset = 0x80000000
+
+type DerivationConfig struct {
+	Mnemonic   string          // required, BIP39 mnemonic
+	Passphrase string          // optional, BIP39 passphrase (requires a Path)
+	Path       string          // optional, hardened path (ie AccountPath), empty uses the single-key derivation of GenerateEd25519 / GenerateEd448
+	KeyType    helper.KeyType  // ED25519 or ED448 (ED448 only without a Path, see DeriveEd448NonStandard)
+	HashAlg    helper.HashType // hash used for the address (BLAKE3, SHA3_256, SHA3_512)
+}
+
+type DerivedKey struct {
+	Path       string // empty for the single-key derivation
+	PrivateKey string // base58
+	PublicKey  string // base58 with prefix, ie A_c_...
+	Address    string // base58
+}
+
+// AccountPath returns the hardened BIP44 path m/44'/coinType'/account'/index'. Use the coin type of the wallet the
+// keys must match, the SDK does not assume one.
+func AccountPath(coinType, account, index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/%d'/%d'", coinType, account, index)
+}
+
+// DeriveKey derives a key pair from a mnemonic. Without a Path it is identical to GenerateEd25519 / GenerateEd448
+// (one key per mnemonic), with a Path the BIP39 seed (mnemonic + passphrase) is derived along the path with SLIP-0010,
+// which is only defined for ED25519.
+func DeriveKey(config DerivationConfig) (*DerivedKey, error) {
+	if config.Mnemonic == "" {
+		return nil, errors.New("mnemonic is required")
+	}
+
+	if config.Path == "" {
+		if config.Passphrase != "" {
+			return nil, errors.New("a passphrase requires a derivation path")
+		}
+
+		var privateKey, publicKey, address string
+		var err error
+		switch config.KeyType {
+		case helper.ED25519:
+			privateKey, publicKey, address, err = GenerateEd25519(config.Mnemonic, config.HashAlg, config.KeyType)
+		case helper.ED448:
+			privateKey, publicKey, address, err = GenerateEd448(config.Mnemonic, config.HashAlg, config.KeyType)
+		default:
+			return nil, errors.New("unsupported key type")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return &DerivedKey{PrivateKey: privateKey, PublicKey: publicKey, Address: address}, nil
+	}
+
+	if config.KeyType != helper.ED25519 {
+		return nil, errors.New("derivation paths are only supported for ed25519 keys (SLIP-0010)")
+	}
+
+	seed, path, err := pathSeed(config)
+	if err != nil {
+		return nil, err
+	}
+
+	keySeed, err := DeriveSLIP10(seed, path)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, rawPublicKey, err := GenerateKeyPairLibsodium(keySeed)
+	if err != nil {
+		return nil, err
+	}
+
+	return derivedKey(config, privateKey, rawPublicKey)
+}
+
+// DeriveEd448NonStandard derives an ED448 key pair along config.Path. SLIP-0010 does not define Ed448, so this is a
+// construction of this SDK only: SLIP-0010 hardened derivation with the curve key "ed448 seed", the 57 byte private
+// key expanded from the 32 byte node key with SHAKE256. Keys derived this way will not match those of any other
+// wallet for the same mnemonic and path.
+func DeriveEd448NonStandard(config DerivationConfig) (*DerivedKey, error) {
+	if config.Mnemonic == "" {
+		return nil, errors.New("mnemonic is required")
+	}
+
+	if config.Path == "" {
+		return nil, errors.New("a derivation path is required, use DeriveKey for the single-key derivation")
+	}
+
+	if config.KeyType != helper.ED448 {
+		return nil, errors.New("key type must be ed448")
+	}
+
+	seed, path, err := pathSeed(config)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := deriveHardened("ed448 seed", seed, path)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded := make([]byte, 57)
+	shake := sha3.NewShake256()
+	shake.Write(key)
+	shake.Read(expanded)
+
+	privateKey, rawPublicKey, err := GenerateKeyPairEd448(expanded)
+	if err != nil {
+		return nil, err
+	}
+
+	return derivedKey(config, privateKey, rawPublicKey)
+}
+
+// pathSeed returns the BIP39 seed of the mnemonic and passphrase with the parsed path
+func pathSeed(config DerivationConfig) ([]byte, []uint32, error) {
+	if !bip39.IsMnemonicValid(config.Mnemonic) {
+		return nil, nil, errors.New("invalid BIP39 mnemonic")
+	}
+
+	path, err := ParsePath(config.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bip39.NewSeed(config.Mnemonic, config.Passphrase), path, nil
+}
+
+func derivedKey(config DerivationConfig, privateKey, rawPublicKey []byte) (*DerivedKey, error) {
+	_, address, err := GetWalletAddress(rawPublicKey, config.HashAlg, config.KeyType)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey := &helper.ParsedPublicKey{KeyType: config.KeyType, HashTokens: []helper.HashType{config.HashAlg}, Key: rawPublicKey}
+
+	return &DerivedKey{
+		Path:       config.Path,
+		PrivateKey: transcode.Base58Encode(privateKey),
+		PublicKey:  publicKey.String(),
+		Address:    address,
+	}, nil
+}
+
+// ParsePath parses a derivation path (ie m/44'/0'/0'/0'). Only hardened indexes are supported, as with SLIP-0010
+// for Edwards curves; H or h may be used instead of '.
+func ParsePath(path string) ([]uint32, error) {
+	elements := strings.Split(path, "/")
+	if len(elements) < 2 || elements[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q", path)
+	}
+
+	var indexes []uint32
+	for _, element := range elements[1:] {
+		trimmed := strings.TrimRight(element, "'hH")
+		if trimmed == element || len(element)-len(trimmed) != 1 {
+			return nil, fmt.Errorf("derivation path %q: %q is not hardened", path, element)
+		}
+
+		index, err := strconv.ParseUint(trimmed, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("derivation path %q: invalid index %q", path, element)
+		}
+
+		indexes = append(indexes, uint32(index)+HardenedOffset)
+	}
+
+	return indexes, nil
+}
+
+// DeriveSLIP10 derives the ed25519 private key seed at path (hardened indexes, see ParsePath) from a BIP39 seed,
+// as specified by SLIP-0010.
+func DeriveSLIP10(seed []byte, path []uint32) ([]byte, error) {
+	return deriveHardened("ed25519 seed", seed, path)
+}
+
+// deriveHardened is the SLIP-0010 hardened derivation for the curve key, returning the 32 byte node key
+func deriveHardened(curve string, seed []byte, path []uint32) ([]byte, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, errors.New("seed must be between 16 and 64 bytes")
+	}
+
+	mac := hmac.New(sha512.New, []byte(curve))
+	mac.Write(seed)
+	node := mac.Sum(nil)
+	key, chainCode := node[:32], node[32:]
+
+	for _, index := range path {
+		if index < HardenedOffset {
+			return nil, fmt.Errorf("index %d is not hardened", index)
+		}
+
+		data := make([]byte, 0, 37)
+		data = append(data, 0x00)
+		data = append(data, key...)
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		node = mac.Sum(nil)
+		key, chainCode = node[:32], node[32:]
+	}
+
+	return key, nil
+}
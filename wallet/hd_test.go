@@ -0,0 +1,118 @@
+package wallet_test
+
+import (
+	"testing"
+
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/ZeraVision/zera-go-sdk/wallet"
+)
+
+// SLIP-0010 test vector 1 for ed25519
+func TestDeriveSLIP10(t *testing.T) {
+	seed, _ := transcode.HexDecode("000102030405060708090a0b0c0d0e0f")
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"m", "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7"},
+		{"m/0'", "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3"},
+		{"m/0'/1'", "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2"},
+		{"m/0'/1'/2'/2'/1000000000'", "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793"},
+	}
+
+	for _, tt := range tests {
+		var path []uint32
+		if tt.path != "m" {
+			var err error
+			path, err = wallet.ParsePath(tt.path)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+
+		key, err := wallet.DeriveSLIP10(seed, path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if transcode.HexEncode(key) != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.path, tt.expected, transcode.HexEncode(key))
+		}
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	mnemonic := "crumble tattoo grape hurry pizza inject remind play believe museum thing mosquito"
+
+	// no path is the single-key derivation
+	legacy, err := wallet.DeriveKey(wallet.DerivationConfig{Mnemonic: mnemonic, KeyType: helper.ED25519, HashAlg: helper.BLAKE3})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if legacy.PublicKey != "A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7" || legacy.Address != "8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR" {
+		t.Errorf("Expected the GenerateEd25519 key, got %+v", legacy)
+	}
+
+	// SLIP-0044 coin type 1, testnet of all coins
+	const coinType = 1
+
+	derive := map[helper.KeyType]func(wallet.DerivationConfig) (*wallet.DerivedKey, error){
+		helper.ED25519: wallet.DeriveKey,
+		helper.ED448:   wallet.DeriveEd448NonStandard,
+	}
+
+	seen := map[string]bool{legacy.Address: true}
+	for _, keyType := range []helper.KeyType{helper.ED25519, helper.ED448} {
+		for _, config := range []wallet.DerivationConfig{
+			{Path: wallet.AccountPath(coinType, 0, 0)},
+			{Path: wallet.AccountPath(coinType, 0, 1)},
+			{Path: wallet.AccountPath(coinType, 1, 0)},
+			{Path: wallet.AccountPath(coinType, 0, 0), Passphrase: "deposit"},
+		} {
+			config.Mnemonic = mnemonic
+			config.KeyType = keyType
+			config.HashAlg = helper.BLAKE3
+
+			key, err := derive[keyType](config)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if seen[key.Address] {
+				t.Errorf("%s: expected a distinct address, got %s again", key.Path, key.Address)
+			}
+			seen[key.Address] = true
+
+			// derivation is deterministic and the keys sign
+			again, _ := derive[keyType](config)
+			if again.PrivateKey != key.PrivateKey {
+				t.Errorf("%s: expected the same key on every derivation", key.Path)
+			}
+
+			signature, err := helper.Sign(key.PrivateKey, []byte("payload"), keyType)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if ok, err := helper.Verify(key.PublicKey, []byte("payload"), signature); !ok {
+				t.Errorf("%s: expected a valid signature, got %v", key.Path, err)
+			}
+		}
+	}
+
+	// SLIP-0010 does not define ed448
+	if _, err := wallet.DeriveKey(wallet.DerivationConfig{Mnemonic: mnemonic, Path: wallet.AccountPath(coinType, 0, 0), KeyType: helper.ED448, HashAlg: helper.BLAKE3}); err == nil {
+		t.Error("Expected an error for an ed448 path, got none")
+	}
+
+	if _, err := wallet.DeriveKey(wallet.DerivationConfig{Mnemonic: mnemonic, Path: "m/44'/0", KeyType: helper.ED25519, HashAlg: helper.BLAKE3}); err == nil {
+		t.Error("Expected an error for a non hardened path, got none")
+	}
+
+	if _, err := wallet.DeriveKey(wallet.DerivationConfig{Mnemonic: mnemonic, Passphrase: "x", KeyType: helper.ED25519, HashAlg: helper.BLAKE3}); err == nil {
+		t.Error("Expected an error for a passphrase without path, got none")
+	}
+}
@@ -0,0 +1,316 @@
+package zera
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/nonce"
+	"github.com/ZeraVision/zera-go-sdk/parts"
+	"github.com/ZeraVision/zera-go-sdk/ratelimit"
+	"github.com/ZeraVision/zera-go-sdk/track"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	DefaultTXNPort  = "50052" // port of the validator TXNService
+	DefaultAPIPort  = "50053" // port of the validator APIService (nonce etc)
+	DefaultPoolSize = 4       // connections held open per validator
+)
+
+type Config struct {
+	Validators []string // validator hosts (ie 125.253.87.133 or host:50052), at least one required
+	APIPort    string   // optional, port used for the APIService on each validator (default 50053)
+	PoolSize   int      // optional, number of connections per validator (default 4)
+
+	Security *helper.TransportSecurity // optional, TLS / credentials for validator and indexer connections (plaintext if nil)
+
+	MaxRetries          int           // optional, retries on transient errors, each on the next healthy validator (default 2, -1 to disable)
+	RetryBackoff        time.Duration // optional, wait before the first retry, doubled each retry (default 200ms)
+	MaxRetryBackoff     time.Duration // optional, the most RetryBackoff is doubled to (default 5s)
+	HealthCheckInterval time.Duration // optional, how often validator connections are checked (default 10s, -1 to disable)
+
+	RateLimit *ratelimit.Config // optional, per endpoint limits for submissions, nonce and parts lookups (not limited if nil)
+}
+
+// Client is a long lived connection pool to one or more validators.
+// It is safe for concurrent use and should be created once and shared, rather than per transaction.
+// Submissions that fail with a transient error (unavailable, overloaded...) are retried on the next healthy validator.
+type Client struct {
+	security        *helper.TransportSecurity
+	limits          *ratelimit.Registry
+	endpoints       []*endpoint
+	next            atomic.Uint64
+	maxRetries      int
+	retryBackoff    time.Duration
+	maxRetryBackoff time.Duration
+	done            chan struct{}
+	closeOnce       sync.Once
+}
+
+type endpoint struct {
+	addr     string
+	apiAddr  string
+	txnConns []*grpc.ClientConn
+	apiConns []*grpc.ClientConn
+	next     atomic.Uint64
+	healthy  atomic.Bool
+}
+
+// NewClient opens a pool of connections to every validator in config.
+func NewClient(config Config) (*Client, error) {
+	if len(config.Validators) < 1 {
+		return nil, errors.New("at least one validator is required")
+	}
+
+	if config.PoolSize <= 0 {
+		config.PoolSize = DefaultPoolSize
+	}
+
+	if config.APIPort == "" {
+		config.APIPort = DefaultAPIPort
+	}
+
+	switch {
+	case config.MaxRetries < 0:
+		config.MaxRetries = 0
+	case config.MaxRetries == 0:
+		config.MaxRetries = DefaultMaxRetries
+	}
+
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = DefaultRetryBackoff
+	}
+
+	if config.MaxRetryBackoff <= 0 {
+		config.MaxRetryBackoff = DefaultMaxRetryBackoff
+	}
+
+	if config.HealthCheckInterval == 0 {
+		config.HealthCheckInterval = DefaultHealthCheckInterval
+	}
+
+	// a client is not limited unless asked to, it is meant for high volume submission
+	limits := ratelimit.NewRegistry(ratelimit.Config{Rate: -1})
+	if config.RateLimit != nil {
+		limits = ratelimit.NewRegistry(*config.RateLimit)
+	}
+
+	client := &Client{
+		security:        config.Security,
+		limits:          limits,
+		maxRetries:      config.MaxRetries,
+		retryBackoff:    config.RetryBackoff,
+		maxRetryBackoff: config.MaxRetryBackoff,
+		done:            make(chan struct{}),
+	}
+
+	for _, validator := range config.Validators {
+		ep, err := newEndpoint(validator, config)
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+
+		client.endpoints = append(client.endpoints, ep)
+	}
+
+	if config.HealthCheckInterval > 0 {
+		go client.healthCheck(config.HealthCheckInterval)
+	}
+
+	return client, nil
+}
+
+func newEndpoint(validator string, config Config) (*endpoint, error) {
+	txnAddr := withPort(validator, DefaultTXNPort)
+	apiAddr := withPort(hostOf(validator), config.APIPort)
+
+	ep := &endpoint{addr: txnAddr, apiAddr: apiAddr}
+	ep.healthy.Store(true)
+
+	for i := 0; i < config.PoolSize; i++ {
+		txnConn, err := helper.NewConnection(txnAddr, DefaultTXNPort, config.Security)
+		if err != nil {
+			ep.close()
+			return nil, fmt.Errorf("failed to create connection to %s: %v", txnAddr, err)
+		}
+		ep.txnConns = append(ep.txnConns, txnConn)
+
+		apiConn, err := helper.NewConnection(apiAddr, config.APIPort, config.Security)
+		if err != nil {
+			ep.close()
+			return nil, fmt.Errorf("failed to create connection to %s: %v", apiAddr, err)
+		}
+		ep.apiConns = append(ep.apiConns, apiConn)
+	}
+
+	return ep, nil
+}
+
+// Close closes every pooled connection. The client can not be used afterwards.
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		for _, ep := range c.endpoints {
+			if closeErr := ep.close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	})
+	return err
+}
+
+func (ep *endpoint) close() error {
+	var err error
+	for _, conn := range append(ep.txnConns, ep.apiConns...) {
+		if closeErr := conn.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Validators returns the TXNService address of every validator in the pool.
+func (c *Client) Validators() []string {
+	addrs := make([]string, 0, len(c.endpoints))
+	for _, ep := range c.endpoints {
+		addrs = append(addrs, ep.addr)
+	}
+	return addrs
+}
+
+// pick round robins over validators, and over the connections within that validator
+func (c *Client) pick() *endpoint {
+	return c.endpoints[(c.next.Add(1)-1)%uint64(len(c.endpoints))]
+}
+
+func (ep *endpoint) txnClient() pb.TXNServiceClient {
+	conn := ep.txnConns[(ep.next.Add(1)-1)%uint64(len(ep.txnConns))]
+	return helper.NewNetworkClient(conn)
+}
+
+func (ep *endpoint) apiClient() pb.APIServiceClient {
+	conn := ep.apiConns[(ep.next.Add(1)-1)%uint64(len(ep.apiConns))]
+	return helper.NewValidatorNetworkApiClient(conn)
+}
+
+type submitFunc func(ctx context.Context, client pb.TXNServiceClient) (*emptypb.Empty, error)
+
+func (c *Client) submit(ctx context.Context, txnName string, send submitFunc) (*emptypb.Empty, error) {
+	response, err := c.submitWithRetry(ctx, send)
+	if err != nil {
+		return nil, fmt.Errorf("%s transaction failed: %w", txnName, err)
+	}
+
+	return response, nil
+}
+
+// Security returns the transport security the client was created with, for use in nonce.NonceInfo and parts.PartsInfo.
+func (c *Client) Security() *helper.TransportSecurity {
+	return c.security
+}
+
+// RateLimits returns the per endpoint rate limits of the client, for use in nonce.NonceInfo and parts.PartsInfo.
+func (c *Client) RateLimits() *ratelimit.Registry {
+	return c.limits
+}
+
+// APIClient returns a pooled validator api client, for use as nonce.NonceInfo.ApiClient.
+func (c *Client) APIClient() pb.APIServiceClient {
+	return c.pickHealthy(nil).apiClient()
+}
+
+// Nonce requests the current nonce of a wallet from the validator APIService
+func (c *Client) Nonce(ctx context.Context, req *pb.NonceRequest) (*pb.NonceResponse, error) {
+	ep := c.pickHealthy(nil)
+
+	if _, err := c.limits.Wait(ctx, ep.apiAddr); err != nil {
+		return nil, err
+	}
+
+	response, err := ep.apiClient().Nonce(ctx, req)
+	if err != nil {
+		return nil, zeraerr.FromGRPC(err)
+	}
+
+	return response, nil
+}
+
+// GetNonce resolves nonces like nonce.GetNonceWithContext, but through the pooled validator connections
+// and with the client's transport security applied to indexer requests. concurrency bounds the lookups in flight at
+// once, the request rate is set by the client's rate limits.
+func (c *Client) GetNonce(ctx context.Context, info nonce.NonceInfo, concurrency int) ([]uint64, error) {
+	if info.Security == nil {
+		info.Security = c.security
+	}
+
+	if info.RateLimits == nil {
+		info.RateLimits = c.limits
+	}
+
+	if !info.UseIndexer && info.ApiClient == nil {
+		ep := c.pickHealthy(nil)
+		info.ApiClient = ep.apiClient()
+		info.ValidatorAddr = ep.apiAddr
+	}
+
+	return nonce.GetNonceWithContext(ctx, info, concurrency)
+}
+
+// GetParts resolves the parts per coin of a contract with the client's transport security applied.
+func (c *Client) GetParts(ctx context.Context, info parts.PartsInfo) (*big.Int, error) {
+	if info.Security == nil {
+		info.Security = c.security
+	}
+
+	if info.RateLimits == nil {
+		info.RateLimits = c.limits
+	}
+
+	return parts.GetPartsWithContext(ctx, info)
+}
+
+// WaitForConfirmation waits like track.WaitForConfirmation, through the pooled validator connections
+// and with the client's transport security and rate limits applied.
+func (c *Client) WaitForConfirmation(ctx context.Context, info track.TrackInfo, hash string) (*track.Receipt, error) {
+	if info.Security == nil {
+		info.Security = c.security
+	}
+
+	if info.RateLimits == nil {
+		info.RateLimits = c.limits
+	}
+
+	if !info.UseIndexer && info.ApiClient == nil {
+		ep := c.pickHealthy(nil)
+		info.ApiClient = ep.apiClient()
+		info.ValidatorAddr = ep.apiAddr
+	}
+
+	return track.WaitForConfirmation(ctx, info, hash)
+}
+
+func withPort(addr, port string) string {
+	if !strings.Contains(addr, ":") {
+		addr += ":" + port
+	}
+	return addr
+}
+
+func hostOf(addr string) string {
+	if i := strings.LastIndex(addr, ":"); i != -1 {
+		return addr[:i]
+	}
+	return addr
+}
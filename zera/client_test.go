@@ -0,0 +1,220 @@
+package zera_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/nonce"
+	"github.com/ZeraVision/zera-go-sdk/ratelimit"
+	"github.com/ZeraVision/zera-go-sdk/zera"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type countingValidator struct {
+	pb.UnimplementedTXNServiceServer
+	pb.UnimplementedAPIServiceServer
+	coins atomic.Int64
+}
+
+func (v *countingValidator) Coin(ctx context.Context, txn *pb.CoinTXN) (*emptypb.Empty, error) {
+	v.coins.Add(1)
+	return &emptypb.Empty{}, nil
+}
+
+func (v *countingValidator) Nonce(ctx context.Context, req *pb.NonceRequest) (*pb.NonceResponse, error) {
+	return &pb.NonceResponse{Nonce: 41}, nil
+}
+
+// startValidator serves the TXNService and APIService on two local ports, like a real validator
+func startValidator(t *testing.T) (*countingValidator, string, string) {
+	validator := &countingValidator{}
+
+	txnLis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	apiLis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	txnServer := grpc.NewServer()
+	pb.RegisterTXNServiceServer(txnServer, validator)
+	apiServer := grpc.NewServer()
+	pb.RegisterAPIServiceServer(apiServer, validator)
+
+	go txnServer.Serve(txnLis)
+	go apiServer.Serve(apiLis)
+
+	t.Cleanup(func() {
+		txnServer.Stop()
+		apiServer.Stop()
+	})
+
+	apiPort := strconv.Itoa(apiLis.Addr().(*net.TCPAddr).Port)
+
+	return validator, txnLis.Addr().String(), apiPort
+}
+
+func TestClientConcurrentSubmit(t *testing.T) {
+	validator, addr, apiPort := startValidator(t)
+
+	client, err := zera.NewClient(zera.Config{
+		Validators: []string{addr},
+		APIPort:    apiPort,
+		PoolSize:   2,
+		RateLimit:  &ratelimit.Config{Rate: -1},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.SubmitCoinTXN(context.Background(), &pb.CoinTXN{ContractId: "$ZRA+0000"}); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if validator.coins.Load() != 50 {
+		t.Errorf("Expected 50 coin transactions, got %d", validator.coins.Load())
+	}
+
+	req, err := nonce.MakeNonceRequest("8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := client.Nonce(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.GetNonce() != 41 {
+		t.Errorf("Expected nonce 41, got %d", response.GetNonce())
+	}
+}
+
+func TestClientRequiresValidator(t *testing.T) {
+	if _, err := zera.NewClient(zera.Config{}); err == nil {
+		t.Fatal("Expected an error for missing validators, got none")
+	}
+}
+
+type tokenCredentials struct{ token string }
+
+func (c tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.token}, nil
+}
+
+func (c tokenCredentials) RequireTransportSecurity() bool { return true }
+
+func TestClientTLSAndPerRPCCredentials(t *testing.T) {
+	// Borrow the self signed certificate (valid for 127.0.0.1) from an httptest TLS server
+	certSource := httptest.NewTLSServer(http.NotFoundHandler())
+	defer certSource.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(certSource.Certificate())
+
+	validator := &countingValidator{}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	authorize := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if len(md.Get("authorization")) != 1 || md.Get("authorization")[0] != "Bearer secret" {
+			return nil, status.Error(codes.Unauthenticated, "missing token")
+		}
+		return handler(ctx, req)
+	}
+
+	server := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: certSource.TLS.Certificates})),
+		grpc.UnaryInterceptor(authorize),
+	)
+	pb.RegisterTXNServiceServer(server, validator)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	client, err := zera.NewClient(zera.Config{
+		Validators: []string{lis.Addr().String()},
+		Security: &helper.TransportSecurity{
+			TLS:               true,
+			RootCAs:           pool,
+			PerRPCCredentials: tokenCredentials{token: "secret"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.SubmitCoinTXN(context.Background(), &pb.CoinTXN{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Without the CA the handshake must fail
+	untrusted, err := zera.NewClient(zera.Config{
+		Validators: []string{lis.Addr().String()},
+		Security:   &helper.TransportSecurity{TLS: true},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer untrusted.Close()
+
+	if _, err := untrusted.SubmitCoinTXN(context.Background(), &pb.CoinTXN{}); err == nil {
+		t.Fatal("Expected an error for untrusted certificate, got none")
+	}
+}
+
+func TestClientNotLimitedByDefault(t *testing.T) {
+	validator, addr, _ := startValidator(t)
+
+	client, err := zera.NewClient(zera.Config{Validators: []string{addr}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer client.Close()
+
+	start := time.Now()
+	for i := 0; i < 50; i++ {
+		if _, err := client.SubmitCoinTXN(context.Background(), &pb.CoinTXN{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected 50 submissions in under a second without a rate limit, took %s", elapsed)
+	}
+
+	if validator.coins.Load() != 50 {
+		t.Errorf("Expected 50 coin transactions, got %d", validator.coins.Load())
+	}
+}
@@ -0,0 +1,265 @@
+package zera
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	DefaultMaxRetries          = 2                      // retries after the first attempt
+	DefaultRetryBackoff        = 200 * time.Millisecond // doubled on every retry
+	DefaultMaxRetryBackoff     = 5 * time.Second        // the retry backoff stops doubling here
+	DefaultHealthCheckInterval = 10 * time.Second
+)
+
+// BroadcastResult reports the outcome of sending a transaction to a single validator.
+type BroadcastResult struct {
+	Validator string
+	Err       error // nil if the validator accepted the transaction
+}
+
+// pickHealthy returns the next endpoint not in exclude, preferring healthy ones. It walks c.endpoints from a single
+// round robin position so concurrent callers can not make it skip endpoints. If every endpoint is excluded any
+// endpoint is returned.
+func (c *Client) pickHealthy(exclude map[*endpoint]bool) *endpoint {
+	if targets := c.targets(1, exclude); len(targets) > 0 {
+		return targets[0]
+	}
+	return c.targets(1, nil)[0]
+}
+
+// targets returns up to n distinct endpoints not in exclude, healthy ones first, starting at the next round robin position
+func (c *Client) targets(n int, exclude map[*endpoint]bool) []*endpoint {
+	start := int((c.next.Add(1) - 1) % uint64(len(c.endpoints)))
+
+	targets := make([]*endpoint, 0, n)
+	for _, wantHealthy := range []bool{true, false} {
+		for i := range c.endpoints {
+			if len(targets) == n {
+				return targets
+			}
+
+			ep := c.endpoints[(start+i)%len(c.endpoints)]
+			if !exclude[ep] && ep.healthy.Load() == wantHealthy {
+				targets = append(targets, ep)
+			}
+		}
+	}
+	return targets
+}
+
+// submitWithRetry sends to a validator, moving on to the next validator with backoff on transient errors
+func (c *Client) submitWithRetry(ctx context.Context, send submitFunc) (*emptypb.Empty, error) {
+	tried := map[*endpoint]bool{}
+	backoff := min(c.retryBackoff, c.maxRetryBackoff)
+
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			// full jitter so concurrent senders don't retry in lock step
+			wait := time.Duration(rand.Int63n(int64(backoff)))
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, errors.Join(ctx.Err(), lastErr)
+			}
+			// doubled up to the cap, never past it (or past int64)
+			if backoff <= c.maxRetryBackoff/2 {
+				backoff *= 2
+			} else {
+				backoff = c.maxRetryBackoff
+			}
+		}
+
+		if len(tried) == len(c.endpoints) {
+			tried = map[*endpoint]bool{} // every validator has been tried, start over
+		}
+
+		ep := c.pickHealthy(tried)
+		tried[ep] = true
+
+		if _, err := c.limits.Wait(ctx, ep.addr); err != nil {
+			return nil, errors.Join(err, lastErr)
+		}
+
+		response, err := send(ctx, ep.txnClient())
+		if err == nil {
+			ep.healthy.Store(true)
+			return response, nil
+		}
+
+		err = zeraerr.FromGRPC(err)
+		lastErr = fmt.Errorf("%s: %w", ep.addr, err)
+
+		if !zeraerr.IsRetryable(err) || ctx.Err() != nil {
+			return nil, lastErr
+		}
+
+		ep.healthy.Store(false)
+	}
+
+	return nil, lastErr
+}
+
+// Broadcast sends the same signed transaction to n validators in parallel (all validators if n <= 0).
+// Every validator's result is returned, err is only set if no validator accepted the transaction.
+func (c *Client) Broadcast(ctx context.Context, txn proto.Message, n int) ([]BroadcastResult, error) {
+	txnName, send, err := submitterFor(txn)
+	if err != nil {
+		return nil, err
+	}
+
+	if n <= 0 || n > len(c.endpoints) {
+		n = len(c.endpoints)
+	}
+
+	// n distinct validators, healthy ones first
+	targets := c.targets(n, nil)
+
+	results := make([]BroadcastResult, len(targets))
+
+	var wg sync.WaitGroup
+	for i, ep := range targets {
+		wg.Add(1)
+		go func(i int, ep *endpoint) {
+			defer wg.Done()
+
+			if _, err := c.limits.Wait(ctx, ep.addr); err != nil {
+				results[i] = BroadcastResult{Validator: ep.addr, Err: err}
+				return
+			}
+
+			_, err := send(ctx, ep.txnClient())
+			err = zeraerr.FromGRPC(err)
+			if zeraerr.IsRetryable(err) {
+				ep.healthy.Store(false)
+			}
+
+			results[i] = BroadcastResult{Validator: ep.addr, Err: err}
+		}(i, ep)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, result := range results {
+		if result.Err == nil {
+			return results, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", result.Validator, result.Err))
+	}
+
+	return results, fmt.Errorf("%s transaction was not accepted by any validator: %w", txnName, errors.Join(errs...))
+}
+
+// Submit sends any supported transaction type, with the same failover as the typed Submit methods.
+func (c *Client) Submit(ctx context.Context, txn proto.Message) (*emptypb.Empty, error) {
+	txnName, send, err := submitterFor(txn)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.submit(ctx, txnName, send)
+}
+
+func submitterFor(txn proto.Message) (string, submitFunc, error) {
+	switch txn := txn.(type) {
+	case *pb.CoinTXN:
+		return "coin", func(ctx context.Context, client pb.TXNServiceClient) (*emptypb.Empty, error) {
+			return client.Coin(ctx, txn)
+		}, nil
+	case *pb.MintTXN:
+		return "mint", func(ctx context.Context, client pb.TXNServiceClient) (*emptypb.Empty, error) {
+			return client.Mint(ctx, txn)
+		}, nil
+	case *pb.ItemizedMintTXN:
+		return "item mint", func(ctx context.Context, client pb.TXNServiceClient) (*emptypb.Empty, error) {
+			return client.ItemMint(ctx, txn)
+		}, nil
+	case *pb.NFTTXN:
+		return "NFT transfer", func(ctx context.Context, client pb.TXNServiceClient) (*emptypb.Empty, error) {
+			return client.NFT(ctx, txn)
+		}, nil
+	case *pb.InstrumentContract:
+		return "token", func(ctx context.Context, client pb.TXNServiceClient) (*emptypb.Empty, error) {
+			return client.Contract(ctx, txn)
+		}, nil
+	case *pb.ContractUpdateTXN:
+		return "contract update", func(ctx context.Context, client pb.TXNServiceClient) (*emptypb.Empty, error) {
+			return client.ContractUpdate(ctx, txn)
+		}, nil
+	case *pb.AllowanceTXN:
+		return "allowance", func(ctx context.Context, client pb.TXNServiceClient) (*emptypb.Empty, error) {
+			return client.Allowance(ctx, txn)
+		}, nil
+	case *pb.ComplianceTXN:
+		return "compliance", func(ctx context.Context, client pb.TXNServiceClient) (*emptypb.Empty, error) {
+			return client.Compliance(ctx, txn)
+		}, nil
+	case *pb.GovernanceProposal:
+		return "proposal", func(ctx context.Context, client pb.TXNServiceClient) (*emptypb.Empty, error) {
+			return client.GovernProposal(ctx, txn)
+		}, nil
+	case *pb.GovernanceVote:
+		return "vote", func(ctx context.Context, client pb.TXNServiceClient) (*emptypb.Empty, error) {
+			return client.GovernVote(ctx, txn)
+		}, nil
+	case *pb.ExpenseRatioTXN:
+		return "expense ratio", func(ctx context.Context, client pb.TXNServiceClient) (*emptypb.Empty, error) {
+			return client.ExpenseRatio(ctx, txn)
+		}, nil
+	case *pb.AuthorizedCurrencyEquiv:
+		return "ACE", func(ctx context.Context, client pb.TXNServiceClient) (*emptypb.Empty, error) {
+			return client.AuthCurrencyEquiv(ctx, txn)
+		}, nil
+	case *pb.SelfCurrencyEquiv:
+		return "currency equivalent", func(ctx context.Context, client pb.TXNServiceClient) (*emptypb.Empty, error) {
+			return client.CurrencyEquiv(ctx, txn)
+		}, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported transaction type %T", txn)
+	}
+}
+
+// healthCheck periodically inspects each validator's connections, marking validators whose
+// connections are all failing as unhealthy and waking idle connections so they reconnect.
+func (c *Client) healthCheck(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			for _, ep := range c.endpoints {
+				ep.checkHealth()
+			}
+		}
+	}
+}
+
+func (ep *endpoint) checkHealth() {
+	healthy := false
+
+	for _, conn := range ep.txnConns {
+		switch conn.GetState() {
+		case connectivity.Idle:
+			conn.Connect()
+			healthy = true
+		case connectivity.Ready, connectivity.Connecting:
+			healthy = true
+		}
+	}
+
+	ep.healthy.Store(healthy)
+}
@@ -0,0 +1,184 @@
+package zera_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/ratelimit"
+	"github.com/ZeraVision/zera-go-sdk/zera"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
+)
+
+// deadAddress returns a local address with nothing listening on it
+func deadAddress(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+	return addr
+}
+
+func TestClientFailover(t *testing.T) {
+	validator, addr, _ := startValidator(t)
+
+	client, err := zera.NewClient(zera.Config{
+		Validators:   []string{deadAddress(t), addr},
+		RetryBackoff: 10 * time.Millisecond,
+		RateLimit:    &ratelimit.Config{Rate: -1},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer client.Close()
+
+	for i := 0; i < 10; i++ {
+		if _, err := client.SubmitCoinTXN(context.Background(), &pb.CoinTXN{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if validator.coins.Load() != 10 {
+		t.Errorf("Expected 10 coin transactions on the live validator, got %d", validator.coins.Load())
+	}
+}
+
+func TestClientNoRetry(t *testing.T) {
+	client, err := zera.NewClient(zera.Config{
+		Validators: []string{deadAddress(t)},
+		MaxRetries: -1,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.SubmitCoinTXN(context.Background(), &pb.CoinTXN{})
+	if err == nil {
+		t.Fatal("Expected an error for unreachable validator, got none")
+	}
+
+	if !errors.Is(err, zeraerr.ErrTransient) {
+		t.Errorf("Expected a transient error, got %v", err)
+	}
+}
+
+func TestClientManyRetries(t *testing.T) {
+	// enough retries to overflow the backoff if it kept doubling
+	client, err := zera.NewClient(zera.Config{
+		Validators:      []string{deadAddress(t)},
+		MaxRetries:      100,
+		RetryBackoff:    time.Millisecond,
+		MaxRetryBackoff: 2 * time.Millisecond,
+		RateLimit:       &ratelimit.Config{Rate: -1},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.SubmitCoinTXN(context.Background(), &pb.CoinTXN{})
+	if !errors.Is(err, zeraerr.ErrTransient) {
+		t.Errorf("Expected a transient error, got %v", err)
+	}
+}
+
+func TestClientBroadcast(t *testing.T) {
+	validator1, addr1, _ := startValidator(t)
+	validator2, addr2, _ := startValidator(t)
+	dead := deadAddress(t)
+
+	client, err := zera.NewClient(zera.Config{
+		Validators: []string{addr1, addr2, dead},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer client.Close()
+
+	results, err := client.Broadcast(context.Background(), &pb.CoinTXN{}, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+
+	for _, result := range results {
+		if result.Validator == dead && result.Err == nil {
+			t.Errorf("Expected unreachable validator %s to fail", dead)
+		}
+		if result.Validator != dead && result.Err != nil {
+			t.Errorf("Expected validator %s to accept, got %v", result.Validator, result.Err)
+		}
+	}
+
+	if validator1.coins.Load() != 1 || validator2.coins.Load() != 1 {
+		t.Errorf("Expected each live validator to receive the transaction once, got %d and %d", validator1.coins.Load(), validator2.coins.Load())
+	}
+
+	if _, err := client.Broadcast(context.Background(), &pb.NonceRequest{}, 0); err == nil {
+		t.Fatal("Expected an error for unsupported transaction type, got none")
+	}
+}
+
+func TestClientConcurrentBroadcast(t *testing.T) {
+	var validators []*countingValidator
+	var addrs []string
+	for i := 0; i < 3; i++ {
+		validator, addr, _ := startValidator(t)
+		validators = append(validators, validator)
+		addrs = append(addrs, addr)
+	}
+
+	client, err := zera.NewClient(zera.Config{Validators: addrs, RateLimit: &ratelimit.Config{Rate: -1}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer client.Close()
+
+	const broadcasts = 50
+
+	var wg sync.WaitGroup
+	errs := make(chan error, broadcasts)
+	for i := 0; i < broadcasts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			results, err := client.Broadcast(context.Background(), &pb.CoinTXN{}, 0)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			seen := map[string]bool{}
+			for _, result := range results {
+				if seen[result.Validator] {
+					errs <- errors.New("validator " + result.Validator + " was sent the transaction twice")
+					return
+				}
+				seen[result.Validator] = true
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i, validator := range validators {
+		if validator.coins.Load() != broadcasts {
+			t.Errorf("Expected validator %d to receive %d transactions, got %d", i, broadcasts, validator.coins.Load())
+		}
+	}
+}
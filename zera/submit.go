@@ -0,0 +1,73 @@
+package zera
+
+import (
+	"context"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// SubmitCoinTXN submits a CoinTXN (see transfer.CreateCoinTxn)
+func (c *Client) SubmitCoinTXN(ctx context.Context, txn *pb.CoinTXN) (*emptypb.Empty, error) {
+	return c.Submit(ctx, txn)
+}
+
+// SubmitMintTXN submits a MintTXN (see mint.CreateMintTxn)
+func (c *Client) SubmitMintTXN(ctx context.Context, txn *pb.MintTXN) (*emptypb.Empty, error) {
+	return c.Submit(ctx, txn)
+}
+
+// SubmitItemMintTXN submits an ItemizedMintTXN (see itemmint.CreateItemMintTxn)
+func (c *Client) SubmitItemMintTXN(ctx context.Context, txn *pb.ItemizedMintTXN) (*emptypb.Empty, error) {
+	return c.Submit(ctx, txn)
+}
+
+// SubmitNftTransferTXN submits an NFTTXN (see nfttransfer.CreateNftTransfer)
+func (c *Client) SubmitNftTransferTXN(ctx context.Context, txn *pb.NFTTXN) (*emptypb.Empty, error) {
+	return c.Submit(ctx, txn)
+}
+
+// SubmitInstrumentContract submits an InstrumentContract (see contract.CreateContractTXN)
+func (c *Client) SubmitInstrumentContract(ctx context.Context, txn *pb.InstrumentContract) (*emptypb.Empty, error) {
+	return c.Submit(ctx, txn)
+}
+
+// SubmitContractUpdate submits a ContractUpdateTXN (see contract.UpdateContractTXN)
+func (c *Client) SubmitContractUpdate(ctx context.Context, txn *pb.ContractUpdateTXN) (*emptypb.Empty, error) {
+	return c.Submit(ctx, txn)
+}
+
+// SubmitAllowanceTXN submits an AllowanceTXN (see allowance.CreateAllowanceTxn)
+func (c *Client) SubmitAllowanceTXN(ctx context.Context, txn *pb.AllowanceTXN) (*emptypb.Empty, error) {
+	return c.Submit(ctx, txn)
+}
+
+// SubmitComplianceTXN submits a ComplianceTXN (see compliance.CreateComplianceTxn)
+func (c *Client) SubmitComplianceTXN(ctx context.Context, txn *pb.ComplianceTXN) (*emptypb.Empty, error) {
+	return c.Submit(ctx, txn)
+}
+
+// SubmitProposal submits a GovernanceProposal (see governance.CreateProposalTxn)
+func (c *Client) SubmitProposal(ctx context.Context, txn *pb.GovernanceProposal) (*emptypb.Empty, error) {
+	return c.Submit(ctx, txn)
+}
+
+// SubmitVoteTXN submits a GovernanceVote (see governance.CreateVoteTxn)
+func (c *Client) SubmitVoteTXN(ctx context.Context, txn *pb.GovernanceVote) (*emptypb.Empty, error) {
+	return c.Submit(ctx, txn)
+}
+
+// SubmitExpenseRatioTXN submits an ExpenseRatioTXN (see expenseratio.ExpenseRatioTxn)
+func (c *Client) SubmitExpenseRatioTXN(ctx context.Context, txn *pb.ExpenseRatioTXN) (*emptypb.Empty, error) {
+	return c.Submit(ctx, txn)
+}
+
+// SubmitAceTXN submits an AuthorizedCurrencyEquiv (see currencyequivalent.CreateAceTxn)
+func (c *Client) SubmitAceTXN(ctx context.Context, txn *pb.AuthorizedCurrencyEquiv) (*emptypb.Empty, error) {
+	return c.Submit(ctx, txn)
+}
+
+// SubmitSelfCurrencyEquivalentTXN submits a SelfCurrencyEquiv (see currencyequivalent.CreateSelfCurrencyEquivalentTxn)
+func (c *Client) SubmitSelfCurrencyEquivalentTXN(ctx context.Context, txn *pb.SelfCurrencyEquiv) (*emptypb.Empty, error) {
+	return c.Submit(ctx, txn)
+}
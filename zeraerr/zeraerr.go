@@ -0,0 +1,216 @@
+package zeraerr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// kind is a sentinel that can itself belong to a broader kind (ie ErrRateLimited is ErrTransient)
+type kind struct {
+	msg    string
+	parent error
+}
+
+func (k *kind) Error() string { return k.msg }
+func (k *kind) Unwrap() error { return k.parent }
+
+// Sentinels for use with errors.Is. Every error built by FromGRPC / FromIndexer matches exactly one of them
+// (and its parent kind).
+var (
+	ErrTransient           error = &kind{msg: "transient failure"}                         // network / overload, safe to retry
+	ErrRateLimited         error = &kind{msg: "rate limited", parent: ErrTransient}        // retry after backing off
+	ErrNotFound            error = &kind{msg: "not found"}                                 // requested object does not exist
+	ErrUnknownWallet       error = &kind{msg: "unknown wallet", parent: ErrNotFound}       // wallet has never been used (nonce 0)
+	ErrUnknownContract     error = &kind{msg: "unknown contract", parent: ErrNotFound}     // contract id does not exist
+	ErrRejected            error = &kind{msg: "transaction rejected"}                      // the network refused the transaction
+	ErrInsufficientBalance error = &kind{msg: "insufficient balance", parent: ErrRejected} // not enough funds for amount + fees
+	ErrBadNonce            error = &kind{msg: "bad nonce", parent: ErrRejected}            // resync the nonce and rebuild
+	ErrUnauthorizedKey     error = &kind{msg: "unauthorized key", parent: ErrRejected}     // key / signature not allowed for this action
+	ErrUnauthenticated     error = &kind{msg: "unauthenticated"}                           // missing or invalid api key / credentials
+	ErrUnknown             error = &kind{msg: "unknown error"}                             // could not be classified
+)
+
+// Error is a classified failure from a validator or the indexer.
+type Error struct {
+	Kind       error         // one of the Err* sentinels
+	Code       codes.Code    // gRPC status code, codes.Unknown for indexer errors
+	HTTPStatus int           // indexer http status, 0 for gRPC errors
+	Message    string        // message from the validator / indexer
+	RetryAfter time.Duration // server provided backoff hint, if any
+	Err        error         // the original error
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return e.Kind.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Kind, e.Message)
+}
+
+// Unwrap exposes both the kind and the original error to errors.Is / errors.As
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// GRPCStatus keeps status.Code / status.FromError working on classified errors
+func (e *Error) GRPCStatus() *status.Status {
+	if s, ok := status.FromError(e.Err); ok {
+		return s
+	}
+	return status.New(e.Code, e.Message)
+}
+
+// IsRetryable reports whether the operation may succeed if retried unchanged (possibly on another validator).
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrTransient)
+}
+
+// reasons maps google.rpc.ErrorInfo reasons sent by validators to kinds
+var reasons = map[string]error{
+	"INSUFFICIENT_BALANCE": ErrInsufficientBalance,
+	"BAD_NONCE":            ErrBadNonce,
+	"INVALID_NONCE":        ErrBadNonce,
+	"UNKNOWN_CONTRACT":     ErrUnknownContract,
+	"CONTRACT_NOT_FOUND":   ErrUnknownContract,
+	"UNKNOWN_WALLET":       ErrUnknownWallet,
+	"WALLET_NOT_FOUND":     ErrUnknownWallet,
+	"UNAUTHORIZED_KEY":     ErrUnauthorizedKey,
+	"INVALID_SIGNATURE":    ErrUnauthorizedKey,
+	"RATE_LIMITED":         ErrRateLimited,
+}
+
+// FromGRPC classifies an error returned by a validator gRPC call. nil stays nil, errors that are already
+// classified are returned as is.
+func FromGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return &Error{Kind: ErrUnknown, Code: codes.Unknown, Message: err.Error(), Err: err}
+	}
+
+	e := &Error{Code: s.Code(), Message: s.Message(), Err: err}
+
+	// Step 1: structured details take priority
+	for _, detail := range s.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if k, ok := reasons[strings.ToUpper(detail.GetReason())]; ok && e.Kind == nil {
+				e.Kind = k
+			}
+		case *errdetails.RetryInfo:
+			e.RetryAfter = detail.GetRetryDelay().AsDuration()
+		}
+	}
+
+	if e.Kind != nil {
+		return e
+	}
+
+	// Step 2: status codes that are unambiguous
+	switch s.Code() {
+	case codes.Unavailable, codes.Aborted, codes.DeadlineExceeded:
+		e.Kind = ErrTransient
+		return e
+	case codes.ResourceExhausted:
+		e.Kind = ErrRateLimited
+		return e
+	case codes.Unauthenticated:
+		e.Kind = ErrUnauthenticated
+		return e
+	}
+
+	// Step 3: validators mostly reject with a plain message
+	e.Kind = fromMessage(s.Message())
+	if e.Kind == nil {
+		switch s.Code() {
+		case codes.NotFound:
+			e.Kind = ErrNotFound
+		case codes.PermissionDenied:
+			e.Kind = ErrUnauthorizedKey
+		case codes.InvalidArgument, codes.FailedPrecondition:
+			e.Kind = ErrRejected
+		default:
+			e.Kind = ErrUnknown
+		}
+	}
+
+	return e
+}
+
+// FromIndexer classifies a failed indexer response. notFound is the kind to use when the body reports that
+// the requested object does not exist (ie ErrUnknownContract for a contract lookup), nil for ErrNotFound.
+func FromIndexer(statusCode int, body []byte, notFound error) error {
+	message := strings.TrimSpace(string(body))
+
+	e := &Error{Code: codes.Unknown, HTTPStatus: statusCode, Message: message}
+
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		e.Kind = ErrRateLimited
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		e.Kind = ErrUnauthenticated
+	case statusCode >= 500:
+		e.Kind = ErrTransient
+	default:
+		e.Kind = fromMessage(message)
+	}
+
+	if e.Kind == nil {
+		if statusCode == http.StatusNotFound {
+			e.Kind = ErrNotFound
+		} else {
+			e.Kind = ErrUnknown
+		}
+	}
+
+	if errors.Is(e.Kind, ErrNotFound) && notFound != nil {
+		e.Kind = notFound
+	}
+
+	return e
+}
+
+// fromMessage recognises the error messages of validators and the indexer
+func fromMessage(message string) error {
+	message = strings.ToLower(message)
+
+	switch {
+	case strings.Contains(message, "insufficient"):
+		return ErrInsufficientBalance
+	case strings.Contains(message, "does not exist"), strings.Contains(message, "not found"):
+		// before nonce, the nonce of a new wallet "does not exist"
+		switch {
+		case strings.Contains(message, "contract"), strings.Contains(message, "symbol"):
+			return ErrUnknownContract
+		case strings.Contains(message, "wallet"), strings.Contains(message, "address"):
+			return ErrUnknownWallet
+		}
+		return ErrNotFound
+	case strings.Contains(message, "nonce"):
+		return ErrBadNonce
+	case strings.Contains(message, "signature"), strings.Contains(message, "unauthorized"), strings.Contains(message, "not authorized"):
+		return ErrUnauthorizedKey
+	case strings.Contains(message, "rate limit"), strings.Contains(message, "too many requests"):
+		return ErrRateLimited
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package zeraerr_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestFromGRPC(t *testing.T) {
+	tests := []struct {
+		err       error
+		kind      error
+		retryable bool
+	}{
+		{status.Error(codes.Unavailable, "connection refused"), zeraerr.ErrTransient, true},
+		{status.Error(codes.ResourceExhausted, "slow down"), zeraerr.ErrRateLimited, true},
+		{status.Error(codes.Unauthenticated, "missing token"), zeraerr.ErrUnauthenticated, false},
+		{status.Error(codes.Unknown, "Wallet does not exist"), zeraerr.ErrUnknownWallet, false},
+		{status.Error(codes.Unknown, "contract $ABC+0000 does not exist"), zeraerr.ErrUnknownContract, false},
+		{status.Error(codes.NotFound, "nonce for wallet 8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR does not exist"), zeraerr.ErrUnknownWallet, false},
+		{status.Error(codes.InvalidArgument, "insufficient funds for fee"), zeraerr.ErrInsufficientBalance, false},
+		{status.Error(codes.InvalidArgument, "invalid nonce"), zeraerr.ErrBadNonce, false},
+		{status.Error(codes.InvalidArgument, "invalid signature"), zeraerr.ErrUnauthorizedKey, false},
+		{status.Error(codes.PermissionDenied, "restricted"), zeraerr.ErrUnauthorizedKey, false},
+		{status.Error(codes.FailedPrecondition, "txn malformed"), zeraerr.ErrRejected, false},
+		{errors.New("plain error"), zeraerr.ErrUnknown, false},
+	}
+
+	for _, test := range tests {
+		err := zeraerr.FromGRPC(test.err)
+
+		if !errors.Is(err, test.kind) {
+			t.Errorf("Expected %q to be %q, got %v", test.err, test.kind, err)
+		}
+
+		if !errors.Is(err, test.err) {
+			t.Errorf("Expected %q to wrap the original error", test.err)
+		}
+
+		if zeraerr.IsRetryable(err) != test.retryable {
+			t.Errorf("Expected retryable %v for %q", test.retryable, test.err)
+		}
+	}
+
+	// Parent kinds
+	if !errors.Is(zeraerr.FromGRPC(status.Error(codes.Unknown, "wallet does not exist")), zeraerr.ErrNotFound) {
+		t.Error("Expected unknown wallet to be not found")
+	}
+	if !errors.Is(zeraerr.FromGRPC(status.Error(codes.Unknown, "bad nonce")), zeraerr.ErrRejected) {
+		t.Error("Expected bad nonce to be a rejection")
+	}
+
+	// Wrapped errors are still classified, status codes are kept
+	err := zeraerr.FromGRPC(fmt.Errorf("coin: %w", status.Error(codes.Unavailable, "down")))
+	if !errors.Is(err, zeraerr.ErrTransient) {
+		t.Errorf("Expected wrapped error to be transient, got %v", err)
+	}
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected status code to be kept, got %s", status.Code(err))
+	}
+
+	if zeraerr.FromGRPC(nil) != nil {
+		t.Error("Expected nil for nil error")
+	}
+}
+
+func TestFromGRPCDetails(t *testing.T) {
+	s, err := status.New(codes.FailedPrecondition, "rejected").WithDetails(
+		&errdetails.ErrorInfo{Reason: "INSUFFICIENT_BALANCE"},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(2 * time.Second)},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	classified := zeraerr.FromGRPC(s.Err())
+	if !errors.Is(classified, zeraerr.ErrInsufficientBalance) {
+		t.Errorf("Expected insufficient balance, got %v", classified)
+	}
+
+	var zeraErr *zeraerr.Error
+	if !errors.As(classified, &zeraErr) {
+		t.Fatalf("Expected a *zeraerr.Error, got %T", classified)
+	}
+
+	if zeraErr.RetryAfter != 2*time.Second {
+		t.Errorf("Expected retry after 2s, got %s", zeraErr.RetryAfter)
+	}
+}
+
+func TestFromIndexer(t *testing.T) {
+	tests := []struct {
+		status   int
+		body     string
+		notFound error
+		kind     error
+	}{
+		{http.StatusTooManyRequests, "", nil, zeraerr.ErrRateLimited},
+		{http.StatusUnauthorized, "invalid api key", nil, zeraerr.ErrUnauthenticated},
+		{http.StatusBadGateway, "", nil, zeraerr.ErrTransient},
+		{http.StatusOK, "Contract does not exist", zeraerr.ErrUnknownContract, zeraerr.ErrUnknownContract},
+		{http.StatusNotFound, "", zeraerr.ErrUnknownWallet, zeraerr.ErrUnknownWallet},
+		{http.StatusBadRequest, "???", nil, zeraerr.ErrUnknown},
+	}
+
+	for _, test := range tests {
+		err := zeraerr.FromIndexer(test.status, []byte(test.body), test.notFound)
+		if !errors.Is(err, test.kind) {
+			t.Errorf("Expected %d %q to be %q, got %v", test.status, test.body, test.kind, err)
+		}
+	}
+}
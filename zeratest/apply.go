@@ -0,0 +1,530 @@
+package zeratest
+
+import (
+	"math/big"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/wallet"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type balanceKey struct {
+	contract string
+	address  string
+}
+
+// change is a transaction being applied to the ledger. Checks see the pending balances, nonces and allowance use of
+// the transaction, nothing reaches the ledger until commit.
+type change struct {
+	ledger     *Ledger
+	at         time.Time                 // transaction timestamp
+	balances   map[balanceKey]*big.Int   // pending balance deltas
+	nonces     map[string]uint64         // pending nonces by wallet address bytes
+	allowances map[allowanceKey]*big.Int // pending allowance use
+	effects    []func()                  // other state changes, in order
+}
+
+// commit writes the change to the ledger. Callers hold l.mu.
+func (c *change) commit() {
+	l := c.ledger
+
+	for key, delta := range c.balances {
+		l.contracts[key.contract].add(key.address, delta)
+	}
+
+	for address, nonce := range c.nonces {
+		l.nonces[address] = nonce
+	}
+
+	for _, effect := range c.effects {
+		effect()
+	}
+}
+
+// coin applies a CoinTXN
+func (c *change) coin(txn *pb.CoinTXN) error {
+	l := c.ledger
+	auth := txn.GetAuth()
+
+	contract, err := l.contract(txn.GetContractId())
+	if err != nil {
+		return err
+	}
+
+	if contract.parts == nil {
+		return status.Errorf(codes.FailedPrecondition, "%s is not a token", txn.GetContractId())
+	}
+
+	// Step 1: Nonces of the signers and of the wallets giving an allowance
+	if len(auth.GetPublicKey()) == 0 || len(auth.GetNonce()) != len(auth.GetPublicKey()) {
+		return status.Error(codes.InvalidArgument, "every transfer key needs exactly one nonce")
+	}
+
+	if len(auth.GetAllowanceNonce()) != len(auth.GetAllowanceAddress()) {
+		return status.Error(codes.InvalidArgument, "every allowance address needs exactly one nonce")
+	}
+
+	var signers [][]byte
+	for i, key := range auth.GetPublicKey() {
+		address, err := keyAddress(key)
+		if err != nil {
+			return err
+		}
+
+		if err := c.useNonce(address, auth.GetNonce()[i]); err != nil {
+			return err
+		}
+
+		signers = append(signers, address)
+	}
+
+	for i, owner := range auth.GetAllowanceAddress() {
+		if err := c.useNonce(owner, auth.GetAllowanceNonce()[i]); err != nil {
+			return err
+		}
+	}
+
+	// Step 2: Split the base fee between the signers by their fee percent
+	fee, err := parseParts(txn.GetBase().GetFeeAmount(), "fee amount")
+	if err != nil {
+		return err
+	}
+
+	feePercent := big.NewInt(0)
+	for _, input := range txn.GetInputTransfers() {
+		feePercent.Add(feePercent, big.NewInt(int64(input.GetFeePercent())))
+	}
+
+	if fee.Sign() > 0 && feePercent.Cmp(feePercentScale) != 0 {
+		return status.Errorf(codes.InvalidArgument, "input fee percents add up to %s, expected %s (100%%)", feePercent, feePercentScale)
+	}
+
+	// Step 3: Inputs, from the allowance address of the input if there is one, otherwise from its signer
+	if len(txn.GetInputTransfers()) == 0 {
+		return status.Error(codes.InvalidArgument, "transaction has no inputs")
+	}
+
+	totalInput := big.NewInt(0)
+	charged := big.NewInt(0)
+	var wallets [][]byte
+
+	for i, input := range txn.GetInputTransfers() {
+		amount, err := parseParts(input.GetAmount(), "input amount")
+		if err != nil {
+			return err
+		}
+
+		signer := signers[min(i, len(signers)-1)]
+		source := signer
+
+		if i < len(auth.GetAllowanceAddress()) {
+			source = auth.GetAllowanceAddress()[i]
+			if err := c.spendAllowance(contract, source, signer, amount); err != nil {
+				return err
+			}
+		}
+
+		if err := c.debit(txn.GetContractId(), source, amount); err != nil {
+			return err
+		}
+
+		share := new(big.Int).Mul(fee, big.NewInt(int64(input.GetFeePercent())))
+		share.Quo(share, feePercentScale)
+		if i == len(txn.GetInputTransfers())-1 {
+			share.Sub(fee, charged) // rounding goes to the last input
+		}
+
+		if err := c.charge(txn.GetBase().GetFeeId(), signer, share); err != nil {
+			return err
+		}
+
+		totalInput.Add(totalInput, amount)
+		charged.Add(charged, share)
+		wallets = append(wallets, source)
+	}
+
+	// Step 4: Outputs
+	totalOutput := big.NewInt(0)
+	for _, output := range txn.GetOutputTransfers() {
+		amount, err := parseParts(output.GetAmount(), "output amount")
+		if err != nil {
+			return err
+		}
+
+		if len(output.GetWalletAddress()) == 0 {
+			return status.Error(codes.InvalidArgument, "output wallet address is required")
+		}
+
+		c.credit(txn.GetContractId(), output.GetWalletAddress(), amount)
+		totalOutput.Add(totalOutput, amount)
+		wallets = append(wallets, output.GetWalletAddress())
+	}
+
+	if totalInput.Cmp(totalOutput) != 0 {
+		return status.Errorf(codes.InvalidArgument, "inputs of %s parts do not equal outputs of %s parts", totalInput, totalOutput)
+	}
+
+	// Step 5: KYC contracts only move between compliant wallets
+	for _, address := range wallets {
+		if err := l.compliant(contract, address, c.at); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// signed applies every transaction type with a single signer (all except CoinTXN)
+func (c *change) signed(txn signedTxn) error {
+	l := c.ledger
+
+	// Step 1: Nonce and base fee of the signer
+	signer, err := keyAddress(txn.GetBase().GetPublicKey())
+	if err != nil {
+		return err
+	}
+
+	if err := c.useNonce(signer, txn.GetBase().GetNonce()); err != nil {
+		return err
+	}
+
+	fee, err := parseParts(txn.GetBase().GetFeeAmount(), "fee amount")
+	if err != nil {
+		return err
+	}
+
+	if err := c.charge(txn.GetBase().GetFeeId(), signer, fee); err != nil {
+		return err
+	}
+
+	// Step 2: What the transaction does
+	switch txn := txn.(type) {
+	case *pb.InstrumentContract:
+		if _, ok := l.contracts[txn.GetContractId()]; ok {
+			return status.Errorf(codes.AlreadyExists, "contract %s already exists", txn.GetContractId())
+		}
+
+		contract, err := newContract(txn)
+		if err != nil {
+			return err
+		}
+
+		c.effects = append(c.effects, func() { l.contracts[txn.GetContractId()] = contract })
+
+	case *pb.MintTXN:
+		contract, err := l.contract(txn.GetContractId())
+		if err != nil {
+			return err
+		}
+
+		if contract.parts == nil {
+			return status.Errorf(codes.FailedPrecondition, "%s is not a token", txn.GetContractId())
+		}
+
+		if err := l.authorize(contract, signer, "mint", (*pb.RestrictedKey).GetMint); err != nil {
+			return err
+		}
+
+		amount, err := parseParts(txn.GetAmount(), "amount")
+		if err != nil {
+			return err
+		}
+
+		if err := c.mint(contract, txn.GetRecipientAddress(), amount); err != nil {
+			return err
+		}
+
+		c.credit(txn.GetContractId(), txn.GetRecipientAddress(), amount)
+
+	case *pb.ItemizedMintTXN:
+		contract, err := l.contract(txn.GetContractId())
+		if err != nil {
+			return err
+		}
+
+		if contract.parts != nil {
+			return status.Errorf(codes.FailedPrecondition, "%s is not an nft / sbt contract", txn.GetContractId())
+		}
+
+		if err := l.authorize(contract, signer, "mint", (*pb.RestrictedKey).GetMint); err != nil {
+			return err
+		}
+
+		if _, ok := contract.items[txn.GetItemId()]; ok {
+			return status.Errorf(codes.AlreadyExists, "item %s of %s already exists", txn.GetItemId(), txn.GetContractId())
+		}
+
+		if err := c.mint(contract, txn.GetRecipientAddress(), big.NewInt(1)); err != nil {
+			return err
+		}
+
+		c.effects = append(c.effects, func() { contract.items[txn.GetItemId()] = string(txn.GetRecipientAddress()) })
+
+	case *pb.NFTTXN:
+		contract, err := l.contract(txn.GetContractId())
+		if err != nil {
+			return err
+		}
+
+		if contract.definition.GetType() == pb.CONTRACT_TYPE_SBT {
+			return status.Errorf(codes.FailedPrecondition, "items of %s are soulbound", txn.GetContractId())
+		}
+
+		holder, ok := contract.items[txn.GetItemId()]
+		if !ok {
+			return status.Errorf(codes.NotFound, "item %s of %s does not exist", txn.GetItemId(), txn.GetContractId())
+		}
+
+		if holder != string(signer) {
+			return status.Errorf(codes.PermissionDenied, "%s is not authorized to transfer item %s, it is held by %s", displayAddress(signer), txn.GetItemId(), displayAddress([]byte(holder)))
+		}
+
+		if err := c.receive(contract, txn.GetRecipientAddress()); err != nil {
+			return err
+		}
+
+		c.effects = append(c.effects, func() { contract.items[txn.GetItemId()] = string(txn.GetRecipientAddress()) })
+
+	case *pb.AllowanceTXN:
+		return c.allowance(txn, signer)
+
+	case *pb.ComplianceTXN:
+		contract, err := l.contract(txn.GetContractId())
+		if err != nil {
+			return err
+		}
+
+		if err := l.authorize(contract, signer, "assign compliance for", (*pb.RestrictedKey).GetCompliance); err != nil {
+			return err
+		}
+
+		for _, assign := range txn.GetCompliance() {
+			if len(assign.GetRecipientAddress()) == 0 {
+				return status.Error(codes.InvalidArgument, "compliance recipient address is required")
+			}
+
+			var expiry time.Time
+			if assign.GetExpiry() != nil {
+				expiry = assign.GetExpiry().AsTime()
+			}
+
+			c.effects = append(c.effects, func() {
+				address := string(assign.GetRecipientAddress())
+
+				if !assign.GetAssignRevoke() {
+					delete(contract.compliance[address], assign.GetComplianceLevel())
+					return
+				}
+
+				if contract.compliance[address] == nil {
+					contract.compliance[address] = make(map[uint32]time.Time)
+				}
+				contract.compliance[address][assign.GetComplianceLevel()] = expiry
+			})
+		}
+	}
+
+	return nil
+}
+
+// allowance applies an AllowanceTXN, the signer is the wallet giving the allowance
+func (c *change) allowance(txn *pb.AllowanceTXN, owner []byte) error {
+	l := c.ledger
+
+	if _, err := l.contract(txn.GetContractId()); err != nil {
+		return err
+	}
+
+	if len(txn.GetWalletAddress()) == 0 {
+		return status.Error(codes.InvalidArgument, "allowance wallet address is required")
+	}
+
+	key := allowanceKey{contract: txn.GetContractId(), owner: string(owner), spender: string(txn.GetWalletAddress())}
+
+	if !txn.GetAuthorize() {
+		if _, ok := l.allowances[key]; !ok {
+			return status.Errorf(codes.FailedPrecondition, "%s has no allowance from %s to revoke", displayAddress(txn.GetWalletAddress()), displayAddress(owner))
+		}
+
+		c.effects = append(c.effects, func() { delete(l.allowances, key) })
+		return nil
+	}
+
+	if (txn.AllowedAmount == nil) == (txn.AllowedCurrencyEquivalent == nil) {
+		return status.Error(codes.InvalidArgument, "exactly one of allowed amount or allowed currency equivalent is required")
+	}
+
+	if txn.PeriodMonths != nil && txn.PeriodSeconds != nil {
+		return status.Error(codes.InvalidArgument, "at most one of period months or period seconds is allowed")
+	}
+
+	allowed := &allowance{
+		periodSeconds: txn.GetPeriodSeconds(),
+		periodMonths:  txn.GetPeriodMonths(),
+		start:         c.at,
+		used:          big.NewInt(0),
+	}
+
+	if txn.GetStartTime() != nil {
+		allowed.start = txn.GetStartTime().AsTime()
+	}
+
+	if txn.AllowedAmount != nil {
+		amount, err := parseParts(txn.GetAllowedAmount(), "allowed amount")
+		if err != nil {
+			return err
+		}
+		allowed.amount = amount
+	}
+
+	c.effects = append(c.effects, func() { l.allowances[key] = allowed })
+	return nil
+}
+
+// spendAllowance takes amount from the allowance owner gave spender
+func (c *change) spendAllowance(contract *ledgerContract, owner, spender []byte, amount *big.Int) error {
+	key := allowanceKey{contract: contract.definition.GetContractId(), owner: string(owner), spender: string(spender)}
+
+	allowed, ok := c.ledger.allowances[key]
+	if !ok || c.at.Before(allowed.start) {
+		return status.Errorf(codes.PermissionDenied, "%s is not authorized to spend %s from %s", displayAddress(spender), key.contract, displayAddress(owner))
+	}
+
+	if allowed.amount == nil {
+		return status.Error(codes.Unimplemented, "currency equivalent allowances are not simulated")
+	}
+
+	period := allowed.periodAt(c.at)
+
+	used := big.NewInt(0)
+	if period == allowed.period {
+		used.Set(allowed.used)
+	}
+
+	pending := c.allowances[key]
+	if pending == nil {
+		pending = big.NewInt(0)
+	}
+
+	pending = new(big.Int).Add(pending, amount)
+	if new(big.Int).Add(used, pending).Cmp(allowed.amount) > 0 {
+		return status.Errorf(codes.FailedPrecondition, "insufficient allowance: %s of %s parts left this period, %s parts requested", new(big.Int).Sub(allowed.amount, used), allowed.amount, pending)
+	}
+
+	c.allowances[key] = pending
+	c.effects = append(c.effects, func() {
+		if allowed.period != period {
+			allowed.period = period
+			allowed.used = big.NewInt(0)
+		}
+		allowed.used = new(big.Int).Add(allowed.used, amount)
+	})
+
+	return nil
+}
+
+// mint grows the supply of the contract for a recipient, within the released max supply
+func (c *change) mint(contract *ledgerContract, recipient []byte, amount *big.Int) error {
+	if amount.Sign() == 0 {
+		return status.Error(codes.InvalidArgument, "mint amount must be positive")
+	}
+
+	supply := new(big.Int).Add(contract.supply, amount)
+	if mintable := contract.mintable(c.at); mintable != nil && supply.Cmp(mintable) > 0 {
+		return status.Errorf(codes.FailedPrecondition, "mint of %s exceeds the released max supply of %s (%s minted)", amount, mintable, contract.supply)
+	}
+
+	if err := c.receive(contract, recipient); err != nil {
+		return err
+	}
+
+	c.effects = append(c.effects, func() { contract.supply.Add(contract.supply, amount) })
+	return nil
+}
+
+// receive checks a recipient, which must be set and compliant with KYC contracts
+func (c *change) receive(contract *ledgerContract, recipient []byte) error {
+	if len(recipient) == 0 {
+		return status.Error(codes.InvalidArgument, "recipient address is required")
+	}
+
+	return c.ledger.compliant(contract, recipient, c.at)
+}
+
+// useNonce checks that a wallet uses its next nonce
+func (c *change) useNonce(address []byte, nonce uint64) error {
+	last, ok := c.nonces[string(address)]
+	if !ok {
+		last = c.ledger.nonces[string(address)]
+	}
+
+	if nonce != last+1 {
+		return status.Errorf(codes.FailedPrecondition, "invalid nonce %d for %s, expected %d", nonce, displayAddress(address), last+1)
+	}
+
+	c.nonces[string(address)] = nonce
+	return nil
+}
+
+// charge takes a base fee from the payer
+func (c *change) charge(feeID string, payer []byte, fee *big.Int) error {
+	if fee.Sign() == 0 {
+		return nil
+	}
+
+	if err := c.debit(feeID, payer, fee); err != nil {
+		return err
+	}
+
+	l := c.ledger
+	c.effects = append(c.effects, func() {
+		if l.fees[feeID] == nil {
+			l.fees[feeID] = big.NewInt(0)
+		}
+		l.fees[feeID].Add(l.fees[feeID], fee)
+	})
+
+	return nil
+}
+
+// debit takes amount from a wallet, which must hold it including the pending changes
+func (c *change) debit(contractID string, address []byte, amount *big.Int) error {
+	contract, err := c.ledger.contract(contractID)
+	if err != nil {
+		return err
+	}
+
+	key := balanceKey{contract: contractID, address: string(address)}
+	balance := new(big.Int).Add(contract.balance(key.address), c.delta(key))
+
+	if balance.Cmp(amount) < 0 {
+		return status.Errorf(codes.FailedPrecondition, "insufficient balance: %s holds %s parts of %s, %s needed", displayAddress(address), balance, contractID, amount)
+	}
+
+	c.balances[key] = new(big.Int).Sub(c.delta(key), amount)
+	return nil
+}
+
+// credit gives amount to a wallet
+func (c *change) credit(contractID string, address []byte, amount *big.Int) {
+	key := balanceKey{contract: contractID, address: string(address)}
+	c.balances[key] = new(big.Int).Add(c.delta(key), amount)
+}
+
+func (c *change) delta(key balanceKey) *big.Int {
+	if delta, ok := c.balances[key]; ok {
+		return delta
+	}
+	return big.NewInt(0)
+}
+
+// keyAddress is the wallet address of a transaction key
+func keyAddress(key *pb.PublicKey) ([]byte, error) {
+	address, _, err := wallet.GetAddressFromKey(key)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid public key: %v", err)
+	}
+	return address, nil
+}
@@ -0,0 +1,125 @@
+package zeratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"google.golang.org/grpc/codes"
+)
+
+// indexer serves the store requests the SDK makes to the indexer
+type indexer struct {
+	server *Server
+}
+
+func (i *indexer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s := i.server
+	query := rawQuery(r.URL.RawQuery)
+	requestType := query["requestType"]
+
+	if r.Header.Get("Authorization") != "Api-Key "+APIKey {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if failure := s.failure(requestType); failure != nil {
+		http.Error(w, failure.Message, httpStatus(failure.Code))
+		return
+	}
+
+	switch requestType {
+	case "getNextNonce":
+		address, err := transcode.Base58Decode(query["address"])
+		if err != nil {
+			http.Error(w, "invalid address", http.StatusBadRequest)
+			return
+		}
+
+		nonce, _ := s.lastNonce(address)
+		fmt.Fprint(w, nonce+1)
+
+	case "getContractGlance":
+		contract, ok := s.contract(query["symbol"])
+		if !ok {
+			http.Error(w, "contract does not exist", http.StatusNotFound)
+			return
+		}
+
+		glance := map[string]any{
+			"supplyInfo": map[string]any{"parts": contract.Parts},
+			"tokenInfo":  map[string]any{"type": contract.Type},
+		}
+		writeJSON(w, glance)
+
+	case "getTransaction":
+		hash := query["hash"]
+		included, ok := s.blocks[hash]
+		if !ok {
+			http.Error(w, "transaction does not exist", http.StatusNotFound)
+			return
+		}
+
+		receipt := map[string]any{
+			"hash":        hash,
+			"status":      "OK",
+			"blockHeight": included.height,
+			"timestamp":   included.time.Unix(),
+		}
+		if included.feeID != "" {
+			receipt["fees"] = []map[string]string{{"contractId": included.feeID, "amount": included.feeAmount}}
+		}
+		writeJSON(w, receipt)
+
+	default:
+		http.Error(w, fmt.Sprintf("unknown request type %q", requestType), http.StatusBadRequest)
+	}
+}
+
+// rawQuery parses the query without turning + into a space, the SDK sends contract ids (ie $ZRA+0000) unescaped
+func rawQuery(query string) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range strings.Split(query, "&") {
+		key, value, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+		values[key] = value
+	}
+	return values
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+// httpStatus maps a scripted gRPC failure to the status an HTTP service would answer with
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,486 @@
+package zeratest
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/ZeraVision/zera-go-sdk/wallet"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// feePercentScale is 100% in InputTransfers.FeePercent (6 digits of precision)
+var feePercentScale = big.NewInt(100_000_000)
+
+// Ledger is a deterministic in-memory ledger that applies transactions the way a validator would:
+//
+//   - CoinTXN moves balances (in parts) from the input wallets, or the allowance wallets, to the outputs
+//   - MintTXN and ItemizedMintTXN need a restricted key with the mint permission and respect the max supply (and its release schedule)
+//   - InstrumentContract creates a contract and credits its premint wallets
+//   - AllowanceTXN authorizes or revokes a wallet to spend from the signer, per period
+//   - ComplianceTXN needs a restricted key with the compliance permission and assigns or revokes compliance levels
+//   - NFTTXN moves an item held by the signer
+//
+// Every transaction must use the next nonce of its keys and pays its base fee (FeeId / FeeAmount) from the signer.
+// Contracts with KycStatus only move to and from wallets that meet their TokenCompliance (any compliance level of
+// the contract itself if it has none). Other transaction types only use a nonce and pay the base fee, contract fees
+// and currency equivalent allowances are not simulated.
+//
+// Time is taken from the transaction timestamps, so applying the same transactions in the same order always gives
+// the same state. A transaction is applied completely or, if it is rejected, not at all. A Ledger is safe for
+// concurrent use.
+type Ledger struct {
+	mu         sync.Mutex
+	nonces     map[string]uint64 // last used nonce by wallet address bytes
+	contracts  map[string]*ledgerContract
+	allowances map[allowanceKey]*allowance
+	fees       map[string]*big.Int // base fees collected by contract id
+}
+
+// ledgerContract is the state of a contract
+type ledgerContract struct {
+	definition *pb.InstrumentContract
+	parts      *big.Int // parts per coin, nil for nft / sbt contracts
+	maxSupply  *big.Int // nil for an unlimited supply
+	releases   []release
+	supply     *big.Int
+	balances   map[string]*big.Int             // parts by wallet address bytes
+	items      map[string]string               // holder address bytes by item id
+	compliance map[string]map[uint32]time.Time // expiry of the compliance levels of a wallet (zero never expires)
+}
+
+// release is a MaxSupplyRelease, the supply that can be minted from the release date
+type release struct {
+	date   time.Time
+	amount *big.Int
+}
+
+type allowanceKey struct {
+	contract string
+	owner    string
+	spender  string
+}
+
+// allowance is what a spender may take from the owner each period
+type allowance struct {
+	amount        *big.Int // parts per period, nil for a currency equivalent allowance
+	periodSeconds uint32
+	periodMonths  uint32
+	start         time.Time
+	period        int64    // period of used
+	used          *big.Int // parts spent in period
+}
+
+// NewLedger is an empty ledger with the $ZRA+0000 contract (1,000,000,000 parts per coin, unlimited supply).
+func NewLedger() *Ledger {
+	l := &Ledger{
+		nonces:     make(map[string]uint64),
+		contracts:  make(map[string]*ledgerContract),
+		allowances: make(map[allowanceKey]*allowance),
+		fees:       make(map[string]*big.Int),
+	}
+
+	l.AddContract(&pb.InstrumentContract{
+		Type:             pb.CONTRACT_TYPE_TOKEN,
+		ContractId:       "$ZRA+0000",
+		Symbol:           "ZRA",
+		Name:             "ZERA",
+		CoinDenomination: &pb.CoinDenomination{DenominationName: "ZERA", Amount: "1000000000"},
+	})
+
+	return l
+}
+
+// AddContract creates a contract without a transaction (no nonce, fee or signature), ie to set up restricted keys
+// or compliance requirements for a test. Premint wallets are credited.
+func (l *Ledger) AddContract(definition *pb.InstrumentContract) error {
+	contract, err := newContract(definition)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.contracts[definition.GetContractId()]; ok {
+		return fmt.Errorf("contract %s already exists", definition.GetContractId())
+	}
+
+	l.contracts[definition.GetContractId()] = contract
+	return nil
+}
+
+// Fund credits parts of contractID to address (base58) without a transaction, ie to give test wallets a starting
+// balance. The supply grows by parts, regardless of the max supply.
+func (l *Ledger) Fund(address string, contractID string, parts *big.Int) error {
+	key, err := addressKey(address)
+	if err != nil {
+		return err
+	}
+
+	if parts.Sign() < 0 {
+		return fmt.Errorf("cannot fund a negative amount")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	contract, err := l.contract(contractID)
+	if err != nil {
+		return err
+	}
+
+	contract.add(key, parts)
+	contract.supply.Add(contract.supply, parts)
+	return nil
+}
+
+// Balance is the balance in parts of address (base58) in contractID.
+func (l *Ledger) Balance(address string, contractID string) (*big.Int, error) {
+	key, err := addressKey(address)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	contract, err := l.contract(contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).Set(contract.balance(key)), nil
+}
+
+// Supply is the minted supply of contractID in parts (the number of items for nft / sbt contracts).
+func (l *Ledger) Supply(contractID string) (*big.Int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	contract, err := l.contract(contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).Set(contract.supply), nil
+}
+
+// Holder is the address (base58) holding an item of an nft / sbt contract.
+func (l *Ledger) Holder(contractID string, itemID string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	contract, err := l.contract(contractID)
+	if err != nil {
+		return "", err
+	}
+
+	holder, ok := contract.items[itemID]
+	if !ok {
+		return "", fmt.Errorf("item %s of %s does not exist", itemID, contractID)
+	}
+
+	return displayAddress([]byte(holder)), nil
+}
+
+// Fees are the base fees paid in contractID so far, in parts.
+func (l *Ledger) Fees(contractID string) *big.Int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if fees, ok := l.fees[contractID]; ok {
+		return new(big.Int).Set(fees)
+	}
+	return big.NewInt(0)
+}
+
+// Nonce is the last used nonce of address (base58), 0 if it has not transacted.
+func (l *Ledger) Nonce(address string) (uint64, error) {
+	key, err := addressKey(address)
+	if err != nil {
+		return 0, err
+	}
+
+	nonce, _ := l.lastNonce([]byte(key))
+	return nonce, nil
+}
+
+// SetNonce sets the last used nonce of address (base58), the next transaction must use nonce + 1.
+func (l *Ledger) SetNonce(address string, nonce uint64) error {
+	key, err := addressKey(address)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.nonces[key] = nonce
+	return nil
+}
+
+// Apply applies a signed transaction, it does not check signatures or the hash (a Server does before applying).
+// Rejected transactions return a gRPC status error like a validator would, which zeraerr.FromGRPC classifies.
+func (l *Ledger) Apply(txn proto.Message) error {
+	signed, ok := txn.(signedTxn)
+	if !ok || signed.GetBase() == nil {
+		return status.Error(codes.InvalidArgument, "transaction has no base")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c := &change{
+		ledger:     l,
+		at:         signed.GetBase().GetTimestamp().AsTime(),
+		balances:   make(map[balanceKey]*big.Int),
+		nonces:     make(map[string]uint64),
+		allowances: make(map[allowanceKey]*big.Int),
+	}
+
+	var err error
+	if coin, ok := txn.(*pb.CoinTXN); ok {
+		err = c.coin(coin)
+	} else {
+		err = c.signed(signed)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	c.commit()
+	return nil
+}
+
+// lastNonce is the last used nonce of a wallet, false if it has not transacted
+func (l *Ledger) lastNonce(address []byte) (uint64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	nonce, ok := l.nonces[string(address)]
+	return nonce, ok
+}
+
+// glance is the indexer glance data of a contract
+func (l *Ledger) glance(symbol string) (Contract, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	contract, ok := l.contracts[symbol]
+	if !ok {
+		return Contract{}, false
+	}
+
+	return Contract{Symbol: symbol, Type: contractType(contract.definition), Parts: contract.parts}, true
+}
+
+// contract looks up a contract. Callers hold l.mu.
+func (l *Ledger) contract(contractID string) (*ledgerContract, error) {
+	contract, ok := l.contracts[contractID]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "contract %s does not exist", contractID)
+	}
+	return contract, nil
+}
+
+// compliant checks that a wallet meets the compliance requirements of a KYC contract at time at. Callers hold l.mu.
+func (l *Ledger) compliant(contract *ledgerContract, address []byte, at time.Time) error {
+	if !contract.definition.GetKycStatus() {
+		return nil
+	}
+
+	requirements := contract.definition.GetTokenCompliance()
+
+	if len(requirements) == 0 {
+		for _, expiry := range contract.compliance[string(address)] {
+			if expiry.IsZero() || at.Before(expiry) {
+				return nil
+			}
+		}
+	}
+
+	// any one of the requirements, with every level it lists
+	for _, requirement := range requirements {
+		met := true
+		for _, level := range requirement.GetCompliance() {
+			if !l.holds(level.GetContractId(), address, level.GetComplianceLevel(), at) {
+				met = false
+				break
+			}
+		}
+
+		if met {
+			return nil
+		}
+	}
+
+	return status.Errorf(codes.FailedPrecondition, "%s does not meet the compliance requirements of %s", displayAddress(address), contract.definition.GetContractId())
+}
+
+// holds reports whether a wallet has a compliance level of a contract at time at. Callers hold l.mu.
+func (l *Ledger) holds(contractID string, address []byte, level uint32, at time.Time) bool {
+	contract, ok := l.contracts[contractID]
+	if !ok {
+		return false
+	}
+
+	expiry, ok := contract.compliance[string(address)][level]
+	return ok && (expiry.IsZero() || at.Before(expiry))
+}
+
+// authorize checks that signer is a restricted key of the contract with a permission. Callers hold l.mu.
+func (l *Ledger) authorize(contract *ledgerContract, signer []byte, action string, permitted func(*pb.RestrictedKey) bool) error {
+	for _, key := range contract.definition.GetRestrictedKeys() {
+		address, _, err := wallet.GetAddressFromKey(key.GetPublicKey())
+		if err == nil && bytes.Equal(address, signer) && permitted(key) {
+			return nil
+		}
+	}
+
+	return status.Errorf(codes.PermissionDenied, "%s is not authorized to %s %s", displayAddress(signer), action, contract.definition.GetContractId())
+}
+
+// newContract validates a contract definition and creates its state, with the premint wallets credited
+func newContract(definition *pb.InstrumentContract) (*ledgerContract, error) {
+	if definition.GetContractId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "contract id is required")
+	}
+
+	contract := &ledgerContract{
+		definition: proto.Clone(definition).(*pb.InstrumentContract),
+		supply:     big.NewInt(0),
+		balances:   make(map[string]*big.Int),
+		items:      make(map[string]string),
+		compliance: make(map[string]map[uint32]time.Time),
+	}
+
+	var err error
+
+	if definition.GetType() == pb.CONTRACT_TYPE_TOKEN {
+		if contract.parts, err = parseParts(definition.GetCoinDenomination().GetAmount(), "coin denomination"); err != nil {
+			return nil, err
+		}
+
+		if contract.parts.Sign() == 0 {
+			return nil, status.Error(codes.InvalidArgument, "coin denomination is required for tokens")
+		}
+	}
+
+	if definition.MaxSupply != nil {
+		if contract.maxSupply, err = parseParts(definition.GetMaxSupply(), "max supply"); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, scheduled := range definition.GetMaxSupplyRelease() {
+		amount, err := parseParts(scheduled.GetAmount(), "max supply release")
+		if err != nil {
+			return nil, err
+		}
+
+		contract.releases = append(contract.releases, release{date: scheduled.GetReleaseDate().AsTime(), amount: amount})
+	}
+
+	for _, premint := range definition.GetPremintWallets() {
+		amount, err := parseParts(premint.GetAmount(), "premint amount")
+		if err != nil {
+			return nil, err
+		}
+
+		contract.add(string(premint.GetAddress()), amount)
+		contract.supply.Add(contract.supply, amount)
+	}
+
+	if contract.maxSupply != nil && contract.supply.Cmp(contract.maxSupply) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "premint of %s parts exceeds the max supply of %s", contract.supply, contract.maxSupply)
+	}
+
+	return contract, nil
+}
+
+// mintable is the supply that can be minted up to at time at, nil if it is unlimited
+func (c *ledgerContract) mintable(at time.Time) *big.Int {
+	if len(c.releases) == 0 {
+		return c.maxSupply
+	}
+
+	released := big.NewInt(0)
+	for _, release := range c.releases {
+		if !release.date.After(at) {
+			released.Add(released, release.amount)
+		}
+	}
+
+	if c.maxSupply != nil && c.maxSupply.Cmp(released) < 0 {
+		return c.maxSupply
+	}
+	return released
+}
+
+func (c *ledgerContract) balance(address string) *big.Int {
+	if balance, ok := c.balances[address]; ok {
+		return balance
+	}
+	return big.NewInt(0)
+}
+
+// add adds a (possibly negative) amount to a balance
+func (c *ledgerContract) add(address string, amount *big.Int) {
+	balance := new(big.Int).Add(c.balance(address), amount)
+	if balance.Sign() == 0 {
+		delete(c.balances, address)
+		return
+	}
+	c.balances[address] = balance
+}
+
+// periodAt is the allowance period time at falls in, periods restart the allowance
+func (a *allowance) periodAt(at time.Time) int64 {
+	switch {
+	case a.periodSeconds > 0:
+		return int64(at.Sub(a.start) / (time.Duration(a.periodSeconds) * time.Second))
+	case a.periodMonths > 0:
+		months := (at.Year()-a.start.Year())*12 + int(at.Month()) - int(a.start.Month())
+		if a.start.AddDate(0, months, 0).After(at) {
+			months--
+		}
+		return int64(months / int(a.periodMonths))
+	default:
+		return 0 // never restarts
+	}
+}
+
+// contractType is the glance type of a contract, ie token
+func contractType(definition *pb.InstrumentContract) string {
+	return strings.ToLower(strings.TrimPrefix(definition.GetType().String(), "CONTRACT_TYPE_"))
+}
+
+// parseParts parses a non-negative amount of parts, "" is zero
+func parseParts(value string, field string) (*big.Int, error) {
+	if value == "" {
+		return big.NewInt(0), nil
+	}
+
+	parts, ok := new(big.Int).SetString(value, 10)
+	if !ok || parts.Sign() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s %q", field, value)
+	}
+	return parts, nil
+}
+
+// displayAddress is base58 for wallet addresses and the key itself for gov_ / sc_ addresses
+func displayAddress(address []byte) string {
+	if bytes.HasPrefix(address, []byte("gov_")) || bytes.HasPrefix(address, []byte("sc_")) || bytes.HasPrefix(address, []byte("$")) {
+		return string(address)
+	}
+	return transcode.Base58Encode(address)
+}
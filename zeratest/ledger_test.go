@@ -0,0 +1,342 @@
+package zeratest_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/allowance"
+	"github.com/ZeraVision/zera-go-sdk/compliance"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/itemmint"
+	"github.com/ZeraVision/zera-go-sdk/mint"
+	"github.com/ZeraVision/zera-go-sdk/nfttransfer"
+	"github.com/ZeraVision/zera-go-sdk/transfer"
+	"github.com/ZeraVision/zera-go-sdk/zera"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
+	"github.com/ZeraVision/zera-go-sdk/zeratest"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// same private key as TEST_PUBLIC, hashed with SHA3-256 instead of BLAKE3
+	OTHER_ADDRESS = "QK2KwEe1qKng1mzfiyDaQMKqYzFvman5CPdEVyRy1PV"
+	OTHER_PUBLIC  = "A_a_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"
+
+	RECIPIENT_ADDRESS = "Hv3KUwrmR8C8XVSxuJFJrQqeDixeDnakUTkUUMZkFCUS"
+)
+
+func simulator(t *testing.T, ledger *zeratest.Ledger) (*zeratest.Server, *zera.Client) {
+	for _, address := range []string{TEST_ADDRESS, OTHER_ADDRESS} {
+		if err := ledger.Fund(address, "$ZRA+0000", big.NewInt(10_000_000_000)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	server := zeratest.NewSimulator(ledger)
+	t.Cleanup(server.Close)
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return server, client
+}
+
+func expectBalance(t *testing.T, ledger *zeratest.Ledger, address, contractID string, expected int64) {
+	t.Helper()
+
+	balance, err := ledger.Balance(address, contractID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if balance.Cmp(big.NewInt(expected)) != 0 {
+		t.Fatalf("Expected %s to hold %d parts of %s, got %s", address, expected, contractID, balance)
+	}
+}
+
+func TestLedgerTransfer(t *testing.T) {
+	ledger := zeratest.NewLedger()
+	server, client := simulator(t, ledger)
+	ctx := context.Background()
+
+	txn := coinTxn(t, server, "1.5")
+	if _, err := client.Submit(ctx, txn); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// the amount and the 1000000 parts fee
+	expectBalance(t, ledger, TEST_ADDRESS, "$ZRA+0000", 8_499_000_000)
+	expectBalance(t, ledger, RECIPIENT_ADDRESS, "$ZRA+0000", 1_500_000_000)
+
+	if fees := ledger.Fees("$ZRA+0000"); fees.Cmp(big.NewInt(1_000_000)) != 0 {
+		t.Errorf("Expected 1000000 parts of fees, got %s", fees)
+	}
+
+	// a replay uses a nonce that is already used
+	if _, err := client.Submit(ctx, txn); !errors.Is(err, zeraerr.ErrBadNonce) {
+		t.Fatalf("Expected bad nonce, got %v", err)
+	}
+
+	// 8.499 left is not enough for 8.5 and the fee
+	if _, err := client.Submit(ctx, coinTxn(t, server, "8.5")); !errors.Is(err, zeraerr.ErrInsufficientBalance) {
+		t.Fatalf("Expected insufficient balance, got %v", err)
+	}
+
+	// rejected transactions change nothing
+	expectBalance(t, ledger, TEST_ADDRESS, "$ZRA+0000", 8_499_000_000)
+
+	if nonce, _ := ledger.Nonce(TEST_ADDRESS); nonce != 1 {
+		t.Errorf("Expected nonce 1, got %d", nonce)
+	}
+
+	if _, err := client.Submit(ctx, coinTxn(t, server, "8.498")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectBalance(t, ledger, TEST_ADDRESS, "$ZRA+0000", 0)
+}
+
+func TestLedgerMintAndCompliance(t *testing.T) {
+	ctx := context.Background()
+	ledger := zeratest.NewLedger()
+
+	restricted := helper.NewPrivateKeySigner("r_"+TEST_PUBLIC, TEST_PRIVATE)
+	restrictedKey, err := helper.TxnPublicKey(restricted)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err = ledger.AddContract(&pb.InstrumentContract{
+		Type:             pb.CONTRACT_TYPE_TOKEN,
+		ContractId:       "$KYC+0000",
+		CoinDenomination: &pb.CoinDenomination{DenominationName: "parts", Amount: "100"},
+		MaxSupply:        proto.String("100000"),
+		RestrictedKeys:   []*pb.RestrictedKey{{PublicKey: restrictedKey, Mint: true, Compliance: true}},
+		KycStatus:        true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	server, client := simulator(t, ledger)
+
+	mintTxn := func(signer helper.Signer, address string, amount string) *pb.MintTXN {
+		nonceInfo, err := server.NonceInfo(address)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		txn, err := mint.CreateMintTxnWithSigner(ctx, nonceInfo, "$KYC+0000", amount, RECIPIENT_ADDRESS, signer, "$ZRA+0000", "1000000")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return txn
+	}
+
+	// the recipient has no compliance level yet
+	if _, err := client.Submit(ctx, mintTxn(restricted, TEST_ADDRESS, "500")); !errors.Is(err, zeraerr.ErrRejected) {
+		t.Fatalf("Expected a rejection, got %v", err)
+	}
+
+	// only restricted keys of the contract with the mint permission mint
+	other := helper.NewPrivateKeySigner("r_"+OTHER_PUBLIC, TEST_PRIVATE)
+	if _, err := client.Submit(ctx, mintTxn(other, OTHER_ADDRESS, "500")); !errors.Is(err, zeraerr.ErrUnauthorizedKey) {
+		t.Fatalf("Expected unauthorized key, got %v", err)
+	}
+
+	nonceInfo, err := server.NonceInfo(TEST_ADDRESS)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	complianceTxn, err := compliance.CreateComplianceTxnWithSigner(ctx, nonceInfo, "$KYC+0000",
+		[]compliance.ComplianceDetails{{WalletAddr: RECIPIENT_ADDRESS, Level: 1, Assign: true}},
+		restricted, "$ZRA+0000", "1000000",
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.Submit(ctx, complianceTxn); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.Submit(ctx, mintTxn(restricted, TEST_ADDRESS, "500")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectBalance(t, ledger, RECIPIENT_ADDRESS, "$KYC+0000", 500)
+
+	// 99,500 parts are left below the max supply
+	if _, err := client.Submit(ctx, mintTxn(restricted, TEST_ADDRESS, "99501")); !errors.Is(err, zeraerr.ErrRejected) {
+		t.Fatalf("Expected a rejection, got %v", err)
+	}
+
+	if supply, _ := ledger.Supply("$KYC+0000"); supply.Cmp(big.NewInt(500)) != 0 {
+		t.Errorf("Expected a supply of 500, got %s", supply)
+	}
+
+	// fees of the compliance transaction and the mint, rejected transactions pay nothing
+	expectBalance(t, ledger, TEST_ADDRESS, "$ZRA+0000", 9_998_000_000)
+}
+
+func TestLedgerAllowance(t *testing.T) {
+	ctx := context.Background()
+	ledger := zeratest.NewLedger()
+	server, client := simulator(t, ledger)
+
+	// TEST_ADDRESS lets OTHER_ADDRESS spend 2 ZRA a month
+	nonceInfo, err := server.NonceInfo(TEST_ADDRESS)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	months := uint32(1)
+	allowanceTxn, err := allowance.CreateAllowanceTxnWithSigner(ctx, nonceInfo, "$ZRA+0000", allowance.AllowanceDetails{
+		Authorize:    true,
+		WalletAddr:   OTHER_ADDRESS,
+		Amount:       big.NewInt(2_000_000_000),
+		PeriodMonths: &months,
+		StartTime:    time.Now().Add(-time.Hour).Unix(),
+	}, helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE), "$ZRA+0000", "1000000")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.Submit(ctx, allowanceTxn); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	spend := func(amount string) *pb.CoinTXN {
+		owner := TEST_ADDRESS
+		inputs := []transfer.Inputs{
+			{B58Address: OTHER_ADDRESS, Signer: helper.NewPrivateKeySigner(OTHER_PUBLIC, TEST_PRIVATE), FeePercent: 100},
+			{AllowanceAddr: &owner, Amount: amount},
+		}
+
+		nonceInfo, err := server.NonceInfo(OTHER_ADDRESS, TEST_ADDRESS)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		txn, err := transfer.CreateCoinTxnWithContext(ctx, nonceInfo, server.PartsInfo("$ZRA+0000"), inputs,
+			map[string]string{RECIPIENT_ADDRESS: amount}, "$ZRA+0000", "1000000", nil, nil, 1,
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return txn
+	}
+
+	if _, err := client.Submit(ctx, spend("1.5")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// the owner pays the amount, the spender the fee
+	expectBalance(t, ledger, TEST_ADDRESS, "$ZRA+0000", 8_499_000_000)
+	expectBalance(t, ledger, OTHER_ADDRESS, "$ZRA+0000", 9_999_000_000)
+	expectBalance(t, ledger, RECIPIENT_ADDRESS, "$ZRA+0000", 1_500_000_000)
+
+	// 0.5 is left this month
+	if _, err := client.Submit(ctx, spend("0.6")); !errors.Is(err, zeraerr.ErrInsufficientBalance) {
+		t.Fatalf("Expected insufficient allowance, got %v", err)
+	}
+
+	if _, err := client.Submit(ctx, spend("0.5")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	nonceInfo, err = server.NonceInfo(TEST_ADDRESS)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	revokeTxn, err := allowance.CreateAllowanceTxnWithSigner(ctx, nonceInfo, "$ZRA+0000", allowance.AllowanceDetails{WalletAddr: OTHER_ADDRESS},
+		helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE), "$ZRA+0000", "1000000")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.Submit(ctx, revokeTxn); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// without an allowance the owner's funds cannot be spent
+	if _, err := client.Submit(ctx, spend("0.1")); !errors.Is(err, zeraerr.ErrUnauthorizedKey) {
+		t.Fatalf("Expected unauthorized key, got %v", err)
+	}
+}
+
+func TestLedgerNFT(t *testing.T) {
+	ctx := context.Background()
+	ledger := zeratest.NewLedger()
+
+	restricted := helper.NewPrivateKeySigner("r_"+TEST_PUBLIC, TEST_PRIVATE)
+	restrictedKey, err := helper.TxnPublicKey(restricted)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err = ledger.AddContract(&pb.InstrumentContract{
+		Type:           pb.CONTRACT_TYPE_NFT,
+		ContractId:     "$ART+0000",
+		RestrictedKeys: []*pb.RestrictedKey{{PublicKey: restrictedKey, Mint: true}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	server, client := simulator(t, ledger)
+
+	nonceInfo, err := server.NonceInfo(TEST_ADDRESS)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	mintTxn, err := itemmint.CreateItemMintTxnWithSigner(ctx, nonceInfo, "$ART+0000", big.NewInt(1), TEST_ADDRESS, restricted, "$ZRA+0000", "1000000", nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.Submit(ctx, mintTxn); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	nftTxn := func(signer helper.Signer, address string) *pb.NFTTXN {
+		nonceInfo, err := server.NonceInfo(address)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		txn, err := nfttransfer.CreateNftTransferWithSigner(ctx, nonceInfo, "$ART+0000", big.NewInt(1), RECIPIENT_ADDRESS, signer, "$ZRA+0000", "1000000", nil, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return txn
+	}
+
+	// only the holder moves the item
+	if _, err := client.Submit(ctx, nftTxn(helper.NewPrivateKeySigner(OTHER_PUBLIC, TEST_PRIVATE), OTHER_ADDRESS)); !errors.Is(err, zeraerr.ErrUnauthorizedKey) {
+		t.Fatalf("Expected unauthorized key, got %v", err)
+	}
+
+	if _, err := client.Submit(ctx, nftTxn(helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE), TEST_ADDRESS)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	holder, err := ledger.Holder("$ART+0000", "1")
+	if err != nil || holder != RECIPIENT_ADDRESS {
+		t.Fatalf("Expected %s to hold item 1, got %s (%v)", RECIPIENT_ADDRESS, holder, err)
+	}
+
+	if supply, _ := ledger.Supply("$ART+0000"); supply.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("Expected one item, got %s", supply)
+	}
+}
@@ -0,0 +1,203 @@
+package zeratest
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/offline"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/ZeraVision/zera-go-sdk/wallet"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// signedTxn is implemented by every transaction type (all of them carry a BaseTXN)
+type signedTxn interface {
+	proto.Message
+	GetBase() *pb.BaseTXN
+}
+
+// block is where an accepted transaction was included, with the base fee it paid
+type block struct {
+	height    uint64
+	time      time.Time
+	feeID     string
+	feeAmount string
+}
+
+// validator implements the TXNService and APIService of the server
+type validator struct {
+	pb.UnimplementedTXNServiceServer
+	pb.UnimplementedAPIServiceServer
+
+	server *Server
+}
+
+func (v *validator) Coin(ctx context.Context, txn *pb.CoinTXN) (*emptypb.Empty, error) {
+	return v.submit("Coin", txn)
+}
+
+func (v *validator) Mint(ctx context.Context, txn *pb.MintTXN) (*emptypb.Empty, error) {
+	return v.submit("Mint", txn)
+}
+
+func (v *validator) ItemMint(ctx context.Context, txn *pb.ItemizedMintTXN) (*emptypb.Empty, error) {
+	return v.submit("ItemMint", txn)
+}
+
+func (v *validator) Contract(ctx context.Context, txn *pb.InstrumentContract) (*emptypb.Empty, error) {
+	return v.submit("Contract", txn)
+}
+
+func (v *validator) GovernVote(ctx context.Context, txn *pb.GovernanceVote) (*emptypb.Empty, error) {
+	return v.submit("GovernVote", txn)
+}
+
+func (v *validator) GovernProposal(ctx context.Context, txn *pb.GovernanceProposal) (*emptypb.Empty, error) {
+	return v.submit("GovernProposal", txn)
+}
+
+func (v *validator) ContractUpdate(ctx context.Context, txn *pb.ContractUpdateTXN) (*emptypb.Empty, error) {
+	return v.submit("ContractUpdate", txn)
+}
+
+func (v *validator) NFT(ctx context.Context, txn *pb.NFTTXN) (*emptypb.Empty, error) {
+	return v.submit("NFT", txn)
+}
+
+func (v *validator) CurrencyEquiv(ctx context.Context, txn *pb.SelfCurrencyEquiv) (*emptypb.Empty, error) {
+	return v.submit("CurrencyEquiv", txn)
+}
+
+func (v *validator) AuthCurrencyEquiv(ctx context.Context, txn *pb.AuthorizedCurrencyEquiv) (*emptypb.Empty, error) {
+	return v.submit("AuthCurrencyEquiv", txn)
+}
+
+func (v *validator) ExpenseRatio(ctx context.Context, txn *pb.ExpenseRatioTXN) (*emptypb.Empty, error) {
+	return v.submit("ExpenseRatio", txn)
+}
+
+func (v *validator) Compliance(ctx context.Context, txn *pb.ComplianceTXN) (*emptypb.Empty, error) {
+	return v.submit("Compliance", txn)
+}
+
+func (v *validator) Allowance(ctx context.Context, txn *pb.AllowanceTXN) (*emptypb.Empty, error) {
+	return v.submit("Allowance", txn)
+}
+
+// Nonce returns the last used nonce of the wallet, NotFound if it has not transacted (like a validator)
+func (v *validator) Nonce(ctx context.Context, req *pb.NonceRequest) (*pb.NonceResponse, error) {
+	s := v.server
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if failure := s.failure("Nonce"); failure != nil {
+		return nil, status.Error(failure.Code, failure.Message)
+	}
+
+	address := req.WalletAddress
+	if req.Encoded {
+		decoded, err := transcode.Base58Decode(string(address))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid wallet address: %v", err)
+		}
+		address = decoded
+	}
+
+	nonce, ok := s.lastNonce(address)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "wallet not found")
+	}
+
+	return &pb.NonceResponse{Nonce: nonce}, nil
+}
+
+// submit records the transaction and accepts it unless a failure is scripted, verification fails or the ledger
+// of a simulator rejects it
+func (v *validator) submit(method string, txn signedTxn) (*emptypb.Empty, error) {
+	s := v.server
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	submission := Submission{
+		Method: method,
+		Txn:    proto.Clone(txn),
+		Hash:   hashKey(txn.GetBase().GetHash()),
+	}
+
+	if failure := s.failure(method); failure != nil {
+		submission.Err = status.Error(failure.Code, failure.Message)
+	} else if err := offline.VerifyTxn(txn); err != nil {
+		submission.Err = status.Errorf(codes.InvalidArgument, "invalid transaction: %v", err)
+	} else if err := s.apply(txn); err != nil {
+		submission.Err = err
+	} else {
+		s.accept(submission.Hash, txn)
+	}
+
+	s.submissions = append(s.submissions, submission)
+
+	if submission.Err != nil {
+		return nil, submission.Err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// apply applies the transaction to the ledger of a simulator. Callers hold s.mu.
+func (s *Server) apply(txn signedTxn) error {
+	if s.ledger == nil {
+		return nil
+	}
+	return s.ledger.Apply(txn)
+}
+
+// accept includes the transaction in the next block, moving the nonces of its keys. Callers hold s.mu.
+func (s *Server) accept(hash string, txn signedTxn) {
+	s.blocks[hash] = block{
+		height:    uint64(len(s.blocks)) + 1,
+		time:      time.Now(),
+		feeID:     txn.GetBase().GetFeeId(),
+		feeAmount: txn.GetBase().GetFeeAmount(),
+	}
+
+	keys := []*pb.PublicKey{txn.GetBase().GetPublicKey()}
+	nonces := []uint64{txn.GetBase().GetNonce()}
+
+	if coin, ok := txn.(*pb.CoinTXN); ok {
+		keys = coin.GetAuth().GetPublicKey()
+		nonces = coin.GetAuth().GetNonce()
+	}
+
+	for i, key := range keys {
+		address, _, err := wallet.GetAddressFromKey(key)
+		if err != nil || i >= len(nonces) {
+			continue
+		}
+
+		if nonces[i] > s.nonces[string(address)] {
+			s.nonces[string(address)] = nonces[i]
+		}
+	}
+
+	// new contracts can be looked up on the indexer
+	if contract, ok := txn.(*pb.InstrumentContract); ok {
+		glance := Contract{
+			Symbol: contract.GetContractId(),
+			Type:   strings.ToLower(strings.TrimPrefix(contract.GetType().String(), "CONTRACT_TYPE_")),
+		}
+
+		if contract.GetCoinDenomination() != nil {
+			glance.Parts, _ = new(big.Int).SetString(contract.GetCoinDenomination().GetAmount(), 10)
+		}
+
+		s.contracts[glance.Symbol] = glance
+	}
+}
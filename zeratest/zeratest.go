@@ -0,0 +1,363 @@
+// Package zeratest provides an in-process validator and indexer so code built on the SDK can be tested offline.
+//
+// A Server serves the validator TXNService and APIService over an in-memory gRPC connection (bufconn) and the
+// indexer over an httptest server. It answers nonce and contract glance requests, records every submitted
+// transaction, checks its signatures and hash, and fails requests on demand (see Fail):
+//
+//	server := zeratest.NewServer()
+//	defer server.Close()
+//
+//	client, _ := server.NewClient()
+//	nonceInfo, _ := server.NonceInfo(address)
+//	txn, _ := mint.CreateMintTxnWithSigner(ctx, nonceInfo, "$ZRA+0000", "1000", recipient, signer, "$ZRA+0000", "1000000")
+//	client.Submit(ctx, txn)
+//
+//	server.Submissions() // the mint, with Err set if it was rejected
+//
+// NewSimulator goes further and applies accepted transactions to a Ledger, so balances, supplies, allowances and
+// compliance can be tested end to end through the same client:
+//
+//	ledger := zeratest.NewLedger()
+//	ledger.Fund(address, "$ZRA+0000", big.NewInt(10_000_000_000))
+//
+//	server := zeratest.NewSimulator(ledger)
+//	defer server.Close()
+//
+//	client.Submit(ctx, txn) // rejected like a validator would (ie insufficient balance, bad nonce)
+//	ledger.Balance(recipient, "$ZRA+0000")
+package zeratest
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"sync"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/nonce"
+	"github.com/ZeraVision/zera-go-sdk/parts"
+	"github.com/ZeraVision/zera-go-sdk/ratelimit"
+	"github.com/ZeraVision/zera-go-sdk/track"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/ZeraVision/zera-go-sdk/zera"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ValidatorAddr is the TXNService address of the in-process validator (only reachable with Server.Security)
+	ValidatorAddr = "passthrough:///zeratest:" + zera.DefaultTXNPort
+	// APIAddr is the APIService address of the in-process validator (only reachable with Server.Security)
+	APIAddr = "passthrough:///zeratest:" + zera.DefaultAPIPort
+	// APIKey is the indexer authorization accepted by the server
+	APIKey = "zeratest"
+)
+
+const bufferSize = 1 << 20
+
+// Contract is the glance data the indexer serves for a contract
+type Contract struct {
+	Symbol string   // contract id, ie $ZRA+0000
+	Type   string   // token, nft or sbt
+	Parts  *big.Int // parts per coin (tokens only)
+}
+
+// Submission is a transaction received by the TXNService
+type Submission struct {
+	Method string        // TXNService method, ie Coin or Mint
+	Txn    proto.Message // the transaction as received
+	Hash   string        // hex of Base.Hash as received
+	Err    error         // why the transaction was rejected (invalid signature / hash or a scripted failure), nil if accepted
+}
+
+// Failure scripts an error for upcoming requests
+type Failure struct {
+	Method  string     // TXNService / APIService method (ie Coin, Nonce) or indexer request type (ie getContractGlance), empty for any request
+	Code    codes.Code // gRPC status returned, mapped to an HTTP status for the indexer
+	Message string     // error message returned
+	Times   int        // number of requests to fail (default 1, -1 for every request until Reset)
+}
+
+// Server is an in-process validator and indexer. It is safe for concurrent use.
+type Server struct {
+	Indexer *httptest.Server // the indexer, Indexer.URL is the IndexerURL to use
+
+	listener *bufconn.Listener
+	grpc     *grpc.Server
+
+	mu          sync.Mutex
+	nonces      map[string]uint64 // last used nonce by wallet address bytes
+	contracts   map[string]Contract
+	submissions []Submission
+	failures    []*Failure
+	blocks      map[string]block // accepted transactions by hex hash
+	ledger      *Ledger          // nil unless simulating
+}
+
+// NewServer starts a validator and an indexer that know the $ZRA+0000 contract (1,000,000,000 parts per coin).
+func NewServer() *Server {
+	return newServer(nil)
+}
+
+// NewSimulator is NewServer with accepted transactions applied to ledger (NewLedger if nil), which rejects
+// transactions a validator would reject. Nonces and contracts are served from the ledger.
+func NewSimulator(ledger *Ledger) *Server {
+	if ledger == nil {
+		ledger = NewLedger()
+	}
+	return newServer(ledger)
+}
+
+func newServer(ledger *Ledger) *Server {
+	s := &Server{
+		listener:  bufconn.Listen(bufferSize),
+		grpc:      grpc.NewServer(),
+		nonces:    make(map[string]uint64),
+		contracts: make(map[string]Contract),
+		blocks:    make(map[string]block),
+		ledger:    ledger,
+	}
+
+	s.AddContract(Contract{Symbol: "$ZRA+0000", Type: "token", Parts: big.NewInt(1_000_000_000)})
+
+	validator := &validator{server: s}
+	pb.RegisterTXNServiceServer(s.grpc, validator)
+	pb.RegisterAPIServiceServer(s.grpc, validator)
+	go s.grpc.Serve(s.listener)
+
+	s.Indexer = httptest.NewServer(&indexer{server: s})
+
+	return s
+}
+
+// Close stops the validator and the indexer.
+func (s *Server) Close() {
+	s.grpc.Stop()
+	s.listener.Close()
+	s.Indexer.Close()
+}
+
+// Ledger is the ledger of a simulator, nil for a server from NewServer.
+func (s *Server) Ledger() *Ledger {
+	return s.ledger
+}
+
+// Security connects validator connections to the server, for use in zera.Config, nonce.NonceInfo and track.TrackInfo.
+func (s *Server) Security() *helper.TransportSecurity {
+	return &helper.TransportSecurity{
+		Dialer: func(ctx context.Context, addr string) (net.Conn, error) {
+			return s.listener.DialContext(ctx)
+		},
+	}
+}
+
+// Config is a zera.Config for a client of the server, without rate limits or health checks.
+func (s *Server) Config() zera.Config {
+	return zera.Config{
+		Validators:          []string{ValidatorAddr},
+		PoolSize:            1,
+		Security:            s.Security(),
+		HealthCheckInterval: -1,
+		RateLimit:           &ratelimit.Config{Rate: -1},
+	}
+}
+
+// NewClient is a zera.Client connected to the server (see Config).
+func (s *Server) NewClient() (*zera.Client, error) {
+	return zera.NewClient(s.Config())
+}
+
+// NonceInfo looks up the nonces of addresses (base58) on the validator.
+func (s *Server) NonceInfo(addresses ...string) (nonce.NonceInfo, error) {
+	info := nonce.NonceInfo{
+		ValidatorAddr: APIAddr,
+		Security:      s.Security(),
+		RateLimits:    ratelimit.NewRegistry(ratelimit.Config{Rate: -1}),
+	}
+
+	for _, address := range addresses {
+		req, err := nonce.MakeNonceRequest(address)
+		if err != nil {
+			return nonce.NonceInfo{}, err
+		}
+		info.NonceReqs = append(info.NonceReqs, req)
+	}
+
+	return info, nil
+}
+
+// PartsInfo looks up the parts per coin of symbol on the indexer.
+func (s *Server) PartsInfo(symbol string) parts.PartsInfo {
+	return parts.PartsInfo{
+		Symbol:        symbol,
+		UseIndexer:    true,
+		IndexerUrl:    s.Indexer.URL,
+		Authorization: APIKey,
+		RateLimits:    ratelimit.NewRegistry(ratelimit.Config{Rate: -1}),
+	}
+}
+
+// TrackInfo tracks transactions by hash on the indexer, accepted submissions are confirmed.
+func (s *Server) TrackInfo() track.TrackInfo {
+	return track.TrackInfo{
+		UseIndexer:    true,
+		IndexerURL:    s.Indexer.URL,
+		Authorization: APIKey,
+		RateLimits:    ratelimit.NewRegistry(ratelimit.Config{Rate: -1}),
+	}
+}
+
+// NonceTrackInfo tracks the transaction with nonce sent by address (base58) with the nonce fallback of the
+// validator, accepted submissions are included.
+func (s *Server) NonceTrackInfo(address string, nonce uint64) track.TrackInfo {
+	return track.TrackInfo{
+		Address:       address,
+		Nonce:         nonce,
+		ValidatorAddr: APIAddr,
+		Security:      s.Security(),
+		RateLimits:    ratelimit.NewRegistry(ratelimit.Config{Rate: -1}),
+	}
+}
+
+// AddContract adds or replaces the glance data of a contract.
+func (s *Server) AddContract(contract Contract) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.contracts[contract.Symbol] = contract
+}
+
+// SetNonce sets the last used nonce of address (base58), the next transaction uses nonce + 1.
+func (s *Server) SetNonce(address string, nonce uint64) error {
+	key, err := addressKey(address)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nonces[key] = nonce
+
+	if s.ledger != nil {
+		return s.ledger.SetNonce(address, nonce)
+	}
+	return nil
+}
+
+// Nonce is the last used nonce of address (base58), 0 if it has not transacted.
+func (s *Server) Nonce(address string) (uint64, error) {
+	key, err := addressKey(address)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nonce, _ := s.lastNonce([]byte(key))
+	return nonce, nil
+}
+
+// Submissions returns every transaction received so far, in order.
+func (s *Server) Submissions() []Submission {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Submission(nil), s.submissions...)
+}
+
+// Accepted returns the transactions that passed verification, in order.
+func (s *Server) Accepted() []proto.Message {
+	var accepted []proto.Message
+	for _, submission := range s.Submissions() {
+		if submission.Err == nil {
+			accepted = append(accepted, submission.Txn)
+		}
+	}
+	return accepted
+}
+
+// Fail makes upcoming requests fail, failures are used in the order they were added.
+func (s *Server) Fail(failure Failure) {
+	if failure.Times == 0 {
+		failure.Times = 1
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, &failure)
+}
+
+// Reset removes scripted failures, recorded submissions and nonces. Contracts and the ledger of a simulator are kept.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = nil
+	s.submissions = nil
+	s.nonces = make(map[string]uint64)
+	s.blocks = make(map[string]block)
+}
+
+// failure takes the next scripted failure for method, nil if there is none. Callers hold s.mu.
+func (s *Server) failure(method string) *Failure {
+	for i, failure := range s.failures {
+		if failure.Method != "" && failure.Method != method {
+			continue
+		}
+
+		if failure.Times > 0 {
+			failure.Times--
+			if failure.Times == 0 {
+				s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			}
+		}
+
+		return failure
+	}
+
+	return nil
+}
+
+// lastNonce is the last used nonce of a wallet, false if it has not transacted. Callers hold s.mu.
+func (s *Server) lastNonce(address []byte) (uint64, bool) {
+	if s.ledger != nil {
+		return s.ledger.lastNonce(address)
+	}
+
+	nonce, ok := s.nonces[string(address)]
+	return nonce, ok
+}
+
+// contract is the glance data of a contract. Callers hold s.mu.
+func (s *Server) contract(symbol string) (Contract, bool) {
+	if s.ledger != nil {
+		if contract, ok := s.ledger.glance(symbol); ok {
+			return contract, true
+		}
+	}
+
+	contract, ok := s.contracts[symbol]
+	return contract, ok
+}
+
+// addressKey is the key of a base58 address (or gov_ address) in the nonce map
+func addressKey(address string) (string, error) {
+	req, err := nonce.MakeNonceRequest(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid address %q: %v", address, err)
+	}
+	return string(req.WalletAddress), nil
+}
+
+// hashKey is the hex hash transactions are tracked by
+func hashKey(hash []byte) string {
+	return transcode.HexEncode(hash)
+}
@@ -0,0 +1,299 @@
+package zeratest_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	pb "github.com/ZeraVision/go-zera-network/grpc/protobuf"
+	"github.com/ZeraVision/zera-go-sdk/allowance"
+	"github.com/ZeraVision/zera-go-sdk/convert"
+	"github.com/ZeraVision/zera-go-sdk/helper"
+	"github.com/ZeraVision/zera-go-sdk/mint"
+	"github.com/ZeraVision/zera-go-sdk/parts"
+	"github.com/ZeraVision/zera-go-sdk/track"
+	"github.com/ZeraVision/zera-go-sdk/transcode"
+	"github.com/ZeraVision/zera-go-sdk/transfer"
+	"github.com/ZeraVision/zera-go-sdk/zera"
+	"github.com/ZeraVision/zera-go-sdk/zeraerr"
+	"github.com/ZeraVision/zera-go-sdk/zeratest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	TEST_ADDRESS = "8ZfvifzSPMhhhivnH6NtaBXcmF3vsSaiB8KBULTetBcR"
+	TEST_PUBLIC  = "A_c_FPXdqFTeqC3rHCaAAXmXbunb8C5BbRZEZNGjt23dAVo7"
+	TEST_PRIVATE = "2ap5CkCekErkqJ4UuSGAW1BmRRRNr8hXaebudv1j8TY6mJMSsbnniakorFGmetE4aegsyQAD8WX1N8Q2Y45YEBDs"
+)
+
+func coinTxn(t *testing.T, server *zeratest.Server, amount string) *pb.CoinTXN {
+	nonceInfo, err := server.NonceInfo(TEST_ADDRESS)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	txn, err := transfer.CreateCoinTxnWithContext(context.Background(), nonceInfo, server.PartsInfo("$ZRA+0000"),
+		[]transfer.Inputs{{B58Address: TEST_ADDRESS, Signer: helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE), Amount: amount, FeePercent: 100}},
+		map[string]string{"Hv3KUwrmR8C8XVSxuJFJrQqeDixeDnakUTkUUMZkFCUS": amount},
+		"$ZRA+0000", "1000000", nil, nil, 1,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	return txn
+}
+
+func TestSubmitAndTrack(t *testing.T) {
+	server := zeratest.NewServer()
+	defer server.Close()
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	// parts are looked up on the indexer, nonces on the validator
+	txn := coinTxn(t, server, "1.5")
+	if txn.Auth.Nonce[0] != 1 || txn.OutputTransfers[0].Amount != "1500000000" {
+		t.Fatalf("Expected nonce 1 and 1500000000 parts, got %d and %s", txn.Auth.Nonce[0], txn.OutputTransfers[0].Amount)
+	}
+
+	if _, err := client.Submit(ctx, txn); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	submissions := server.Submissions()
+	if len(submissions) != 1 || submissions[0].Method != "Coin" || submissions[0].Err != nil {
+		t.Fatalf("Expected one accepted Coin submission, got %+v", submissions)
+	}
+
+	if !proto.Equal(submissions[0].Txn, txn) {
+		t.Errorf("Expected the recorded transaction to equal the submitted one")
+	}
+
+	receipt, err := track.WaitForConfirmation(ctx, server.TrackInfo(), transcode.HexEncode(txn.Base.Hash))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if receipt.Status != track.StatusConfirmed || receipt.BlockHeight != 1 {
+		t.Errorf("Expected confirmed at height 1, got %s at %d", receipt.Status, receipt.BlockHeight)
+	}
+
+	if len(receipt.Fees) != 1 || receipt.Fees[0].ContractId != "$ZRA+0000" || receipt.Fees[0].Amount.Int64() != 1000000 {
+		t.Errorf("Expected a fee of 1000000 parts of $ZRA+0000, got %+v", receipt.Fees)
+	}
+
+	// without the indexer only the nonce of the sender is known
+	receipt, err = track.WaitForConfirmation(ctx, server.NonceTrackInfo(TEST_ADDRESS, txn.Auth.Nonce[0]), transcode.HexEncode(txn.Base.Hash))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if receipt.Status != track.StatusIncluded || receipt.BlockHeight != 0 {
+		t.Errorf("Expected included without a block, got %s at %d", receipt.Status, receipt.BlockHeight)
+	}
+
+	// the accepted transaction moved the nonce
+	if next := coinTxn(t, server, "1"); next.Auth.Nonce[0] != 2 {
+		t.Errorf("Expected nonce 2, got %d", next.Auth.Nonce[0])
+	}
+}
+
+func TestSendWithSecurity(t *testing.T) {
+	server := zeratest.NewServer()
+	defer server.Close()
+
+	// the package senders dial with the same transport security as the client
+	txn := coinTxn(t, server, "1")
+	if _, err := transfer.SendCoinTXNWithSecurity(context.Background(), zeratest.ValidatorAddr, txn, server.Security()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	submissions := server.Submissions()
+	if len(submissions) != 1 || submissions[0].Method != "Coin" || submissions[0].Err != nil {
+		t.Fatalf("Expected one accepted Coin submission, got %+v", submissions)
+	}
+}
+
+func TestVerification(t *testing.T) {
+	server := zeratest.NewServer()
+	defer server.Close()
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer client.Close()
+
+	if err := server.SetNonce(TEST_ADDRESS, 41); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	nonceInfo, err := server.NonceInfo(TEST_ADDRESS)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	signer := helper.NewPrivateKeySigner("r_"+TEST_PUBLIC, TEST_PRIVATE)
+	txn, err := mint.CreateMintTxnWithSigner(context.Background(), nonceInfo, "$ZRA+0000", "1000", TEST_ADDRESS, signer, "$ZRA+0000", "1000000")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if txn.Base.Nonce != 42 {
+		t.Fatalf("Expected nonce 42, got %d", txn.Base.Nonce)
+	}
+
+	// changing the amount after signing invalidates the signature
+	tampered := proto.Clone(txn).(*pb.MintTXN)
+	tampered.Amount = "1000000"
+
+	if _, err := client.Submit(context.Background(), tampered); err == nil {
+		t.Fatalf("Expected error for a tampered transaction")
+	}
+
+	// a hash that does not belong to the transaction
+	rehashed := proto.Clone(txn).(*pb.MintTXN)
+	rehashed.Base.Hash = transcode.SHA3256([]byte("other"))
+
+	if _, err := client.Submit(context.Background(), rehashed); err == nil {
+		t.Fatalf("Expected error for a wrong hash")
+	}
+
+	if _, err := client.Submit(context.Background(), txn); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	submissions := server.Submissions()
+	if len(submissions) != 3 || submissions[0].Err == nil || submissions[1].Err == nil || submissions[2].Err != nil {
+		t.Fatalf("Expected two rejected submissions and one accepted, got %+v", submissions)
+	}
+
+	if accepted := server.Accepted(); len(accepted) != 1 || !proto.Equal(accepted[0], txn) {
+		t.Errorf("Expected the valid mint to be accepted, got %d", len(accepted))
+	}
+
+	if nonce, _ := server.Nonce(TEST_ADDRESS); nonce != 42 {
+		t.Errorf("Expected nonce 42, got %d", nonce)
+	}
+}
+
+func TestScriptedFailures(t *testing.T) {
+	server := zeratest.NewServer()
+	defer server.Close()
+
+	config := server.Config()
+	config.RetryBackoff = time.Millisecond
+
+	client, err := zera.NewClient(config)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer client.Close()
+
+	txn := coinTxn(t, server, "1")
+
+	// a transient failure is retried by the client
+	server.Fail(zeratest.Failure{Method: "Coin", Code: codes.Unavailable, Message: "validator overloaded"})
+
+	if _, err := client.Submit(context.Background(), txn); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if submissions := server.Submissions(); len(submissions) != 2 || submissions[0].Err == nil || submissions[1].Err != nil {
+		t.Fatalf("Expected a failed then an accepted submission, got %+v", submissions)
+	}
+
+	// a permanent failure is returned as a classified error
+	server.Fail(zeratest.Failure{Code: codes.FailedPrecondition, Message: "insufficient balance"})
+
+	_, err = client.Submit(context.Background(), txn)
+	if !errors.Is(err, zeraerr.ErrInsufficientBalance) {
+		t.Fatalf("Expected insufficient balance, got %v", err)
+	}
+
+	// indexer failures
+	server.Fail(zeratest.Failure{Method: "getContractGlance", Code: codes.Unavailable, Message: "maintenance", Times: -1})
+
+	for i := 0; i < 2; i++ {
+		if _, err := parts.GetParts(server.PartsInfo("$ZRA+0000")); !errors.Is(err, zeraerr.ErrTransient) {
+			t.Fatalf("Expected a transient error, got %v", err)
+		}
+	}
+
+	server.Reset()
+
+	partsPerCoin, err := parts.GetParts(server.PartsInfo("$ZRA+0000"))
+	if err != nil || partsPerCoin.Cmp(big.NewInt(1_000_000_000)) != 0 {
+		t.Fatalf("Expected 1000000000 parts, got %v (%v)", partsPerCoin, err)
+	}
+}
+
+func TestAmountVariants(t *testing.T) {
+	server := zeratest.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	partsInfo := server.PartsInfo("$ZRA+0000")
+	coins := func(value string) convert.Amount { return convert.MustParseAmount(value, big.NewInt(1_000_000_000)) }
+	recipient := "Hv3KUwrmR8C8XVSxuJFJrQqeDixeDnakUTkUUMZkFCUS"
+
+	nonceInfo, err := server.NonceInfo(TEST_ADDRESS)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// amounts at the parts per coin of the contract
+	signer := helper.NewPrivateKeySigner("r_"+TEST_PUBLIC, TEST_PRIVATE)
+	mintTxn, err := mint.CreateMintTxnWithAmount(ctx, nonceInfo, partsInfo, coins("2.5"), recipient, signer, "$ZRA+0000", "1000000")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mintTxn.Amount != "2500000000" || mintTxn.ContractId != "$ZRA+0000" {
+		t.Errorf("Expected 2500000000 parts of $ZRA+0000, got %s of %s", mintTxn.Amount, mintTxn.ContractId)
+	}
+
+	months := uint32(1)
+	allowanceTxn, err := allowance.CreateAllowanceTxnWithAmount(ctx, nonceInfo, partsInfo, allowance.AllowanceDetails{Authorize: true, WalletAddr: recipient, PeriodMonths: &months}, coins("1.5"), signer, "$ZRA+0000", "1000000")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if allowanceTxn.GetAllowedAmount() != "1500000000" {
+		t.Errorf("Expected 1500000000 parts allowed, got %s", allowanceTxn.GetAllowedAmount())
+	}
+
+	inputs := []transfer.Inputs{{B58Address: TEST_ADDRESS, Signer: helper.NewPrivateKeySigner(TEST_PUBLIC, TEST_PRIVATE), FeePercent: 100}}
+	coinTxn, err := transfer.CreateCoinTxnWithAmounts(ctx, nonceInfo, partsInfo, inputs, []convert.Amount{coins("1.5")}, map[string]convert.Amount{recipient: coins("1.5")}, "$ZRA+0000", "1000000", nil, nil, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if coinTxn.InputTransfers[0].Amount != "1500000000" || coinTxn.OutputTransfers[0].Amount != "1500000000" {
+		t.Errorf("Expected 1500000000 parts in and out, got %s and %s", coinTxn.InputTransfers[0].Amount, coinTxn.OutputTransfers[0].Amount)
+	}
+
+	// a decimal is not at the parts per coin of the contract, it is not minted, allowed or sent as its parts
+	ten := convert.MustParseDecimal("10")
+
+	if _, err := mint.CreateMintTxnWithAmount(ctx, nonceInfo, partsInfo, ten, recipient, signer, "$ZRA+0000", "1000000"); err == nil {
+		t.Errorf("Expected an error minting a decimal, got none")
+	}
+
+	if _, err := allowance.CreateAllowanceTxnWithAmount(ctx, nonceInfo, partsInfo, allowance.AllowanceDetails{Authorize: true, WalletAddr: recipient, PeriodMonths: &months}, ten, signer, "$ZRA+0000", "1000000"); err == nil {
+		t.Errorf("Expected an error allowing a decimal, got none")
+	}
+
+	if _, err := transfer.CreateCoinTxnWithAmounts(ctx, nonceInfo, partsInfo, inputs, []convert.Amount{ten}, map[string]convert.Amount{recipient: ten}, "$ZRA+0000", "1000000", nil, nil, 1); err == nil {
+		t.Errorf("Expected an error sending a decimal, got none")
+	}
+}